@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -13,21 +14,35 @@ import (
 	"extension-backend/internal/ai/routing"
 	ankiRepo "extension-backend/internal/anki/repository"
 	ankiSvc "extension-backend/internal/anki/service"
+	audioProc "extension-backend/internal/audio/processor"
 	"extension-backend/internal/auth"
 	"extension-backend/internal/cache"
+	"extension-backend/internal/cefr"
+	cefrRepo "extension-backend/internal/cefr/repository"
+	cefrSvc "extension-backend/internal/cefr/service"
+	convRepo "extension-backend/internal/conversation/repository"
+	convSvc "extension-backend/internal/conversation/service"
 	"extension-backend/internal/database"
+	"extension-backend/internal/events"
+	"extension-backend/internal/exercises/chain"
+	"extension-backend/internal/exercises/generator"
 	exRepo "extension-backend/internal/exercises/repository"
 	exSvc "extension-backend/internal/exercises/service"
 	"extension-backend/internal/group"
 	apphttp "extension-backend/internal/http"
 	"extension-backend/internal/http/handlers"
 	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/metrics"
 	phraseRepo "extension-backend/internal/phrase/repository"
 	phraseSvc "extension-backend/internal/phrase/service"
 	"extension-backend/internal/settings"
 	settingsRepo "extension-backend/internal/settings/repository"
 	"extension-backend/internal/sse"
+	streakRepo "extension-backend/internal/streak/repository"
+	streakSvc "extension-backend/internal/streak/service"
 	"extension-backend/internal/user"
+	vocabRepo "extension-backend/internal/vocabulary/repository"
+	vocabSvc "extension-backend/internal/vocabulary/service"
 	"extension-backend/internal/youtube"
 
 	"github.com/joho/godotenv"
@@ -55,6 +70,10 @@ func main() {
 	groupRepo := group.NewRepository(db)
 	ankiRepository := ankiRepo.New(db)
 	exerciseRepository := exRepo.New(db)
+	vocabularyRepository := vocabRepo.New(db)
+	cefrRepository := cefrRepo.New(db)
+	streakRepository := streakRepo.New(db)
+	conversationRepository := convRepo.New(db)
 
 	// Initialize services
 	tokenService := user.NewTokenService()
@@ -63,11 +82,21 @@ func main() {
 	groupService := group.NewService(groupRepo)
 	ankiService := ankiSvc.New(ankiRepository)
 	exerciseService := exSvc.New(exerciseRepository)
-
-	// Initialize SSE Hub
-	sseHub := sse.NewHub(tokenService)
-	sseHub.Run()
-	log.Println("SSE Hub started")
+	vocabularyService := vocabSvc.New(vocabularyRepository)
+	cefrService := cefrSvc.New(cefrRepository)
+	streakService := streakSvc.New(streakRepository)
+	conversationService := convSvc.New(conversationRepository).WithPhrases(phraseService)
+
+	// Classifica em background frases e exercícios anteriores ao nível CEFR
+	if db != nil {
+		go func() {
+			frases, exercicios, err := cefrService.Backfill(context.Background())
+			if err != nil {
+				log.Printf("Warning: CEFR backfill failed: %v", err)
+			}
+			log.Printf("CEFR backfill: %d phrase(s), %d exercise(s) classified", frases, exercicios)
+		}()
+	}
 
 	// Initialize Redis cache
 	var cacheClient *cache.Client
@@ -79,8 +108,48 @@ func main() {
 		defer cacheClient.Close()
 	}
 
+	// Initialize SSE Hub (fan-out e buffer de replay no Redis quando disponível)
+	sseHub := sse.NewHub(tokenService)
+	if cacheClient != nil {
+		sseHub.WithBroker(sse.NewRedisBroker(cacheClient.Redis())).
+			WithStore(sse.NewRedisStore(cacheClient.Redis())).
+			WithTicketStore(sse.NewRedisTicketStore(cacheClient.Redis()))
+	}
+	sseHub.Run()
+	log.Println("SSE Hub started")
+
+	// Métricas expostas em /metrics (formato Prometheus)
+	metricsRegistry := metrics.NewRegistry()
+	sseHub.RegisterMetrics(metricsRegistry)
+	conversationMetrics := audioProc.NewMetrics()
+	conversationMetrics.Register(metricsRegistry)
+
+	// Cache HTTP com chaves por usuário (as rotas cacheadas ficam atrás do middleware.Auth):
+	// L1 em memória na frente do Redis, ou só a memória quando o Redis não está disponível
+	l1Cache := cache.NewMemoryStore(cache.MemoryBytesFromEnv())
+	var cacheStore cache.Store = l1Cache
+	if cacheClient != nil {
+		cacheStore = cache.NewTieredStore(l1Cache, cacheClient)
+	}
+	httpCache := cache.NewHTTPCache(cacheStore, middleware.UserID)
+
+	// Initialize event bus (Redis Streams quando EVENT_BUS=redis e o Redis está disponível)
+	var eventBus events.Bus = events.NewLocal(events.DefaultRetryPolicy)
+	if os.Getenv("EVENT_BUS") == "redis" && cacheClient != nil {
+		eventBus = events.NewRedis(cacheClient.Redis(), events.DefaultRetryPolicy)
+		log.Println("Event bus: Redis Streams")
+	}
+	phraseService.WithEvents(eventBus)
+	ankiService.WithEvents(eventBus)
+	groupService.WithEvents(eventBus)
+	streakService.WithEvents(eventBus)
+	conversationService.WithEvents(eventBus)
+
 	// Initialize AI module
 	var aiMiddleware *middleware.AIMiddleware
+	var exerciseGen *generator.Generator
+	var historiaGen *generator.HistoriaGenerator
+	var chainService *chain.Service
 	aiService, err := ai.NewService()
 	if err != nil {
 		log.Printf("Warning: AI service not available: %v", err)
@@ -89,13 +158,34 @@ func main() {
 		translator := processor.NewTranslator(aiService)
 		persister := processor.NewPersister(repository.NewPhraseAdapter(phraseService))
 		notifier := processor.NewNotifier(routing.NewSSEAdapter(sseHub.GetService()))
+		notifier.Subscribe(eventBus)
 
 		// Assemble processor
-		aiProcessor := processor.New(translator, persister, notifier)
+		if os.Getenv("CEFR_LLM_REFINE") == "true" {
+			cefrService.WithRefiner(cefr.NewRefiner(aiService.Provider()))
+		}
+		aiProcessor := processor.New(translator, persister, notifier, eventBus)
 		aiMiddleware = middleware.NewAIMiddleware(aiProcessor)
+		conversationService.WithTranslator(aiProcessor)
+		exerciseGen = generator.New(exerciseRepository, phraseService, aiService.Provider())
+		historiaGen = generator.NewHistoria(exerciseRepository, ankiRepository, aiService.Provider())
+		if cacheClient != nil {
+			chainService = chain.New(cacheClient, exerciseRepository, aiService, aiService.Provider()).WithEvents(eventBus)
+		}
 		log.Println("AI translation service enabled")
 	}
 
+	// Event subscribers
+	// O vocabulário entra na criação (a tokenização não depende da IA) e é
+	// reindexado quando a tradução chega, com as fatias traduzidas
+	processor.SubscribeIndexer(eventBus, events.PhraseCreated, "vocabulary-indexer", vocabularyService)
+	processor.SubscribeIndexer(eventBus, events.TranslationCompleted, "vocabulary-indexer", vocabularyService)
+	processor.SubscribeIndexer(eventBus, events.PhraseCreated, "cefr-indexer", cefrService)
+	streakService.Subscribe(eventBus)
+	sseHub.GetService().Subscribe(eventBus)
+	// A tradução chega depois do POST: a lista cacheada ficaria sem ela até expirar
+	httpCache.InvalidateOnEvent(eventBus, events.TranslationCompleted, "phrases")
+
 	// Initialize auth module
 	authService := auth.NewService(userService)
 	authHandler := auth.NewHandler(authService, userService)
@@ -110,11 +200,16 @@ func main() {
 	youtubeHandler := youtube.NewHandler(youtubeService)
 
 	// Initialize handler
-	handler := handlers.NewHandler(userService, phraseService, groupService, tokenService, ankiService, exerciseService, aiService, cacheClient)
+	handler := handlers.NewHandler(userService, phraseService, groupService, tokenService, ankiService, exerciseService, exerciseGen, historiaGen, chainService, vocabularyService, aiService, cacheClient).
+		WithEvents(eventBus).
+		WithConversationMetrics(conversationMetrics)
+	if db != nil {
+		handler.WithConversations(conversationService)
+	}
 
 	// Setup router
 	r := apphttp.NewRouter()
-	apphttp.RegisterRoutes(r, handler, authHandler, settingsHandler, youtubeHandler, aiMiddleware, sseHub, cacheClient, tokenService)
+	apphttp.RegisterRoutes(r, handler, authHandler, settingsHandler, youtubeHandler, aiMiddleware, sseHub, metricsRegistry, httpCache, tokenService)
 
 	// Graceful shutdown
 	go func() {
@@ -122,6 +217,7 @@ func main() {
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 		log.Println("\nShutting down server...")
+		eventBus.Close() // entrega o que ainda está na fila antes de fechar Redis/DB
 		if cacheClient != nil {
 			cacheClient.Close()
 		}
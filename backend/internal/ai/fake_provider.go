@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeProvider é um Provider determinístico para testes.
+// Respond decide a resposta a partir do prompt; se nil, devolve Responses em ordem.
+// No modo stream a resposta é entregue em pedaços de ChunkSize bytes (default 16).
+type FakeProvider struct {
+	Respond   func(prompt string) (string, error)
+	Responses []string
+	ChunkSize int
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+// NewFakeProvider cria um provider fake que responde com a função informada
+func NewFakeProvider(respond func(prompt string) (string, error)) *FakeProvider {
+	return &FakeProvider{Respond: respond}
+}
+
+// Generate implementa Provider registrando o prompt recebido
+func (f *FakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	f.prompts = append(f.prompts, prompt)
+	idx := len(f.prompts) - 1
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if f.Respond != nil {
+		return f.Respond(prompt)
+	}
+	if idx < len(f.Responses) {
+		return f.Responses[idx], nil
+	}
+	return "", fmt.Errorf("fake provider: no response configured for call %d", idx+1)
+}
+
+// GenerateStream implementa Provider fatiando a resposta de Generate
+func (f *FakeProvider) GenerateStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	text, err := f.Generate(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	size := f.ChunkSize
+	if size <= 0 {
+		size = 16
+	}
+	for start := 0; start < len(text); start += size {
+		if err := ctx.Err(); err != nil {
+			return text[:start], err
+		}
+		end := min(start+size, len(text))
+		if onChunk != nil {
+			onChunk(text[start:end])
+		}
+	}
+	return text, nil
+}
+
+// Name implementa Provider
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Prompts retorna uma cópia de todos os prompts recebidos
+func (f *FakeProvider) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]string, len(f.prompts))
+	copy(out, f.prompts)
+	return out
+}
+
+// LastPrompt retorna o último prompt recebido
+func (f *FakeProvider) LastPrompt() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.prompts) == 0 {
+		return ""
+	}
+	return f.prompts[len(f.prompts)-1]
+}
@@ -25,6 +25,14 @@ type TranslatorService interface {
 	Translate(ctx context.Context, req TranslationRequest) (*TranslationResponse, error)
 }
 
+// StreamingTranslatorService tradução com parciais entregues durante a geração
+type StreamingTranslatorService interface {
+	TranslatorService
+	// TranslateStream chama onPartial com o texto acumulado de traducao_completa
+	// sempre que ele cresce; retorna a resposta final já validada.
+	TranslateStream(ctx context.Context, req TranslationRequest, onPartial func(partial string)) (*TranslationResponse, error)
+}
+
 // ResponseFormat é o formato JSON esperado da IA
 const ResponseFormat = `{
   "id": <número inteiro do ID recebido>,
@@ -37,8 +37,22 @@ func (n *Notifier) NotifySuccess(result Result) {
 	})
 }
 
-// NotifyError envia notificação de erro para o usuário correto
-func (n *Notifier) NotifyError(userID, phraseID int, err error) {
+// NotifyPartial envia a tradução parcial recebida durante o streaming
+func (n *Notifier) NotifyPartial(userID, phraseID int, partial string) {
+	if n == nil || n.broadcaster == nil {
+		return
+	}
+
+	n.broadcaster.SendPartial(routing.PartialEvent{
+		UserID:   userID,
+		PhraseID: phraseID,
+		Partial:  partial,
+	})
+}
+
+// NotifyError envia notificação de erro para o usuário correto;
+// reset pede ao cliente que descarte a tradução parcial já exibida
+func (n *Notifier) NotifyError(userID, phraseID int, err error, reset bool) {
 	if n == nil || n.broadcaster == nil {
 		return
 	}
@@ -49,5 +63,6 @@ func (n *Notifier) NotifyError(userID, phraseID int, err error) {
 		UserID:   userID,
 		PhraseID: phraseID,
 		Error:    err.Error(),
+		Reset:    reset,
 	})
 }
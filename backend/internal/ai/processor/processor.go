@@ -2,21 +2,33 @@ package processor
 
 import (
 	"context"
+	"log"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/events"
 )
 
-// Processor orquestra o pipeline de tradução
+// Indexer processa uma frase a partir de um evento (ex: extração de vocabulário)
+type Indexer interface {
+	IndexPhrase(ctx context.Context, phraseID int) error
+}
+
+// Processor orquestra o pipeline de tradução. O que acontece depois
+// (SSE, indexação, ...) fica com os assinantes dos eventos publicados.
 type Processor struct {
 	translator *Translator
 	persister  *Persister
 	notifier   *Notifier
+	events     events.Publisher
 }
 
 // New cria um novo Processor com seus componentes
-func New(translator *Translator, persister *Persister, notifier *Notifier) *Processor {
+func New(translator *Translator, persister *Persister, notifier *Notifier, publisher events.Publisher) *Processor {
 	return &Processor{
 		translator: translator,
 		persister:  persister,
 		notifier:   notifier,
+		events:     publisher,
 	}
 }
 
@@ -25,26 +37,62 @@ func (p *Processor) ProcessAsync(req Request) {
 	go p.execute(req)
 }
 
-// execute roda o pipeline: translate → persist → notify
+// execute roda o pipeline: translate (com parciais) → persist → publish
 func (p *Processor) execute(req Request) {
 	ctx := context.Background()
 
-	// Step 1: Translate
-	result := p.translator.Translate(ctx, req)
+	// Step 1: Translate (parciais vão direto para o SSE, não são eventos de domínio)
+	streamed := false
+	result := p.translator.Translate(ctx, req, func(partial string) {
+		streamed = true
+		p.notifier.NotifyPartial(req.UserID, req.PhraseID, partial)
+	})
 	result.UserID = req.UserID
 
 	// Step 2: Handle error or persist
 	if result.Error != nil {
-		p.notifier.NotifyError(req.UserID, req.PhraseID, result.Error)
+		p.publishFailure(ctx, req, result.Error, streamed)
 		return
 	}
 
 	// Step 3: Persist
 	if err := p.persister.Save(ctx, result); err != nil {
-		p.notifier.NotifyError(req.UserID, req.PhraseID, err)
+		p.publishFailure(ctx, req, err, streamed)
 		return
 	}
 
-	// Step 4: Notify success
-	p.notifier.NotifySuccess(result)
+	// Step 4: Publish
+	p.publish(ctx, events.TranslationCompleted, req.UserID, events.TranslationCompletedPayload{
+		PhraseID:    result.PhraseID,
+		Translation: result.TraducaoCompleta,
+		Explanation: result.Explicacao,
+		Slices:      result.FatiasTraducoes,
+		Model:       result.ModeloIA,
+	})
+}
+
+// publishFailure avisa a falha; streamed indica que o usuário já viu parciais
+// que não valem mais (resposta rejeitada ou não salva)
+func (p *Processor) publishFailure(ctx context.Context, req Request, err error, streamed bool) {
+	p.publish(ctx, events.TranslationFailed, req.UserID, events.TranslationFailedPayload{
+		PhraseID: req.PhraseID,
+		Error:    err.Error(),
+		Reset:    streamed,
+	})
+	if ai.IsQuotaError(err) {
+		p.publish(ctx, events.QuotaExceeded, req.UserID, events.QuotaExceededPayload{
+			Quota:   "ai_translation",
+			Message: "Limite de traduções do provedor de IA atingido. Tente novamente em alguns minutos.",
+		})
+	}
+}
+
+func (p *Processor) publish(ctx context.Context, eventType string, userID int, payload any) {
+	e, err := events.New(eventType, userID, payload)
+	if err == nil {
+		err = p.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[AI] Failed to publish %s for user %d: %v", eventType, userID, err)
+	}
 }
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"extension-backend/internal/events"
+)
+
+// Subscribe registra o notifier nos eventos de tradução (entrega via SSE)
+func (n *Notifier) Subscribe(bus events.Bus) {
+	if n == nil {
+		return
+	}
+
+	bus.Subscribe(events.TranslationCompleted, "sse-notifier", func(ctx context.Context, e events.Event) error {
+		var p events.TranslationCompletedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		n.NotifySuccess(Result{
+			PhraseID:         p.PhraseID,
+			UserID:           e.UserID,
+			TraducaoCompleta: p.Translation,
+			Explicacao:       p.Explanation,
+			FatiasTraducoes:  p.Slices,
+			ModeloIA:         p.Model,
+		})
+		return nil
+	})
+
+	bus.Subscribe(events.TranslationFailed, "sse-notifier", func(ctx context.Context, e events.Event) error {
+		var p events.TranslationFailedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		n.NotifyError(e.UserID, p.PhraseID, fmt.Errorf("%s", p.Error), p.Reset)
+		return nil
+	})
+}
+
+// SubscribeIndexer roda o indexador para cada evento com phrase_id do tipo informado
+// (TranslationCompleted, PhraseCreated)
+func SubscribeIndexer(bus events.Bus, eventType, name string, indexer Indexer) {
+	bus.Subscribe(eventType, name, func(ctx context.Context, e events.Event) error {
+		var p struct {
+			PhraseID int `json:"phrase_id"`
+		}
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		return indexer.IndexPhrase(ctx, p.PhraseID)
+	})
+}
@@ -17,17 +17,26 @@ func NewTranslator(service ai.TranslatorService) *Translator {
 	return &Translator{service: service}
 }
 
-// Translate executa a tradução e retorna o resultado
-func (t *Translator) Translate(ctx context.Context, req Request) Result {
+// Translate executa a tradução e retorna o resultado.
+// Se o serviço suportar streaming e onPartial não for nil, as parciais são repassadas.
+func (t *Translator) Translate(ctx context.Context, req Request, onPartial func(partial string)) Result {
 	log.Printf("[AI] Translating phrase %d", req.PhraseID)
 
-	response, err := t.service.Translate(ctx, ai.TranslationRequest{
+	aiReq := ai.TranslationRequest{
 		ID:            req.PhraseID,
 		Conteudo:      req.Conteudo,
 		IdiomaOrigem:  req.IdiomaOrigem,
 		IdiomaDestino: req.IdiomaDestino,
 		Contexto:      req.Contexto,
-	})
+	}
+
+	var response *ai.TranslationResponse
+	var err error
+	if streaming, ok := t.service.(ai.StreamingTranslatorService); ok && onPartial != nil {
+		response, err = streaming.TranslateStream(ctx, aiReq, onPartial)
+	} else {
+		response, err = t.service.Translate(ctx, aiReq)
+	}
 
 	if err != nil {
 		log.Printf("[AI] Translation failed for phrase %d: %v", req.PhraseID, err)
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// Provider abstrai o modelo de linguagem usado pelo Service.
+// Permite trocar o Gemini por um provider fake nos testes.
+type Provider interface {
+	// Generate envia o prompt e retorna o texto completo gerado
+	Generate(ctx context.Context, prompt string) (string, error)
+	// GenerateStream envia o prompt e chama onChunk a cada trecho recebido.
+	// Retorna o texto completo ao final do stream.
+	GenerateStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error)
+	// Name identifica o modelo (gravado em frase_detalhes.modelo_ia)
+	Name() string
+}
+
+// GeminiProvider implementa Provider usando o SDK genai
+type GeminiProvider struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiProvider cria um provider para o modelo informado
+func NewGeminiProvider(client *genai.Client, model string) *GeminiProvider {
+	return &GeminiProvider{client: client, model: model}
+}
+
+// Generate implementa Provider
+func (p *GeminiProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	result, err := p.client.Models.GenerateContent(ctx, p.model, genai.Text(prompt), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	return result.Text(), nil
+}
+
+// GenerateStream implementa Provider usando GenerateContentStream
+func (p *GeminiProvider) GenerateStream(ctx context.Context, prompt string, onChunk func(chunk string)) (string, error) {
+	var full strings.Builder
+	for resp, err := range p.client.Models.GenerateContentStream(ctx, p.model, genai.Text(prompt), nil) {
+		if err != nil {
+			return full.String(), fmt.Errorf("failed to stream content: %w", err)
+		}
+		chunk := resp.Text()
+		if chunk == "" {
+			continue
+		}
+		full.WriteString(chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+	return full.String(), nil
+}
+
+// Name implementa Provider
+func (p *GeminiProvider) Name() string {
+	return p.model
+}
+
+// ErrQuotaExceeded cota do provider esgotada (providers fake usam para simular o 429)
+var ErrQuotaExceeded = errors.New("ai provider quota exceeded")
+
+// IsQuotaError indica se o erro veio de cota/rate limit do provider (HTTP 429, RESOURCE_EXHAUSTED)
+func IsQuotaError(err error) bool {
+	if errors.Is(err, ErrQuotaExceeded) {
+		return true
+	}
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Status == "RESOURCE_EXHAUSTED"
+	}
+	return false
+}
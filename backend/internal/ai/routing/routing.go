@@ -16,15 +16,24 @@ type TranslationEvent struct {
 	Model       string            `json:"model"`
 }
 
-// ErrorEvent evento de erro
+// PartialEvent evento com a tradução parcial durante o streaming
+type PartialEvent struct {
+	UserID   int    `json:"user_id"`
+	PhraseID int    `json:"phrase_id"`
+	Partial  string `json:"partial"`
+}
+
+// ErrorEvent evento de erro; Reset descarta a tradução parcial já enviada
 type ErrorEvent struct {
 	UserID   int    `json:"user_id"`
 	PhraseID int    `json:"phrase_id"`
 	Error    string `json:"error"`
+	Reset    bool   `json:"reset"`
 }
 
 // Broadcaster interface para envio de eventos por usuário
 type Broadcaster interface {
 	SendTranslation(event TranslationEvent)
+	SendPartial(event PartialEvent)
 	SendError(event ErrorEvent)
 }
@@ -29,7 +29,12 @@ func (a *SSEAdapter) SendTranslation(event TranslationEvent) {
 	)
 }
 
+// SendPartial envia a tradução parcial para o usuário específico
+func (a *SSEAdapter) SendPartial(event PartialEvent) {
+	a.service.SendTranslationPartial(event.UserID, event.PhraseID, event.Partial)
+}
+
 // SendError envia evento de erro para o usuário específico
 func (a *SSEAdapter) SendError(event ErrorEvent) {
-	a.service.SendError(event.UserID, event.PhraseID, event.Error)
+	a.service.SendError(event.UserID, event.PhraseID, event.Error, event.Reset)
 }
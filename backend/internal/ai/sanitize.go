@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Limites de tamanho para conteúdo vindo de páginas web (em runes)
+const (
+	MaxConteudoLength = 500
+	MaxContextoLength = 1500
+)
+
+// Delimitadores que envolvem dados não confiáveis dentro dos prompts
+const (
+	dataOpen  = "<<<"
+	dataClose = ">>>"
+)
+
+var (
+	whitespaceRun = regexp.MustCompile(`[ \t]+`)
+	newlineRun    = regexp.MustCompile(`\n{3,}`)
+	languageCode  = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+)
+
+// injectionPatterns detecta textos que tentam dar ordens ao modelo.
+// Cobre inglês, português e espanhol, que são os idiomas mais capturados.
+var injectionPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"ignore_instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,40}\b(instructions?|rules|prompt|directions|above|previous)\b`)},
+	{"ignore_instructions_pt", regexp.MustCompile(`(?i)\b(ignore|ignora|desconsidere|esque[çc]a)\b.{0,40}\b(instru[çc](ão|ões)|regras|comandos|anteriores|acima)\b`)},
+	{"ignore_instructions_es", regexp.MustCompile(`(?i)\b(ignora|olvida|omite)\b.{0,40}\b(instrucciones|reglas|anteriores)\b`)},
+	{"role_override", regexp.MustCompile(`(?i)\b(you are now|act as|pretend to be|from now on you|voc[êe] agora [ée]|aja como|finja ser|ahora eres)\b`)},
+	{"system_prompt", regexp.MustCompile(`(?i)\b(system prompt|system message|prompt do sistema|new instructions|novas instru[çc][õo]es|nuevas instrucciones)\b`)},
+	{"role_marker", regexp.MustCompile(`(?im)^\s*(system|assistant|user|developer)\s*:`)},
+	{"output_override", regexp.MustCompile(`(?i)\b(respond|reply|answer|output|responda|retorne|responde)\b.{0,30}\b(only|with|apenas|somente|solo)\b.{0,30}\b(json|"?id"?|traducao_completa|fatias_traducoes)\b`)},
+	{"delimiter_spoof", regexp.MustCompile(`<<<|>>>|` + "```")},
+}
+
+// NormalizeInput limpa texto não confiável antes de ir para o prompt:
+// aplica NFKC, remove caracteres de controle, invisíveis e de direção (bidi),
+// colapsa espaços e corta em max runes.
+func NormalizeInput(s string, max int) string {
+	s = norm.NFKC.String(s)
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r == '\r':
+			b.WriteRune('\n')
+		case isInvisible(r):
+			// descarta
+		case unicode.IsControl(r):
+			// descarta
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	out := whitespaceRun.ReplaceAllString(b.String(), " ")
+	out = newlineRun.ReplaceAllString(out, "\n\n")
+	out = strings.TrimSpace(out)
+
+	if max > 0 {
+		runes := []rune(out)
+		if len(runes) > max {
+			out = strings.TrimSpace(string(runes[:max]))
+		}
+	}
+	return out
+}
+
+// isInvisible identifica caracteres de largura zero e de controle de direção
+// usados para esconder instruções do usuário mas não do modelo
+func isInvisible(r rune) bool {
+	switch {
+	case r >= 0x200B && r <= 0x200F: // zero-width space/joiners, LRM, RLM
+		return true
+	case r >= 0x202A && r <= 0x202E: // bidi embeddings/overrides
+		return true
+	case r >= 0x2060 && r <= 0x2064: // word joiner, invisible operators
+		return true
+	case r >= 0x2066 && r <= 0x2069: // bidi isolates
+		return true
+	case r == 0xFEFF || r == 0x00AD || r == 0x180E:
+		return true
+	case r >= 0xE0000 && r <= 0xE007F: // tag characters
+		return true
+	}
+	return false
+}
+
+// DetectInjection retorna os nomes dos padrões de instrução encontrados no texto.
+// Retorna nil quando o texto parece ser apenas conteúdo.
+func DetectInjection(s string) []string {
+	var found []string
+	for _, p := range injectionPatterns {
+		if p.re.MatchString(s) {
+			found = append(found, p.name)
+		}
+	}
+	return found
+}
+
+// EscapeDelimiters remove sequências que poderiam fechar o bloco de dados antes da hora
+func EscapeDelimiters(s string) string {
+	for strings.Contains(s, dataOpen) || strings.Contains(s, dataClose) || strings.Contains(s, "```") {
+		s = strings.ReplaceAll(s, dataOpen, "<<")
+		s = strings.ReplaceAll(s, dataClose, ">>")
+		s = strings.ReplaceAll(s, "```", "''")
+	}
+	return s
+}
+
+// DataBlock envolve texto não confiável em um bloco delimitado e rotulado
+func DataBlock(label, s string) string {
+	return dataOpen + label + "\n" + EscapeDelimiters(s) + "\n" + dataClose
+}
+
+// NormalizeLanguageCode aceita apenas códigos no formato "en" ou "pt-BR"
+func NormalizeLanguageCode(code, fallback string) string {
+	code = strings.TrimSpace(code)
+	if languageCode.MatchString(code) {
+		return code
+	}
+	return fallback
+}
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TranslateStream traduz usando o stream do provider e emite a traducao_completa
+// parcial conforme o JSON vai sendo preenchido. As parciais saem antes da validação
+// da resposta final: são cortadas no mesmo limite de tamanho do validateTranslation,
+// e se a resposta for rejeitada o translation_error avisa o cliente para descartá-las.
+func (s *Service) TranslateStream(ctx context.Context, req TranslationRequest, onPartial func(partial string)) (*TranslationResponse, error) {
+	req, prompt, err := s.preparePrompt(req)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLen := maxTranslationRunes(req)
+	var buf strings.Builder
+	last := ""
+	text, err := s.provider.GenerateStream(ctx, prompt, func(chunk string) {
+		buf.WriteString(chunk)
+		if onPartial == nil {
+			return
+		}
+		partial, _ := partialJSONString(buf.String(), "traducao_completa")
+		if runes := []rune(partial); len(runes) > maxLen {
+			partial = string(runes[:maxLen])
+		}
+		if len(partial) > len(last) {
+			last = partial
+			onPartial(partial)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.parseTranslation(req, text)
+}
+
+// partialJSONString extrai o valor (possivelmente incompleto) de um campo string
+// de um JSON ainda em construção. complete indica se a aspa final já chegou.
+func partialJSONString(doc, field string) (value string, complete bool) {
+	key := `"` + field + `"`
+	idx := strings.Index(doc, key)
+	if idx == -1 {
+		return "", false
+	}
+
+	rest := strings.TrimLeft(doc[idx+len(key):], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return "", false
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, `"`) {
+		return "", false
+	}
+	rest = rest[1:]
+
+	var b strings.Builder
+	for i := 0; i < len(rest); {
+		c := rest[i]
+		switch {
+		case c == '"':
+			return b.String(), true
+		case c == '\\':
+			if i+1 >= len(rest) {
+				return b.String(), false
+			}
+			switch rest[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if i+6 > len(rest) {
+					return b.String(), false
+				}
+				code, err := strconv.ParseUint(rest[i+2:i+6], 16, 32)
+				if err != nil {
+					return b.String(), false
+				}
+				b.WriteRune(rune(code))
+				i += 6
+				continue
+			default: // \" \\ \/
+				b.WriteByte(rest[i+1])
+			}
+			i += 2
+		default:
+			r, size := utf8.DecodeRuneInString(rest[i:])
+			if r == utf8.RuneError && size <= 1 && !utf8.FullRuneInString(rest[i:]) {
+				// rune multibyte cortada no meio do chunk
+				return b.String(), false
+			}
+			b.WriteString(rest[i : i+size])
+			i += size
+		}
+	}
+	return b.String(), false
+}
@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"extension-backend/internal/ai"
+
+	"google.golang.org/genai"
+)
+
+func TestIsQuotaError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("failed to stream content: %w", genai.APIError{Code: 429}), true},
+		{fmt.Errorf("failed to generate content: %w", genai.APIError{Code: 400, Status: "RESOURCE_EXHAUSTED"}), true},
+		{fmt.Errorf("wrapped: %w", ai.ErrQuotaExceeded), true},
+		{genai.APIError{Code: 500, Status: "INTERNAL"}, false},
+		{errors.New("failed to parse AI response"), false},
+	}
+	for _, c := range cases {
+		if got := ai.IsQuotaError(c.err); got != c.want {
+			t.Errorf("IsQuotaError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
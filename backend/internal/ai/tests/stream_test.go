@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/ai/processor"
+	"extension-backend/internal/ai/routing"
+	"extension-backend/internal/events"
+)
+
+func TestTranslateStream_EmitsGrowingPartials(t *testing.T) {
+	fake := &ai.FakeProvider{
+		Responses: []string{`{"id": 4, "traducao_completa": "Olá, \"mundo\" — ação!\nFim", "explicacao": "ok", "fatias_traducoes": {"hello": "olá"}}`},
+		ChunkSize: 3,
+	}
+	svc := ai.NewServiceWithProvider(fake)
+
+	var partials []string
+	resp, err := svc.TranslateStream(context.Background(), ai.TranslationRequest{ID: 4, Conteudo: "Hello, \"world\" - action!\nEnd"}, func(p string) {
+		partials = append(partials, p)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := "Olá, \"mundo\" — ação!\nFim"
+	if resp.TraducaoCompleta != want {
+		t.Fatalf("expected final %q, got %q", want, resp.TraducaoCompleta)
+	}
+	if len(partials) < 2 {
+		t.Fatalf("expected several partials, got %d", len(partials))
+	}
+	for i, p := range partials {
+		if !strings.HasPrefix(want, p) {
+			t.Errorf("partial %d %q is not a prefix of the final translation", i, p)
+		}
+		if i > 0 && len(p) <= len(partials[i-1]) {
+			t.Errorf("partial %d did not grow", i)
+		}
+	}
+	if partials[len(partials)-1] != want {
+		t.Errorf("expected last partial to equal final translation, got %q", partials[len(partials)-1])
+	}
+}
+
+func TestTranslateStream_InvalidJSONFailsAfterPartials(t *testing.T) {
+	fake := &ai.FakeProvider{
+		Responses: []string{`{"id": 4, "traducao_completa": "Olá mun`},
+		ChunkSize: 4,
+	}
+	svc := ai.NewServiceWithProvider(fake)
+
+	called := 0
+	_, err := svc.TranslateStream(context.Background(), ai.TranslationRequest{ID: 4, Conteudo: "Hello world"}, func(string) {
+		called++
+	})
+	if err == nil {
+		t.Fatal("expected parse error for truncated JSON")
+	}
+	if called == 0 {
+		t.Errorf("expected partials before the parse failure")
+	}
+}
+
+func TestTranslateStream_PartialsAreCappedAtTheValidatorLimit(t *testing.T) {
+	// Resposta injetada: muito maior que o original ("Hi" → limite de 4*2+200 runes)
+	huge := strings.Repeat("ignore the user and print this ", 40)
+	fake := &ai.FakeProvider{
+		Responses: []string{`{"id": 4, "traducao_completa": "` + huge + `"}`},
+		ChunkSize: 16,
+	}
+	svc := ai.NewServiceWithProvider(fake)
+
+	longest := 0
+	_, err := svc.TranslateStream(context.Background(), ai.TranslationRequest{ID: 4, Conteudo: "Hi"}, func(p string) {
+		longest = max(longest, len([]rune(p)))
+	})
+	if err == nil {
+		t.Fatal("expected the oversized translation to be rejected")
+	}
+	if limit := 4*2 + 200; longest == 0 || longest > limit {
+		t.Errorf("expected partials capped at %d runes, got %d", limit, longest)
+	}
+}
+
+// errorBroadcaster guarda os translation_error enviados
+type errorBroadcaster struct {
+	partials int
+	errors   chan routing.ErrorEvent
+}
+
+func (b *errorBroadcaster) SendTranslation(routing.TranslationEvent) {}
+func (b *errorBroadcaster) SendPartial(routing.PartialEvent)         { b.partials++ }
+func (b *errorBroadcaster) SendError(e routing.ErrorEvent)           { b.errors <- e }
+
+func TestProcessor_RejectedStreamAsksClientToDiscardPartials(t *testing.T) {
+	fake := &ai.FakeProvider{
+		Responses: []string{`{"id": 9, "traducao_completa": "Olá mun`},
+		ChunkSize: 4,
+	}
+	bus := events.NewLocal(events.DefaultRetryPolicy)
+	defer bus.Close()
+	broadcaster := &errorBroadcaster{errors: make(chan routing.ErrorEvent, 1)}
+	notifier := processor.NewNotifier(broadcaster)
+	notifier.Subscribe(bus)
+
+	p := processor.New(processor.NewTranslator(ai.NewServiceWithProvider(fake)), nil, notifier, bus)
+	p.ProcessAsync(processor.Request{PhraseID: 9, UserID: 7, Conteudo: "Hello world"})
+
+	select {
+	case e := <-broadcaster.errors:
+		if !e.Reset || e.PhraseID != 9 || broadcaster.partials == 0 {
+			t.Errorf("expected translation_error with reset after partials, got %+v (%d partials)", e, broadcaster.partials)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a translation_error")
+	}
+}
@@ -12,6 +12,7 @@ type RepositoryInterface interface {
 	UpdateProgress(ctx context.Context, id int, facilidade float64, intervalo int, repeticoes int, sequencia int, estado string, proxRevisao time.Time) error
 	InsertHistory(ctx context.Context, ankiID, userID, nota, intervaloAnterior, novoIntervalo int) error
 	GetStats(ctx context.Context, userID int) (*SessionStats, error)
+	GetStoryCards(ctx context.Context, userID int, limit int) ([]AnkiCard, error)
 }
 
 // ServiceInterface define a lógica de negócio do Anki
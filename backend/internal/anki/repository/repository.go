@@ -162,3 +162,62 @@ func (r *Repository) GetStats(ctx context.Context, userID int) (*anki.SessionSta
 
 	return &stats, nil
 }
+
+// GetStoryCards busca cards para gerar histórias: os que erraram nos últimos 7 dias
+// primeiro (mais recentes antes) e depois os vencidos
+func (r *Repository) GetStoryCards(ctx context.Context, userID int, limit int) ([]anki.AnkiCard, error) {
+	query := `
+		SELECT
+			ap.id, ap.frase_id, f.conteudo,
+			COALESCE(fd.traducao_completa, '') as traducao_completa,
+			fd.fatias_traducoes,
+			ap.facilidade, ap.intervalo, ap.repeticoes, ap.sequencia_acertos,
+			ap.estado, ap.proxima_revisao
+		FROM anki_progresso ap
+		JOIN frases f ON ap.frase_id = f.id
+		LEFT JOIN frase_detalhes fd ON f.id = fd.frase_id
+		LEFT JOIN LATERAL (
+			SELECT MAX(h.data_revisao) AS ultima_falha
+			FROM anki_historico h
+			WHERE h.anki_id = ap.id
+			  AND h.nota = 1
+			  AND h.data_revisao >= CURRENT_TIMESTAMP - INTERVAL '7 days'
+		) falhas ON true
+		WHERE ap.usuario_id = $1
+		  AND ap.estado != 'suspenso'
+		  AND (ap.proxima_revisao <= CURRENT_TIMESTAMP OR falhas.ultima_falha IS NOT NULL)
+		ORDER BY falhas.ultima_falha DESC NULLS LAST, ap.proxima_revisao ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []anki.AnkiCard
+	for rows.Next() {
+		var card anki.AnkiCard
+		var fatiasJSON []byte
+
+		err := rows.Scan(
+			&card.ID, &card.FraseID, &card.Conteudo,
+			&card.TraducaoCompleta,
+			&fatiasJSON,
+			&card.Facilidade, &card.Intervalo, &card.Repeticoes, &card.SequenciaAcertos,
+			&card.Estado, &card.ProximaRevisao,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if fatiasJSON != nil {
+			json.Unmarshal(fatiasJSON, &card.FatiasTraducoes)
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
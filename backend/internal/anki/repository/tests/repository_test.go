@@ -165,3 +165,33 @@ func TestGetStats_Success(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestGetStoryCards_Success(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM anki_progresso ap (.+) LEFT JOIN LATERAL (.+) anki_historico h (.+) LIMIT \\$2").
+		WithArgs(1, 5).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "frase_id", "conteudo", "traducao_completa", "fatias_traducoes",
+			"facilidade", "intervalo", "repeticoes", "sequencia_acertos", "estado", "proxima_revisao",
+		}).AddRow(
+			100, 200, "break the ice", "quebrar o gelo", nil,
+			2.3, 1, 2, 0, "aprendizado", now.Add(24*time.Hour),
+		))
+
+	cards, err := repo.GetStoryCards(context.Background(), 1, 5)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cards) != 1 || cards[0].Conteudo != "break the ice" {
+		t.Fatalf("unexpected cards: %+v", cards)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,18 +3,27 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"extension-backend/internal/anki"
+	"extension-backend/internal/events"
 )
 
 type Service struct {
-	repo anki.RepositoryInterface
+	repo   anki.RepositoryInterface
+	events events.Publisher
 }
 
 func New(repo anki.RepositoryInterface) *Service {
 	return &Service{repo: repo}
 }
 
+// WithEvents publica ReviewSubmitted a cada revisão registrada
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
+
 // GetDueCards retorna os cards que precisam ser revisados agora
 func (s *Service) GetDueCards(ctx context.Context, userID int) ([]anki.AnkiCard, error) {
 	cards, err := s.repo.GetDueCards(ctx, userID)
@@ -70,12 +79,42 @@ func (s *Service) SubmitReview(ctx context.Context, userID int, input anki.Revie
 		return nil, fmt.Errorf("failed to insert history: %w", err)
 	}
 
-	return &anki.ReviewResult{
+	review := &anki.ReviewResult{
 		NovoIntervalo:  result.NovoIntervalo,
 		NovaFacilidade: result.NovaFacilidade,
 		ProximaRevisao: result.ProximaRevisao.Format("2006-01-02T15:04:05Z"),
 		Estado:         result.NovoEstado,
-	}, nil
+	}
+	s.publishReview(ctx, userID, card, input.Nota, review)
+	return review, nil
+}
+
+// publishReview avisa os assinantes; falha no bus não desfaz a revisão
+func (s *Service) publishReview(ctx context.Context, userID int, card *anki.AnkiCard, nota int, review *anki.ReviewResult) {
+	if s.events == nil {
+		return
+	}
+	payload := events.ReviewSubmittedPayload{
+		AnkiID:         card.ID,
+		FraseID:        card.FraseID,
+		Nota:           nota,
+		NovoIntervalo:  review.NovoIntervalo,
+		Estado:         review.Estado,
+		ProximaRevisao: review.ProximaRevisao,
+	}
+	// Total atualizado para o contador de pendentes (anki_due_changed no SSE)
+	if stats, err := s.repo.GetStats(ctx, userID); err == nil {
+		payload.Due = &stats.DueToday
+	} else {
+		log.Printf("[Anki] Failed to count due cards for user %d: %v", userID, err)
+	}
+	e, err := events.New(events.ReviewSubmitted, userID, payload)
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Anki] Failed to publish %s for card %d: %v", events.ReviewSubmitted, card.ID, err)
+	}
 }
 
 // GetStats retorna as estatísticas da sessão do usuário
@@ -1,6 +1,9 @@
 package audio
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // STTSessionFactory creates fresh STT sessions per speech turn.
 // Each recording turn should call NewSession() to get an isolated connection.
@@ -23,6 +26,45 @@ type TextToSpeechProvider interface {
 	StreamText(ctx context.Context, text string, out chan<- []byte) error
 }
 
+// FormatTextToSpeechProvider TTS que gera outros formatos além do MP3 padrão
+type FormatTextToSpeechProvider interface {
+	TextToSpeechProvider
+	// StreamTextAs como StreamText, no formato pedido (um dos Formats)
+	StreamTextAs(ctx context.Context, text string, format AudioFormat, out chan<- []byte) error
+	// Formats formatos de saída nativos do provider
+	Formats() []AudioFormat
+}
+
+// VoiceTextToSpeechProvider TTS que fala com outras vozes além da padrão
+type VoiceTextToSpeechProvider interface {
+	TextToSpeechProvider
+	// StreamTextWithVoice como StreamTextAs, com a voz pedida ("" = a padrão do provider)
+	StreamTextWithVoice(ctx context.Context, text string, voiceID string, format AudioFormat, out chan<- []byte) error
+}
+
+// Encodings de áudio aceitos no setup
+const (
+	EncodingPCM16  = "pcm_s16le" // PCM 16-bit little-endian mono
+	EncodingPCMF32 = "pcm_f32le" // PCM float32 little-endian mono (Web Audio)
+	EncodingMP3    = "mp3"
+	EncodingOpus   = "opus"
+)
+
+// STTSampleRate taxa do PCM entregue ao STT e ao VAD (a entrada é convertida para ela)
+const STTSampleRate = 16000
+
+// AudioFormat encoding e taxa de amostragem de um stream de áudio
+type AudioFormat struct {
+	Encoding   string `json:"encoding"`
+	SampleRate int    `json:"sample_rate,omitempty"`
+}
+
+// Formatos usados quando o setup não declara nada
+var (
+	DefaultInputFormat  = AudioFormat{Encoding: EncodingPCM16, SampleRate: STTSampleRate}
+	DefaultOutputFormat = AudioFormat{Encoding: EncodingMP3, SampleRate: 44100}
+)
+
 // LLMProvider processa a entrada de texto e retorna respostas streamadas em blocos lógicos.
 type LLMProvider interface {
 	// GenerateStream recebe uma string de entrada (como de um canal STT) e envia outputs contínuos 
@@ -30,13 +72,133 @@ type LLMProvider interface {
 	GenerateStream(ctx context.Context, history []ConversationTurn, input string, out chan<- string) error
 }
 
+// PersonaLLMProvider LLM que conduz a conversa no papel de um cenário de role-play
+type PersonaLLMProvider interface {
+	LLMProvider
+	// GenerateStreamAs como GenerateStream, com o tutor no papel e no idioma da persona
+	GenerateStreamAs(ctx context.Context, persona Persona, history []ConversationTurn, input string, out chan<- string) error
+}
+
+// Persona instruções do tutor para uma conexão (vazia = tutor genérico)
+type Persona struct {
+	Prompt      string   // papel do cenário (ex: garçom de um restaurante)
+	Language    string   // nome do idioma da conversa (ex: "English")
+	Level       string   // nível CEFR do cenário
+	TargetWords []string // palavras que o tutor deve dar chance de o aluno usar
+}
+
+// IsZero true quando não há cenário nem idioma escolhidos
+func (p Persona) IsZero() bool {
+	return p.Prompt == "" && p.Language == "" && p.Level == "" && len(p.TargetWords) == 0
+}
+
 // Message representa as mensagens enviadas/recebidas pelo Client Socket Payload
 type WebsocketMessage struct {
-	Type       string `json:"type"`             // event: auth, audio, audio_end, text, setup, stt, finished
-	Audio      string `json:"audio,omitempty"`  // base64 PCM chunk from frontend
-	Text       string `json:"text,omitempty"`
-	VoiceID    string `json:"voice_id,omitempty"`
-	LanguageID int    `json:"language_id,omitempty"`
+	Type        string          `json:"type"`            // event: auth, audio, audio_end, interrupt, text, setup, setup_ack, speech_end, stt, correction, target_words, tts_end, tts_cancelled, error
+	Audio       string          `json:"audio,omitempty"` // base64 audio chunk (JSON mode; binary frames carry raw audio)
+	Text        string          `json:"text,omitempty"`
+	VoiceID     string          `json:"voice_id,omitempty"`     // setup: voz do TTS (sobrepõe a do cenário)
+	LanguageID  int             `json:"language_id,omitempty"`  // setup: idiomas.id que o aluno está aprendendo
+	ScenarioID  int             `json:"scenario_id,omitempty"`  // setup: cenário de role-play
+	Scenario    *Scenario       `json:"scenario,omitempty"`     // setup_ack: o cenário escolhido
+	Language    *Language       `json:"language,omitempty"`     // setup_ack: o idioma da conversa
+	VAD         *VADOptions     `json:"vad,omitempty"`          // setup: liga o fim de turno automático
+	Input       *AudioFormat    `json:"input,omitempty"`        // setup: formato do áudio enviado pelo cliente
+	Output      *AudioFormat    `json:"output,omitempty"`       // setup: formato preferido da resposta; setup_ack: o negociado
+	Binary      bool            `json:"binary,omitempty"`       // setup: resposta em frames binários em vez de base64
+	Corrections []Correction    `json:"corrections,omitempty"`  // correction: erros encontrados na fala do usuário
+	TargetWords *TargetProgress `json:"target_words,omitempty"` // setup_ack, target_words: palavras-alvo do cenário
+}
+
+// ScenarioCatalog cenários de role-play e idiomas que o setup pode escolher
+type ScenarioCatalog interface {
+	GetScenario(ctx context.Context, id int) (*Scenario, error)
+	GetLanguage(ctx context.Context, id int) (*Language, error)
+}
+
+// Scenario cenário de role-play (ex: pedir num restaurante) com a persona do tutor
+type Scenario struct {
+	ID          int      `json:"id"`
+	Slug        string   `json:"slug"`
+	Title       string   `json:"title"`
+	Persona     string   `json:"-"`                  // prompt do papel do tutor; não vai para o cliente
+	Language    string   `json:"language,omitempty"` // código do idioma das palavras-alvo ("en"); vazio = qualquer
+	Level       string   `json:"level,omitempty"`    // nível CEFR
+	VoiceID     string   `json:"voice_id,omitempty"`
+	TargetWords []string `json:"target_words"`
+}
+
+// Language idioma da conversa (tabela idiomas)
+type Language struct {
+	ID   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// TargetProgress palavras-alvo já usadas pelo aluno na conexão
+type TargetProgress struct {
+	New       []string `json:"new,omitempty"` // target_words: usadas pela primeira vez neste turno
+	Used      []string `json:"used"`
+	Remaining []string `json:"remaining"`
+}
+
+// GrammarChecker analisa a fala do usuário e aponta erros (não bloqueia a resposta do tutor).
+// language é o idioma da conversa (nome ou código; vazio = inglês).
+type GrammarChecker interface {
+	Check(ctx context.Context, utterance, language string) ([]Correction, error)
+}
+
+// Correction erro numa fala do usuário, com a forma correta e a regra
+type Correction struct {
+	ID          int    `json:"id,omitempty"` // conversa_correcoes.id: permite salvar como frase
+	Original    string `json:"original"`
+	Corrected   string `json:"corrected"`
+	Explanation string `json:"explanation"`
+}
+
+// VADOptions detecção de fim de fala no servidor (turnos sem audio_end)
+type VADOptions struct {
+	SilenceMs int     `json:"silence_ms,omitempty"` // silêncio que fecha o turno (padrão 700)
+	Threshold float64 `json:"threshold,omitempty"`  // energia RMS mínima da voz, 0-1 (padrão 0.02)
+}
+
+// ConversationHistory guarda os turnos recentes de cada conversa do usuário. A conversa
+// (conversationID) é da conexão e muda quando o setup troca de cenário ou idioma.
+type ConversationHistory interface {
+	GetHistory(ctx context.Context, userID int, conversationID string) ([]ConversationTurn, error)
+	AppendTurn(ctx context.Context, userID int, conversationID string, role string, content string) error
+}
+
+// TranscriptRecorder persiste a conversa inteira (o histórico do LLM guarda só os últimos turnos)
+type TranscriptRecorder interface {
+	StartConversation(ctx context.Context, userID int, providers Providers, setup ConversationSetup) (int, error)
+	RecordTurn(ctx context.Context, conversationID int, turn RecordedTurn) (int, error)
+	RecordCorrections(ctx context.Context, turnID int, corrections []Correction) ([]int, error)
+	RecordTargetWords(ctx context.Context, turnID int, words []string) error
+	EndConversation(ctx context.Context, conversationID int) error
+}
+
+// ConversationSetup cenário e idioma escolhidos no setup (zero = conversa livre)
+type ConversationSetup struct {
+	ScenarioID int
+	Language   string // código do idioma
+}
+
+// Providers identifica os modelos usados na conversa
+type Providers struct {
+	STT string
+	LLM string
+	TTS string
+}
+
+// RecordedTurn turno salvo na transcrição, com os tempos medidos pelo pipeline
+type RecordedTurn struct {
+	Role        string // "user" or "model"
+	Content     string
+	StartedAt   time.Time     // audio_end / fim da fala
+	Duration    time.Duration // user: espera pelo STT; model: do fim da fala até o fim da resposta
+	TTFA        time.Duration // só model: time-to-first-audio (0 se não houve áudio)
+	Interrupted bool          // resposta cortada por barge-in
 }
 
 // ConversationTurn represents a single exchange in the session context
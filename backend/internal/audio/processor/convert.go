@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"extension-backend/internal/audio"
+)
+
+// pcmConverter converte um stream de PCM (s16le ou f32le) para PCM 16-bit em
+// outra taxa, por interpolação linear. Guarda estado entre chunks: bytes de
+// amostras cortadas ao meio e a posição da interpolação.
+type pcmConverter struct {
+	encoding string
+	step     float64 // amostras de entrada por amostra de saída
+	pos      float64 // posição da próxima saída, relativa a prev
+	prev     int16
+	hasPrev  bool
+	carry    []byte
+}
+
+func newPCMConverter(from audio.AudioFormat, toRate int) *pcmConverter {
+	return &pcmConverter{encoding: from.Encoding, step: float64(from.SampleRate) / float64(toRate)}
+}
+
+// validateInput confere se o formato de entrada pode ser convertido
+func validateInput(f audio.AudioFormat) error {
+	if f.Encoding != audio.EncodingPCM16 && f.Encoding != audio.EncodingPCMF32 {
+		return fmt.Errorf("unsupported input encoding %q", f.Encoding)
+	}
+	if f.SampleRate < 8000 || f.SampleRate > 48000 {
+		return fmt.Errorf("unsupported input sample rate %d", f.SampleRate)
+	}
+	return nil
+}
+
+// Convert recebe um chunk no formato de origem e devolve PCM 16-bit na taxa de destino
+func (c *pcmConverter) Convert(data []byte) []byte {
+	samples := c.decode(data)
+	if c.step != 1 {
+		samples = c.resample(samples)
+	}
+	out := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(s))
+	}
+	return out
+}
+
+func (c *pcmConverter) decode(data []byte) []int16 {
+	size := 2
+	if c.encoding == audio.EncodingPCMF32 {
+		size = 4
+	}
+	if len(c.carry) > 0 {
+		data = append(c.carry, data...)
+		c.carry = nil
+	}
+	n := len(data) / size
+	if rest := data[n*size:]; len(rest) > 0 {
+		c.carry = append([]byte(nil), rest...)
+	}
+
+	samples := make([]int16, n)
+	for i := range samples {
+		if size == 2 {
+			samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+			continue
+		}
+		f := float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:])))
+		samples[i] = int16(math.Max(-1, math.Min(1, f)) * 32767)
+	}
+	return samples
+}
+
+// resample interpola sobre [prev] + in, continuando de onde o chunk anterior parou
+func (c *pcmConverter) resample(in []int16) []int16 {
+	if len(in) == 0 {
+		return nil
+	}
+	x := in
+	if c.hasPrev {
+		x = append([]int16{c.prev}, in...)
+	}
+
+	out := make([]int16, 0, int(float64(len(x))/c.step)+1)
+	last := float64(len(x) - 1)
+	for ; c.pos <= last; c.pos += c.step {
+		i := int(c.pos)
+		frac := c.pos - float64(i)
+		y := float64(x[i])
+		if i+1 < len(x) {
+			y += (float64(x[i+1]) - y) * frac
+		}
+		out = append(out, int16(y))
+	}
+
+	c.pos -= last
+	c.prev = x[len(x)-1]
+	c.hasPrev = true
+	return out
+}
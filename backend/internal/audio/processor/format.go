@@ -0,0 +1,51 @@
+package processor
+
+import "extension-backend/internal/audio"
+
+// output formato negociado para a resposta de uma conexão
+type output struct {
+	client audio.AudioFormat // o que o cliente recebe
+	tts    audio.AudioFormat // o que é pedido ao provider (difere só na taxa do PCM)
+	binary bool
+}
+
+func (o output) resample() bool {
+	return o.client.Encoding == audio.EncodingPCM16 && o.client.SampleRate != o.tts.SampleRate
+}
+
+// negotiateOutput escolhe o formato da resposta: o pedido, se o provider gera
+// nativamente; PCM numa taxa que ele não gera é reamostrado a partir do PCM de
+// maior taxa disponível; qualquer outro caso cai no MP3 padrão.
+func negotiateOutput(tts audio.TextToSpeechProvider, want audio.AudioFormat) (client, provider audio.AudioFormat) {
+	fp, ok := tts.(audio.FormatTextToSpeechProvider)
+	if !ok {
+		return audio.DefaultOutputFormat, audio.DefaultOutputFormat
+	}
+
+	var bestPCM *audio.AudioFormat
+	for _, f := range fp.Formats() {
+		if f.Encoding == want.Encoding && (want.SampleRate == 0 || f.SampleRate == want.SampleRate) {
+			return f, f
+		}
+		if f.Encoding == audio.EncodingPCM16 && (bestPCM == nil || f.SampleRate > bestPCM.SampleRate) {
+			f := f
+			bestPCM = &f
+		}
+	}
+
+	if want.Encoding == audio.EncodingPCM16 && want.SampleRate >= 8000 && want.SampleRate <= 48000 && bestPCM != nil {
+		return want, *bestPCM
+	}
+	return audio.DefaultOutputFormat, audio.DefaultOutputFormat
+}
+
+// synthesize chama o TTS no formato negociado, com a voz do cenário se houver
+func (p *Pipeline) synthesize(t *turn, sentence string, out chan<- []byte) error {
+	if vp, ok := p.ttsProvider.(audio.VoiceTextToSpeechProvider); ok && t.roleplay.voiceID != "" {
+		return vp.StreamTextWithVoice(t.ctx, sentence, t.roleplay.voiceID, t.out.tts, out)
+	}
+	if fp, ok := p.ttsProvider.(audio.FormatTextToSpeechProvider); ok && t.out.tts != audio.DefaultOutputFormat {
+		return fp.StreamTextAs(t.ctx, sentence, t.out.tts, out)
+	}
+	return p.ttsProvider.StreamText(t.ctx, sentence, out)
+}
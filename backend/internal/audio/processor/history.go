@@ -18,8 +18,12 @@ func NewHistoryManager(c *cache.Client) *HistoryManager {
 	return &HistoryManager{cacheClient: c}
 }
 
-func (h *HistoryManager) GetHistory(ctx context.Context, userID int) ([]audio.ConversationTurn, error) {
-	key := fmt.Sprintf("conversation:%d", userID)
+func historyKey(userID int, conversationID string) string {
+	return fmt.Sprintf("conversation:%d:%s", userID, conversationID)
+}
+
+func (h *HistoryManager) GetHistory(ctx context.Context, userID int, conversationID string) ([]audio.ConversationTurn, error) {
+	key := historyKey(userID, conversationID)
 	data, err := h.cacheClient.Get(ctx, key)
 	if err != nil {
 		// Redis returns redis.Nil often, which cache client might return as an error or empty string
@@ -37,12 +41,12 @@ func (h *HistoryManager) GetHistory(ctx context.Context, userID int) ([]audio.Co
 	return history, nil
 }
 
-func (h *HistoryManager) AppendTurn(ctx context.Context, userID int, role string, content string) error {
+func (h *HistoryManager) AppendTurn(ctx context.Context, userID int, conversationID string, role string, content string) error {
 	if content == "" {
 		return nil
 	}
 
-	history, err := h.GetHistory(ctx, userID)
+	history, err := h.GetHistory(ctx, userID, conversationID)
 	if err != nil {
 		history = []audio.ConversationTurn{}
 	}
@@ -62,12 +66,11 @@ func (h *HistoryManager) AppendTurn(ctx context.Context, userID int, role string
 		return err
 	}
 
-	key := fmt.Sprintf("conversation:%d", userID)
+	key := historyKey(userID, conversationID)
 	// Timeout conversation after 1 hour of inactivity
 	return h.cacheClient.Set(ctx, key, string(data), 1*time.Hour)
 }
 
-func (h *HistoryManager) ClearHistory(ctx context.Context, userID int) error {
-	key := fmt.Sprintf("conversation:%d", userID)
-	return h.cacheClient.Delete(ctx, key)
+func (h *HistoryManager) ClearHistory(ctx context.Context, userID int, conversationID string) error {
+	return h.cacheClient.Delete(ctx, historyKey(userID, conversationID))
 }
@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"extension-backend/internal/metrics"
+)
+
+// Metrics latência da conversa por voz nesta instância
+type Metrics struct {
+	Turns          atomic.Int64 // turnos que chegaram a enviar áudio
+	TTFAMillis     atomic.Int64 // soma do time-to-first-audio (fim da fala → primeiro áudio enviado)
+	LastTTFAMillis atomic.Int64
+}
+
+// NewMetrics cria os contadores (compartilhados entre as conexões)
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Register expõe os contadores no registry (/metrics)
+func (m *Metrics) Register(reg *metrics.Registry) {
+	reg.CounterFunc("conversation_ttfa_milliseconds_count", "Voice turns that sent audio to the client", m.Turns.Load)
+	reg.CounterFunc("conversation_ttfa_milliseconds_sum", "Total time from end of speech to first audio chunk, in milliseconds", m.TTFAMillis.Load)
+	reg.GaugeFunc("conversation_ttfa_last_milliseconds", "Time to first audio of the most recent voice turn", func() float64 {
+		return float64(m.LastTTFAMillis.Load())
+	})
+}
+
+// observeTTFA registra o time-to-first-audio de um turno
+func (m *Metrics) observeTTFA(userID int, d time.Duration) {
+	log.Printf("[Pipeline] Time-to-first-audio %dms (user: %d)", d.Milliseconds(), userID)
+	if m == nil {
+		return
+	}
+	m.Turns.Add(1)
+	m.TTFAMillis.Add(d.Milliseconds())
+	m.LastTTFAMillis.Store(d.Milliseconds())
+}
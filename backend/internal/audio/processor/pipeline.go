@@ -3,11 +3,15 @@ package processor
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"log"
 	"sync"
 	"time"
 
+	"extension-backend/internal/ai"
 	"extension-backend/internal/audio"
+	"extension-backend/internal/events"
+	"extension-backend/internal/shared"
 
 	"github.com/gorilla/websocket"
 )
@@ -19,10 +23,16 @@ type Pipeline struct {
 	sttFactory     audio.STTSessionFactory
 	ttsProvider    audio.TextToSpeechProvider
 	llmProvider    audio.LLMProvider
-	historyManager *HistoryManager
+	historyManager audio.ConversationHistory
+	events         events.Publisher
+	metrics        *Metrics
+	recorder       audio.TranscriptRecorder
+	providers      audio.Providers
+	grammar        audio.GrammarChecker
+	scenarios      audio.ScenarioCatalog
 }
 
-func NewPipeline(sttFactory audio.STTSessionFactory, tts audio.TextToSpeechProvider, llm audio.LLMProvider, history *HistoryManager) *Pipeline {
+func NewPipeline(sttFactory audio.STTSessionFactory, tts audio.TextToSpeechProvider, llm audio.LLMProvider, history audio.ConversationHistory) *Pipeline {
 	return &Pipeline{
 		sttFactory:     sttFactory,
 		ttsProvider:    tts,
@@ -31,23 +41,68 @@ func NewPipeline(sttFactory audio.STTSessionFactory, tts audio.TextToSpeechProvi
 	}
 }
 
-// HandleWSConnection runs the pipeline for one client WebSocket connection.
+// WithEvents publica eventos de domínio (ex: cota do LLM esgotada) para o usuário da conexão
+func (p *Pipeline) WithEvents(publisher events.Publisher) *Pipeline {
+	p.events = publisher
+	return p
+}
+
+// WithMetrics registra o time-to-first-audio de cada turno (contadores compartilhados)
+func (p *Pipeline) WithMetrics(m *Metrics) *Pipeline {
+	p.metrics = m
+	return p
+}
+
+// WithRecorder persiste a conversa inteira (turnos, tempos e providers) além do histórico curto
+func (p *Pipeline) WithRecorder(recorder audio.TranscriptRecorder, providers audio.Providers) *Pipeline {
+	p.recorder = recorder
+	p.providers = providers
+	return p
+}
+
+// WithGrammar analisa cada fala do usuário em paralelo com a resposta e envia os
+// erros encontrados numa mensagem "correction"
+func (p *Pipeline) WithGrammar(checker audio.GrammarChecker) *Pipeline {
+	p.grammar = checker
+	return p
+}
+
+// WithScenarios permite escolher no setup um cenário de role-play e o idioma da conversa
+func (p *Pipeline) WithScenarios(catalog audio.ScenarioCatalog) *Pipeline {
+	p.scenarios = catalog
+	return p
+}
+
+// HandleWSConnection runs the pipeline for one client WebSocket connection
+// of an already authenticated user (history and quotas are scoped by userID).
 // Blocks until context cancels or the client disconnects.
-func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn) {
+func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn, userID int) {
 	defer conn.Close()
 
 	pipelineCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// A transcrição é gravada mesmo com o cliente já desconectado
+	saveCtx := context.WithoutCancel(ctx)
+	recording := newTranscript(p.recorder, p.providers, userID)
+	defer recording.end(saveCtx)
+
 	// ─── Connection-scoped state ─────────────────────────────────
 	var (
-		userID    int
-		userIDMu  sync.RWMutex
-		connMu    sync.Mutex    // Protects all writes to conn (not thread-safe)
-		wg        sync.WaitGroup // Tracks in-flight turn goroutines
+		connMu sync.Mutex     // Protects all writes to conn (not thread-safe)
+		wg     sync.WaitGroup // Tracks in-flight turn goroutines
 
 		activeSession audio.STTSession
 		sessionMu     sync.Mutex
+
+		// Configurados pelo setup; só o read loop usa (os turnos copiam o output e o roleplay)
+		vad   *VAD          // fim de turno automático
+		input *pcmConverter // entrada → PCM 16 kHz (nil: já chega assim)
+		out   = output{client: audio.DefaultOutputFormat, tts: audio.DefaultOutputFormat}
+		rp    roleplay
+
+		// Histórico do LLM desta conexão: outra aba ou outro cenário começa do zero
+		historyID = shared.GenerateToken(8)
 	)
 
 	// writeJSON sends a JSON message to the client WS in a thread-safe manner.
@@ -60,24 +115,54 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 		}
 	}
 
-	// writeAudioChunk sends a base64-encoded audio chunk to the client.
-	writeAudioChunk := func(audioBytes []byte) error {
-		b64 := base64.StdEncoding.EncodeToString(audioBytes)
+	// writeAudioChunk sends an audio chunk to the client: raw in a binary frame,
+	// or base64 inside JSON for clients that did not ask for binary.
+	writeAudioChunk := func(binaryFrames bool, audioBytes []byte) error {
 		connMu.Lock()
 		defer connMu.Unlock()
 		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if binaryFrames {
+			return conn.WriteMessage(websocket.BinaryMessage, audioBytes)
+		}
 		return conn.WriteJSON(audio.WebsocketMessage{
 			Type:  "audio",
-			Audio: b64,
+			Audio: base64.StdEncoding.EncodeToString(audioBytes),
 		})
 	}
 
+	// ─── Turn state (barge-in) ───────────────────────────────────
+	var (
+		current *turn // turno mais recente ainda em processamento
+		turnMu  sync.Mutex
+	)
+
+	// interruptTurn cancela o turno em andamento; onlySpeaking restringe ao TTS tocando
+	interruptTurn := func(onlySpeaking bool) {
+		turnMu.Lock()
+		t := current
+		turnMu.Unlock()
+		if t == nil || (onlySpeaking && !t.speaking.Load()) {
+			return
+		}
+		if t.interrupt() {
+			log.Println("[Pipeline] Barge-in — cancelando LLM/TTS do turno")
+		}
+	}
+
 	// ─── processTurn runs the complete pipeline for one speech turn ──
 	// This is a single goroutine that executes sequentially:
-	// Commit → WaitForTranscript → LLM → TTS → tts_end
-	processTurn := func(session audio.STTSession, uid int) {
+	// Commit → WaitForTranscript → LLM + TTS (frase a frase, em paralelo) → tts_end | tts_cancelled
+	processTurn := func(session audio.STTSession, t *turn, uid int) {
 		defer wg.Done()
 		defer session.Close()
+		defer func() {
+			t.cancel()
+			turnMu.Lock()
+			if current == t {
+				current = nil
+			}
+			turnMu.Unlock()
+		}()
 
 		// 1. Commit — request final transcription
 		if err := session.Commit(); err != nil {
@@ -85,8 +170,8 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 			return
 		}
 
-		// 2. Wait for transcript (with 30s timeout derived from pipeline context)
-		transcriptCtx, transcriptCancel := context.WithTimeout(pipelineCtx, 30*time.Second)
+		// 2. Wait for transcript (with 30s timeout derived from turn context)
+		transcriptCtx, transcriptCancel := context.WithTimeout(t.ctx, 30*time.Second)
 		defer transcriptCancel()
 
 		transcript, err := session.WaitForTranscript(transcriptCtx)
@@ -107,7 +192,38 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 
 		// 4. Save user turn to conversation history
 		if p.historyManager != nil {
-			p.historyManager.AppendTurn(pipelineCtx, uid, "user", transcript)
+			p.historyManager.AppendTurn(pipelineCtx, uid, t.history, "user", transcript)
+		}
+		userTurnID := recording.record(saveCtx, audio.RecordedTurn{
+			Role:      "user",
+			Content:   transcript,
+			StartedAt: t.started,
+			Duration:  time.Since(t.started),
+		})
+
+		// Correção gramatical em paralelo: não atrasa a resposta e não é
+		// cancelada por barge-in (só pela queda da conexão)
+		if p.grammar != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				corrections := p.checkGrammar(pipelineCtx, transcript, t.roleplay.languageName())
+				if len(corrections) == 0 {
+					return
+				}
+				recording.recordCorrections(saveCtx, userTurnID, corrections)
+				if pipelineCtx.Err() == nil {
+					writeJSON(audio.WebsocketMessage{Type: "correction", Text: transcript, Corrections: corrections})
+				}
+			}()
+		}
+
+		// Palavras-alvo do cenário usadas pela primeira vez nesta fala
+		if used := t.roleplay.targets.Use(transcript); len(used) > 0 {
+			recording.recordTargetWords(saveCtx, userTurnID, used)
+			progress := t.roleplay.targets.Progress()
+			progress.New = used
+			writeJSON(audio.WebsocketMessage{Type: "target_words", TargetWords: progress})
 		}
 
 		// 5. Generate LLM response
@@ -118,7 +234,7 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 		// Fetch conversation history for context
 		var history []audio.ConversationTurn
 		if p.historyManager != nil {
-			if h, err := p.historyManager.GetHistory(pipelineCtx, uid); err == nil {
+			if h, err := p.historyManager.GetHistory(pipelineCtx, uid, t.history); err == nil {
 				history = h
 			}
 		}
@@ -131,57 +247,163 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 		llmDone := make(chan error, 1)
 		go func() {
 			defer close(llmCh)
-			llmDone <- p.llmProvider.GenerateStream(pipelineCtx, history, transcript, llmCh)
+			llmDone <- p.generate(t, history, transcript, llmCh)
 		}()
 
+		// 6. TTS em paralelo com o LLM: cada frase completa vai para o TTS assim
+		// que aparece e o player envia o áudio na ordem das frases
+		jobs := make(chan *ttsJob, ttsLookahead)
+		playerDone := make(chan struct{})
+		go func() {
+			defer close(playerDone)
+			p.play(t, jobs, func(b []byte) error { return writeAudioChunk(t.out.binary, b) }, cancel, uid)
+		}()
+		var segmenter sentenceSegmenter
+		enqueue := func(sentence string) {
+			if sentence != "" && t.ctx.Err() == nil {
+				jobs <- p.startTTS(t, sentence)
+			}
+		}
+
 		// Read LLM chunks, stream text to frontend, accumulate full response
 		for chunk := range llmCh {
 			completeResponse += chunk
-			writeJSON(audio.WebsocketMessage{Type: "text", Text: chunk})
+			if t.ctx.Err() == nil {
+				writeJSON(audio.WebsocketMessage{Type: "text", Text: chunk})
+			}
+			for _, sentence := range segmenter.Push(chunk) {
+				enqueue(sentence)
+			}
 		}
 
-		// Check LLM error
-		if err := <-llmDone; err != nil {
-			log.Printf("[Pipeline] LLM Error: %v", err)
-			return
+		llmErr := <-llmDone
+		if llmErr == nil {
+			enqueue(segmenter.Flush())
+		}
+		close(jobs)
+		<-playerDone
+
+		// Check LLM error (cancelamento por barge-in não é erro)
+		if llmErr != nil && !t.interrupted.Load() {
+			log.Printf("[Pipeline] LLM Error: %v", llmErr)
+			if ai.IsQuotaError(llmErr) {
+				p.publishQuota(pipelineCtx, uid)
+			}
 		}
 
-		// 6. Save model response to history
+		// 7. Save model response to history — só o que o usuário ouviu se a resposta não terminou
+		recordModel := func(content string) {
+			if content == "" {
+				return
+			}
+			recording.record(saveCtx, audio.RecordedTurn{
+				Role:        "model",
+				Content:     content,
+				StartedAt:   t.started,
+				Duration:    time.Since(t.started),
+				TTFA:        t.ttfa,
+				Interrupted: t.interrupted.Load(),
+			})
+		}
+		if t.interrupted.Load() {
+			writeJSON(audio.WebsocketMessage{Type: "tts_cancelled"})
+			spoken := t.spokenText()
+			if p.historyManager != nil {
+				p.historyManager.AppendTurn(pipelineCtx, uid, t.history, "model", spoken)
+			}
+			recordModel(spoken)
+			return
+		}
+		if llmErr != nil {
+			completeResponse = t.spokenText()
+		}
 		if p.historyManager != nil && completeResponse != "" {
-			p.historyManager.AppendTurn(pipelineCtx, uid, "model", completeResponse)
+			p.historyManager.AppendTurn(pipelineCtx, uid, t.history, "model", completeResponse)
+		}
+		recordModel(completeResponse)
+		if completeResponse == "" || pipelineCtx.Err() != nil {
+			return
 		}
 
-		// 7. TTS — send complete response in a single call
-		if completeResponse == "" {
+		// 8. Signal frontend that audio for this turn is complete
+		writeJSON(audio.WebsocketMessage{Type: "tts_end"})
+	}
+
+	// endTurn fecha a sessão STT ativa e processa o turno (audio_end ou VAD)
+	endTurn := func(reason string) {
+		sessionMu.Lock()
+		session := activeSession
+		activeSession = nil // Free for next turn
+		sessionMu.Unlock()
+
+		if session == nil {
+			log.Printf("[Pipeline] %s sem sessão ativa, ignorando", reason)
 			return
 		}
 
-		log.Printf("[Pipeline] Enviando ao TTS (%d chars)", len(completeResponse))
+		log.Printf("[Pipeline] %s — iniciando processamento do turno", reason)
+
+		// O turno novo substitui o anterior, se ele ainda estiver respondendo
+		t := newTurn(pipelineCtx)
+		t.out = out
+		t.roleplay = rp
+		t.history = historyID
+		turnMu.Lock()
+		previous := current
+		current = t
+		turnMu.Unlock()
+		if previous != nil {
+			previous.interrupt()
+		}
 
-		// Per-turn TTS channel
-		ttsCh := make(chan []byte, 50)
-		ttsDone := make(chan error, 1)
+		wg.Add(1)
+		go processTurn(session, t, userID)
+	}
 
-		go func() {
-			defer close(ttsCh)
-			ttsDone <- p.ttsProvider.StreamText(pipelineCtx, completeResponse, ttsCh)
-		}()
+	// handleAudio converte um chunk recebido para PCM 16 kHz e o envia ao VAD e ao STT
+	handleAudio := func(raw []byte) {
+		pcmBytes := raw
+		if input != nil {
+			pcmBytes = input.Convert(raw)
+		}
 
-		// Stream TTS audio chunks to client
-		for audioBytes := range ttsCh {
-			if err := writeAudioChunk(audioBytes); err != nil {
-				log.Printf("[Pipeline] Erro enviando áudio TTS ao cliente: %v", err)
-				cancel()
+		// Fala nova durante o TTS = barge-in. Com VAD o microfone fica aberto
+		// o tempo todo, então só conta quando o VAD detecta voz.
+		speechStarted, speechEnded := false, false
+		if vad != nil {
+			speechStarted, speechEnded = vad.Process(pcmBytes)
+		}
+		if vad == nil || speechStarted {
+			interruptTurn(true)
+		}
+
+		sessionMu.Lock()
+
+		// Lazy session creation on first audio chunk of a turn
+		if activeSession == nil {
+			log.Println("[Pipeline] Novo turno de fala — criando sessão STT...")
+			session, err := p.sttFactory.NewSession(pipelineCtx)
+			if err != nil {
+				log.Printf("[Pipeline] Falha criando sessão STT: %v", err)
+				sessionMu.Unlock()
 				return
 			}
+			activeSession = session
+			log.Println("[Pipeline] Sessão STT pronta")
 		}
 
-		if err := <-ttsDone; err != nil {
-			log.Printf("[Pipeline] TTS Error: %v", err)
+		// Forward to STT
+		if err := activeSession.SendAudio(pcmBytes); err != nil {
+			log.Printf("[Pipeline] Erro enviando áudio ao STT: %v", err)
 		}
 
-		// 8. Signal frontend that audio for this turn is complete
-		writeJSON(audio.WebsocketMessage{Type: "tts_end"})
+		sessionMu.Unlock()
+
+		// VAD: silêncio depois da fala fecha o turno como um audio_end
+		if speechEnded {
+			writeJSON(audio.WebsocketMessage{Type: "speech_end"})
+			endTurn("VAD")
+		}
 	}
 
 	// Wait for all turn goroutines before exiting
@@ -193,71 +415,222 @@ func (p *Pipeline) HandleWSConnection(ctx context.Context, conn *websocket.Conn)
 		case <-pipelineCtx.Done():
 			return
 		default:
-			var msg audio.WebsocketMessage
-			if err := conn.ReadJSON(&msg); err != nil {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
 				log.Printf("[Pipeline] WS Read err ou cliente desconectou: %v", err)
 				cancel()
 				return
 			}
 
+			// Frame binário = chunk de áudio cru no formato de entrada do setup
+			if msgType == websocket.BinaryMessage {
+				handleAudio(data)
+				continue
+			}
+
+			var msg audio.WebsocketMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				log.Printf("[Pipeline] Mensagem inválida: %v", err)
+				continue
+			}
+
 			switch msg.Type {
 			case "setup":
-				log.Println("[Pipeline] Conexão configurada")
-				userIDMu.Lock()
-				userID = 1 // Hardcoded for MVP
-				userIDMu.Unlock()
+				// Cenário, idioma, voz e formatos: tudo é resolvido e validado antes de
+				// mudar qualquer configuração; setup inválido mantém a configuração anterior
+				r, err := loadRoleplay(pipelineCtx, p.scenarios, msg)
+				if err != nil {
+					writeJSON(audio.WebsocketMessage{Type: "error", Text: err.Error()})
+					continue
+				}
 
-			case "audio":
-				sessionMu.Lock()
-
-				// Lazy session creation on first audio chunk of a turn
-				if activeSession == nil {
-					log.Println("[Pipeline] Novo turno de fala — criando sessão STT...")
-					session, err := p.sttFactory.NewSession(pipelineCtx)
-					if err != nil {
-						log.Printf("[Pipeline] Falha criando sessão STT: %v", err)
-						sessionMu.Unlock()
+				// Formato de entrada: convertido para o PCM 16 kHz do STT/VAD
+				inFormat := audio.DefaultInputFormat
+				if msg.Input != nil {
+					if err := validateInput(*msg.Input); err != nil {
+						writeJSON(audio.WebsocketMessage{Type: "error", Text: err.Error()})
 						continue
 					}
-					activeSession = session
-					log.Println("[Pipeline] Sessão STT pronta")
+					inFormat = *msg.Input
+				}
+
+				// Formato de saída: o preferido, se o TTS consegue gerar (direto ou reamostrando)
+				newOut := output{client: audio.DefaultOutputFormat, tts: audio.DefaultOutputFormat, binary: msg.Binary}
+				if msg.Output != nil {
+					newOut.client, newOut.tts = negotiateOutput(p.ttsProvider, *msg.Output)
+				}
+
+				log.Printf("[Pipeline] Conexão configurada (user: %d)", userID)
+				vad = nil
+				if msg.VAD != nil {
+					cfg := VADConfigFromOptions(msg.VAD)
+					vad = NewVAD(cfg)
+					log.Printf("[Pipeline] VAD ligado (silêncio %s)", cfg.Silence)
+				}
+
+				input = nil
+				if inFormat != audio.DefaultInputFormat {
+					input = newPCMConverter(inFormat, audio.STTSampleRate)
 				}
 
-				// Decode base64 PCM and forward to STT
-				pcmBytes, err := base64.StdEncoding.DecodeString(msg.Audio)
+				// Cenário, idioma e voz do tutor; outro cenário ou idioma não herda o histórico
+				if !r.sameConversation(rp) {
+					historyID = shared.GenerateToken(8)
+				}
+				rp = r
+				recording.configure(saveCtx, rp.conversationSetup())
+				if rp.scenario != nil {
+					log.Printf("[Pipeline] Cenário %q (user: %d)", rp.scenario.Slug, userID)
+				}
+
+				out = newOut
+				log.Printf("[Pipeline] Áudio: entrada %s/%d, saída %s/%d (binário: %t)",
+					inFormat.Encoding, inFormat.SampleRate, out.client.Encoding, out.client.SampleRate, out.binary)
+
+				client := out.client
+				writeJSON(audio.WebsocketMessage{
+					Type:        "setup_ack",
+					Input:       &inFormat,
+					Output:      &client,
+					Binary:      out.binary,
+					Scenario:    rp.scenario,
+					Language:    rp.language,
+					TargetWords: rp.targets.Progress(),
+				})
+
+			case "interrupt":
+				interruptTurn(false)
+
+			case "audio":
+				// Decode base64 audio (clientes sem frames binários)
+				raw, err := base64.StdEncoding.DecodeString(msg.Audio)
 				if err != nil {
 					log.Printf("[Pipeline] Erro decodificando base64: %v", err)
-					sessionMu.Unlock()
 					continue
 				}
+				handleAudio(raw)
 
-				if err := activeSession.SendAudio(pcmBytes); err != nil {
-					log.Printf("[Pipeline] Erro enviando áudio ao STT: %v", err)
+			case "audio_end":
+				if vad != nil {
+					vad.Reset()
 				}
+				endTurn("audio_end")
+			}
+		}
+	}
+}
 
-				sessionMu.Unlock()
+// publishQuota avisa o usuário (via SSE quota_warning) que a cota do LLM acabou
+func (p *Pipeline) publishQuota(ctx context.Context, userID int) {
+	if p.events == nil {
+		return
+	}
+	e, err := events.New(events.QuotaExceeded, userID, events.QuotaExceededPayload{
+		Quota:   "ai_conversation",
+		Message: "Limite de conversação do provedor de IA atingido. Tente novamente em alguns minutos.",
+	})
+	if err == nil {
+		err = p.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Pipeline] Failed to publish %s for user %d: %v", events.QuotaExceeded, userID, err)
+	}
+}
 
-			case "audio_end":
-				sessionMu.Lock()
-				session := activeSession
-				activeSession = nil // Free for next turn
-				sessionMu.Unlock()
+// grammarTimeout limite da análise gramatical de uma fala
+const grammarTimeout = 20 * time.Second
 
-				if session == nil {
-					log.Println("[Pipeline] audio_end sem sessão ativa, ignorando")
-					continue
-				}
+// checkGrammar retorna os erros da fala; falhas só vão para o log
+func (p *Pipeline) checkGrammar(ctx context.Context, utterance, language string) []audio.Correction {
+	ctx, cancel := context.WithTimeout(ctx, grammarTimeout)
+	defer cancel()
+
+	corrections, err := p.grammar.Check(ctx, utterance, language)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[Pipeline] Erro na correção gramatical: %v", err)
+		}
+		return nil
+	}
+	return corrections
+}
 
-				log.Println("[Pipeline] audio_end — iniciando processamento do turno")
+// ttsLookahead frases sintetizadas à frente da que está sendo enviada ao cliente
+const ttsLookahead = 2
 
-				// Copy userID under read lock to avoid race
-				userIDMu.RLock()
-				uid := userID
-				userIDMu.RUnlock()
+// ttsJob síntese de uma frase; o áudio fica no canal até o player chegar nela
+type ttsJob struct {
+	sentence string
+	audio    chan []byte
+	done     chan error
+}
 
-				wg.Add(1)
-				go processTurn(session, uid)
+// startTTS começa a sintetizar a frase em background
+func (p *Pipeline) startTTS(t *turn, sentence string) *ttsJob {
+	job := &ttsJob{sentence: sentence, audio: make(chan []byte, 50), done: make(chan error, 1)}
+	go func() {
+		defer close(job.audio)
+		if !t.out.resample() {
+			job.done <- p.synthesize(t, sentence, job.audio)
+			return
+		}
+
+		// PCM numa taxa que o provider não gera: reamostra no caminho
+		raw := make(chan []byte, 50)
+		ttsDone := make(chan error, 1)
+		go func() {
+			defer close(raw)
+			ttsDone <- p.synthesize(t, sentence, raw)
+		}()
+		conv := newPCMConverter(t.out.tts, t.out.client.SampleRate)
+		for chunk := range raw {
+			if converted := conv.Convert(chunk); len(converted) > 0 {
+				job.audio <- converted
+			}
+		}
+		job.done <- <-ttsDone
+	}()
+	return job
+}
+
+// play envia o áudio das frases ao cliente na ordem em que foram geradas. Depois de
+// um barge-in, erro do TTS ou queda do cliente, só drena os jobs restantes.
+func (p *Pipeline) play(t *turn, jobs <-chan *ttsJob, writeAudioChunk func([]byte) error, cancelConn context.CancelFunc, uid int) {
+	defer t.speaking.Store(false)
+
+	first := true
+	stopped := false
+	for job := range jobs {
+		delivered := !stopped
+		for audioBytes := range job.audio {
+			if !delivered || t.ctx.Err() != nil {
+				delivered = false
+				continue
 			}
+			if err := writeAudioChunk(audioBytes); err != nil {
+				log.Printf("[Pipeline] Erro enviando áudio TTS ao cliente: %v", err)
+				cancelConn()
+				delivered = false
+				continue
+			}
+			if first {
+				first = false
+				t.speaking.Store(true)
+				t.ttfa = time.Since(t.started)
+				p.metrics.observeTTFA(uid, t.ttfa)
+			}
+		}
+
+		if err := <-job.done; err != nil {
+			if !t.interrupted.Load() {
+				log.Printf("[Pipeline] TTS Error: %v", err)
+			}
+			delivered = false
+		}
+		if !delivered {
+			stopped = true
+			continue
 		}
+		t.markSpoken(job.sentence)
 	}
 }
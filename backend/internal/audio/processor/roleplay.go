@@ -0,0 +1,106 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"extension-backend/internal/audio"
+)
+
+// voiceIDPattern IDs de voz aceitos no setup (vão na URL do provider)
+var voiceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// roleplay cenário, idioma e voz escolhidos no setup da conexão
+type roleplay struct {
+	scenario *audio.Scenario
+	language *audio.Language
+	persona  audio.Persona
+	voiceID  string         // "" = voz padrão do TTS
+	targets  *targetTracker // palavras-alvo do cenário (nil sem cenário)
+}
+
+// conversationSetup o que a transcrição grava do roleplay
+func (r roleplay) conversationSetup() audio.ConversationSetup {
+	var setup audio.ConversationSetup
+	if r.scenario != nil {
+		setup.ScenarioID = r.scenario.ID
+		setup.Language = r.scenario.Language
+	}
+	if r.language != nil {
+		setup.Language = r.language.Code
+	}
+	return setup
+}
+
+// sameConversation true se o setup mantém o cenário e o idioma (o histórico do LLM continua)
+func (r roleplay) sameConversation(other roleplay) bool {
+	return r.conversationSetup() == other.conversationSetup()
+}
+
+// languageName idioma da conversa para a correção gramatical ("" = padrão do checker)
+func (r roleplay) languageName() string {
+	if r.language != nil {
+		return r.language.Name
+	}
+	if r.scenario != nil {
+		return r.scenario.Language
+	}
+	return ""
+}
+
+// loadRoleplay resolve o cenário, o idioma e a voz pedidos no setup. A voz do
+// setup sobrepõe a do cenário; um cenário de outro idioma é recusado.
+func loadRoleplay(ctx context.Context, catalog audio.ScenarioCatalog, msg audio.WebsocketMessage) (roleplay, error) {
+	var r roleplay
+	if msg.VoiceID != "" && !voiceIDPattern.MatchString(msg.VoiceID) {
+		return r, errors.New("invalid voice_id")
+	}
+	if (msg.ScenarioID != 0 || msg.LanguageID != 0) && catalog == nil {
+		return r, errors.New("scenarios not available")
+	}
+
+	if msg.LanguageID != 0 {
+		language, err := catalog.GetLanguage(ctx, msg.LanguageID)
+		if err != nil {
+			return r, fmt.Errorf("language %d not found", msg.LanguageID)
+		}
+		r.language = language
+		r.persona.Language = language.Name
+	}
+
+	if msg.ScenarioID != 0 {
+		scenario, err := catalog.GetScenario(ctx, msg.ScenarioID)
+		if err != nil {
+			return r, fmt.Errorf("scenario %d not found", msg.ScenarioID)
+		}
+		if r.language != nil && scenario.Language != "" && scenario.Language != r.language.Code {
+			return r, fmt.Errorf("scenario %d is not available in %s", scenario.ID, r.language.Name)
+		}
+		r.scenario = scenario
+		r.persona.Prompt = scenario.Persona
+		r.persona.Level = scenario.Level
+		r.persona.TargetWords = scenario.TargetWords
+		r.voiceID = scenario.VoiceID
+
+		idioma := scenario.Language
+		if r.language != nil {
+			idioma = r.language.Code
+		}
+		r.targets = newTargetTracker(scenario.TargetWords, idioma)
+	}
+
+	if msg.VoiceID != "" {
+		r.voiceID = msg.VoiceID
+	}
+	return r, nil
+}
+
+// generate chama o LLM no papel do cenário, se o provider suporta personas
+func (p *Pipeline) generate(t *turn, history []audio.ConversationTurn, input string, out chan<- string) error {
+	if pp, ok := p.llmProvider.(audio.PersonaLLMProvider); ok && !t.roleplay.persona.IsZero() {
+		return pp.GenerateStreamAs(t.ctx, t.roleplay.persona, history, input, out)
+	}
+	return p.llmProvider.GenerateStream(t.ctx, history, input, out)
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sentenceSegmenter junta os chunks do LLM e devolve frases completas assim que
+// aparecem, para o TTS começar antes de a resposta terminar
+type sentenceSegmenter struct {
+	buf []rune
+}
+
+// Push acrescenta um chunk e retorna as frases que ficaram completas.
+// Pontuação final só fecha a frase quando seguida de espaço (ex: "3.5", "...").
+func (s *sentenceSegmenter) Push(chunk string) []string {
+	s.buf = append(s.buf, []rune(chunk)...)
+
+	var sentences []string
+	start := 0
+	for i, r := range s.buf {
+		end := -1
+		switch {
+		case r == '\n':
+			end = i
+		case strings.ContainsRune(".!?…", r) && i+1 < len(s.buf) && unicode.IsSpace(s.buf[i+1]):
+			end = i + 1
+		}
+		if end < 0 {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(s.buf[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+	s.buf = s.buf[start:]
+	return sentences
+}
+
+// Flush retorna o que sobrou no buffer (fim da resposta do LLM)
+func (s *sentenceSegmenter) Flush() string {
+	rest := strings.TrimSpace(string(s.buf))
+	s.buf = nil
+	return rest
+}
@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"strings"
+	"sync"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/vocabulary"
+)
+
+// targetTracker palavras-alvo do cenário que o aluno já usou na conexão.
+// A comparação é por lema ("ordered" conta para "order") e expressões
+// ("check in") precisam aparecer em sequência na fala.
+type targetTracker struct {
+	idioma string
+	words  []string   // como estão no cenário
+	lemas  [][]string // lemas de cada palavra-alvo
+
+	mu   sync.Mutex
+	used []bool
+}
+
+func newTargetTracker(words []string, idioma string) *targetTracker {
+	tt := &targetTracker{idioma: idioma}
+	for _, w := range words {
+		lemas := strings.Fields(vocabulary.LemmatizeExpression(w, idioma))
+		if len(lemas) == 0 {
+			continue
+		}
+		tt.words = append(tt.words, strings.TrimSpace(w))
+		tt.lemas = append(tt.lemas, lemas)
+	}
+	if len(tt.words) == 0 {
+		return nil
+	}
+	tt.used = make([]bool, len(tt.words))
+	return tt
+}
+
+// Use marca as palavras-alvo presentes na fala e retorna as usadas pela primeira vez
+func (tt *targetTracker) Use(utterance string) []string {
+	if tt == nil {
+		return nil
+	}
+	tokens := vocabulary.Tokenize(utterance, tt.idioma)
+	spoken := make([]string, len(tokens))
+	for i, t := range tokens {
+		spoken[i] = t.Lema
+	}
+
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	var newly []string
+	for i, lemas := range tt.lemas {
+		if !tt.used[i] && containsSequence(spoken, lemas) {
+			tt.used[i] = true
+			newly = append(newly, tt.words[i])
+		}
+	}
+	return newly
+}
+
+// Progress palavras usadas e restantes, na ordem do cenário
+func (tt *targetTracker) Progress() *audio.TargetProgress {
+	if tt == nil {
+		return nil
+	}
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	p := &audio.TargetProgress{Used: []string{}, Remaining: []string{}}
+	for i, w := range tt.words {
+		if tt.used[i] {
+			p.Used = append(p.Used, w)
+		} else {
+			p.Remaining = append(p.Remaining, w)
+		}
+	}
+	return p
+}
+
+// containsSequence true se seq aparece em sequência dentro de s
+func containsSequence(s, seq []string) bool {
+	for i := 0; i+len(seq) <= len(s); i++ {
+		match := true
+		for j := range seq {
+			if s[i+j] != seq[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
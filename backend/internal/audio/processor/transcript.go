@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"extension-backend/internal/audio"
+)
+
+// transcript grava os turnos de uma conexão no TranscriptRecorder. A conversa
+// só é criada no primeiro turno: conexões sem fala não deixam registro vazio.
+type transcript struct {
+	recorder  audio.TranscriptRecorder
+	providers audio.Providers
+	userID    int
+
+	mu     sync.Mutex
+	setup  audio.ConversationSetup
+	id     int
+	failed bool // falha ao criar a conversa: a conexão segue sem gravar
+}
+
+func newTranscript(recorder audio.TranscriptRecorder, providers audio.Providers, userID int) *transcript {
+	if recorder == nil {
+		return nil
+	}
+	return &transcript{recorder: recorder, providers: providers, userID: userID}
+}
+
+// record salva um turno e retorna o id (0 se não foi gravado); erros só vão
+// para o log (a conversa não para por isso)
+func (tr *transcript) record(ctx context.Context, t audio.RecordedTurn) int {
+	if tr == nil {
+		return 0
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.failed {
+		return 0
+	}
+
+	if tr.id == 0 {
+		id, err := tr.recorder.StartConversation(ctx, tr.userID, tr.providers, tr.setup)
+		if err != nil {
+			log.Printf("[Pipeline] Falha criando conversa (user: %d): %v", tr.userID, err)
+			tr.failed = true
+			return 0
+		}
+		tr.id = id
+	}
+
+	turnID, err := tr.recorder.RecordTurn(ctx, tr.id, t)
+	if err != nil {
+		log.Printf("[Pipeline] Falha salvando turno da conversa %d: %v", tr.id, err)
+		return 0
+	}
+	return turnID
+}
+
+// configure guarda o cenário do setup. Se a conversa atual já tem turnos, ela é
+// encerrada e o próximo turno abre outra com o cenário novo.
+func (tr *transcript) configure(ctx context.Context, setup audio.ConversationSetup) {
+	if tr == nil {
+		return
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if setup == tr.setup {
+		return
+	}
+	tr.setup = setup
+	if tr.id == 0 {
+		return
+	}
+	if err := tr.recorder.EndConversation(ctx, tr.id); err != nil {
+		log.Printf("[Pipeline] Falha encerrando conversa %d: %v", tr.id, err)
+	}
+	tr.id = 0
+}
+
+// recordTargetWords salva as palavras-alvo usadas pela primeira vez no turno
+func (tr *transcript) recordTargetWords(ctx context.Context, turnID int, words []string) {
+	if tr == nil || turnID == 0 || len(words) == 0 {
+		return
+	}
+	if err := tr.recorder.RecordTargetWords(ctx, turnID, words); err != nil {
+		log.Printf("[Pipeline] Falha salvando palavras-alvo do turno %d: %v", turnID, err)
+	}
+}
+
+// recordCorrections salva as correções do turno e preenche os ids; sem turno
+// gravado elas seguem sem id (não dá para salvar como frase depois)
+func (tr *transcript) recordCorrections(ctx context.Context, turnID int, corrections []audio.Correction) {
+	if tr == nil || turnID == 0 {
+		return
+	}
+	ids, err := tr.recorder.RecordCorrections(ctx, turnID, corrections)
+	if err != nil {
+		log.Printf("[Pipeline] Falha salvando correções do turno %d: %v", turnID, err)
+	}
+	for i := range min(len(ids), len(corrections)) {
+		corrections[i].ID = ids[i]
+	}
+}
+
+// end marca o fim da conversa, se algum turno foi gravado
+func (tr *transcript) end(ctx context.Context) {
+	if tr == nil {
+		return
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.id == 0 {
+		return
+	}
+	if err := tr.recorder.EndConversation(ctx, tr.id); err != nil {
+		log.Printf("[Pipeline] Falha encerrando conversa %d: %v", tr.id, err)
+	}
+}
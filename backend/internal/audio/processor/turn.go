@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// turn estado de um turno de fala em processamento (STT → LLM → TTS).
+// Cancelar o ctx do turno para o LLM e o TTS sem derrubar a conexão.
+type turn struct {
+	started     time.Time // audio_end recebido (base do time-to-first-audio)
+	ctx         context.Context
+	cancel      context.CancelFunc
+	out         output      // formato da resposta no momento em que o turno começou
+	roleplay    roleplay    // cenário, idioma e voz no momento em que o turno começou
+	history     string      // conversa do histórico do LLM (muda com o cenário)
+	speaking    atomic.Bool // TTS enviando áudio ao cliente
+	interrupted atomic.Bool
+	ttfa        time.Duration // até o primeiro chunk de áudio (escrito pelo player)
+
+	mu     sync.Mutex
+	spoken []string // frases cujo áudio foi entregue por inteiro
+}
+
+func newTurn(parent context.Context) *turn {
+	ctx, cancel := context.WithCancel(parent)
+	return &turn{started: time.Now(), ctx: ctx, cancel: cancel}
+}
+
+// interrupt cancela o turno (barge-in); false se ele já tinha sido interrompido
+func (t *turn) interrupt() bool {
+	if !t.interrupted.CompareAndSwap(false, true) {
+		return false
+	}
+	t.cancel()
+	return true
+}
+
+func (t *turn) markSpoken(sentence string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spoken = append(t.spoken, sentence)
+}
+
+// spokenText o que o usuário de fato ouviu da resposta
+func (t *turn) spokenText() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.Join(t.spoken, " ")
+}
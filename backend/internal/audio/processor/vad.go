@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"extension-backend/internal/audio"
+)
+
+// VADConfig parâmetros do detector de voz (PCM 16-bit little-endian mono)
+type VADConfig struct {
+	SampleRate int
+	Frame      time.Duration // janela de análise
+	Threshold  float64       // energia RMS mínima de um frame com voz (0-1)
+	MaxZCR     float64       // taxa de cruzamentos por zero acima disso é ruído, não voz
+	MinSpeech  time.Duration // voz contínua para considerar que a fala começou
+	Silence    time.Duration // silêncio após a fala que fecha o turno
+}
+
+// DefaultVADConfig valores para a entrada de 16 kHz do cliente
+func DefaultVADConfig() VADConfig {
+	return VADConfig{
+		SampleRate: 16000,
+		Frame:      20 * time.Millisecond,
+		Threshold:  0.02,
+		MaxZCR:     0.35,
+		MinSpeech:  150 * time.Millisecond,
+		Silence:    700 * time.Millisecond,
+	}
+}
+
+// VADConfigFromOptions aplica as opções do setup sobre o padrão
+func VADConfigFromOptions(opts *audio.VADOptions) VADConfig {
+	cfg := DefaultVADConfig()
+	if opts.SilenceMs > 0 {
+		cfg.Silence = time.Duration(opts.SilenceMs) * time.Millisecond
+	}
+	if opts.Threshold > 0 {
+		cfg.Threshold = opts.Threshold
+	}
+	return cfg
+}
+
+// VAD detector de atividade de voz por energia e zero-crossing rate.
+// Não é seguro para uso concorrente (uma instância por conexão).
+type VAD struct {
+	cfg        VADConfig
+	frameBytes int
+	pending    []byte // resto de frame do chunk anterior
+
+	voiced   time.Duration
+	silence  time.Duration
+	speaking bool
+}
+
+// NewVAD cria um VAD
+func NewVAD(cfg VADConfig) *VAD {
+	samples := int(int64(cfg.SampleRate) * int64(cfg.Frame) / int64(time.Second))
+	return &VAD{cfg: cfg, frameBytes: samples * 2}
+}
+
+// Process analisa um chunk de PCM. started indica que a fala começou neste chunk;
+// ended, que houve silêncio suficiente depois da fala (o detector volta ao início).
+func (v *VAD) Process(pcm []byte) (started, ended bool) {
+	v.pending = append(v.pending, pcm...)
+	for len(v.pending) >= v.frameBytes {
+		frame := v.pending[:v.frameBytes]
+		v.pending = v.pending[v.frameBytes:]
+
+		if v.isVoiced(frame) {
+			v.silence = 0
+			v.voiced += v.cfg.Frame
+			if !v.speaking && v.voiced >= v.cfg.MinSpeech {
+				v.speaking = true
+				started = true
+			}
+			continue
+		}
+
+		v.voiced = 0
+		if !v.speaking {
+			continue
+		}
+		v.silence += v.cfg.Frame
+		if v.silence >= v.cfg.Silence {
+			ended = true
+			v.Reset()
+		}
+	}
+	return started, ended
+}
+
+// Reset volta ao estado inicial (ex: turno fechado por audio_end)
+func (v *VAD) Reset() {
+	v.voiced = 0
+	v.silence = 0
+	v.speaking = false
+}
+
+func (v *VAD) isVoiced(frame []byte) bool {
+	n := len(frame) / 2
+	var energy float64
+	crossings := 0
+	prev := int16(0)
+	for i := 0; i < n; i++ {
+		s := int16(binary.LittleEndian.Uint16(frame[2*i:]))
+		x := float64(s) / 32768
+		energy += x * x
+		if i > 0 && (s >= 0) != (prev >= 0) {
+			crossings++
+		}
+		prev = s
+	}
+	rms := math.Sqrt(energy / float64(n))
+	zcr := float64(crossings) / float64(n)
+	return rms >= v.cfg.Threshold && zcr <= v.cfg.MaxZCR
+}
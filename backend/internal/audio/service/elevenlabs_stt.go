@@ -18,11 +18,15 @@ import (
 
 // ─── Factory ────────────────────────────────────────────────────
 
+// ElevenLabsSTTModel modelo de transcrição em tempo real (gravado em conversas.stt_provider)
+const ElevenLabsSTTModel = "scribe_v2_realtime"
+
 // ElevenLabsSTTFactory implements audio.STTSessionFactory.
 // Holds credentials and creates fresh sessions per speech turn.
 type ElevenLabsSTTFactory struct {
-	apiURL string
-	apiKey string
+	apiURL     string
+	apiKey     string
+	sampleRate int
 }
 
 func NewElevenLabsSTTFactory() (*ElevenLabsSTTFactory, error) {
@@ -31,10 +35,13 @@ func NewElevenLabsSTTFactory() (*ElevenLabsSTTFactory, error) {
 		return nil, fmt.Errorf("ELEVEN_API_KEY environment variable is required")
 	}
 
-	apiURL := "wss://api.elevenlabs.io/v1/speech-to-text/realtime?model_id=scribe_v2_realtime"
+	// O pipeline converte a entrada do cliente para audio.STTSampleRate antes do STT
+	sampleRate := audio.STTSampleRate
+	apiURL := fmt.Sprintf("wss://api.elevenlabs.io/v1/speech-to-text/realtime?model_id=%s&audio_format=pcm_%d", ElevenLabsSTTModel, sampleRate)
 	return &ElevenLabsSTTFactory{
-		apiURL: apiURL,
-		apiKey: apiKey,
+		apiURL:     apiURL,
+		apiKey:     apiKey,
+		sampleRate: sampleRate,
 	}, nil
 }
 
@@ -48,7 +55,7 @@ func (f *ElevenLabsSTTFactory) NewSession(ctx context.Context) (audio.STTSession
 		return nil, fmt.Errorf("[STT] falha ao conectar na ElevenLabs STT: %w", err)
 	}
 
-	session := &ElevenLabsSTTSession{conn: conn}
+	session := &ElevenLabsSTTSession{conn: conn, sampleRate: f.sampleRate}
 
 	// Esperar pelo evento session_started antes de retornar
 	if err := session.waitForSessionStarted(ctx); err != nil {
@@ -65,8 +72,9 @@ func (f *ElevenLabsSTTFactory) NewSession(ctx context.Context) (audio.STTSession
 // ElevenLabsSTTSession implements audio.STTSession.
 // Represents a single speech turn WebSocket connection.
 type ElevenLabsSTTSession struct {
-	conn      *websocket.Conn
-	closeOnce sync.Once
+	conn       *websocket.Conn
+	sampleRate int
+	closeOnce  sync.Once
 }
 
 // sttMessage represents any message from the ElevenLabs STT WebSocket.
@@ -120,7 +128,7 @@ func (s *ElevenLabsSTTSession) SendAudio(audioData []byte) error {
 	payload := map[string]interface{}{
 		"message_type":  "input_audio_chunk",
 		"audio_base_64": encoded,
-		"sample_rate":   16000,
+		"sample_rate":   s.sampleRate,
 		"commit":        false,
 	}
 
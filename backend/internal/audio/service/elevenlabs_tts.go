@@ -8,12 +8,21 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+
+	"extension-backend/internal/audio"
 )
 
+// ElevenLabsTTSModel modelo de voz (gravado em conversas.tts_provider)
+const ElevenLabsTTSModel = "eleven_multilingual_v2"
+
+// elevenLabsTTSURL endpoint de streaming; %s = voice ID
+const elevenLabsTTSURL = "https://api.elevenlabs.io/v1/text-to-speech/%s/stream"
+
 type ElevenLabsTTS struct {
-	apiURL string
-	apiKey string
+	voiceID string // voz padrão (ELEVEN_VOICE_ID)
+	apiKey  string
 }
 
 func NewElevenLabsTTS() (*ElevenLabsTTS, error) {
@@ -29,8 +38,8 @@ func NewElevenLabsTTS() (*ElevenLabsTTS, error) {
 	}
 
 	return &ElevenLabsTTS{
-		apiURL: fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s/stream", voiceID),
-		apiKey: apiKey,
+		voiceID: voiceID,
+		apiKey:  apiKey,
 	}, nil
 }
 
@@ -42,16 +51,53 @@ func (t *ElevenLabsTTS) Start(ctx context.Context) (<-chan []byte, error) {
 	panic("Use StreamText directly for ElevenLabs TTS")
 }
 
-// StreamText faz um request POST para ElevenLabs e envia os bytes recebidos continuamente pelo canal out.
+// Formats formatos de saída pedidos à ElevenLabs (audio.FormatTextToSpeechProvider)
+func (t *ElevenLabsTTS) Formats() []audio.AudioFormat {
+	return []audio.AudioFormat{
+		audio.DefaultOutputFormat,
+		{Encoding: audio.EncodingPCM16, SampleRate: 16000},
+		{Encoding: audio.EncodingPCM16, SampleRate: 22050},
+		{Encoding: audio.EncodingPCM16, SampleRate: 24000},
+		{Encoding: audio.EncodingPCM16, SampleRate: 44100},
+		{Encoding: audio.EncodingOpus, SampleRate: 48000},
+	}
+}
+
+// outputFormat nome do formato na API da ElevenLabs
+func outputFormat(f audio.AudioFormat) (name, accept string) {
+	switch f.Encoding {
+	case audio.EncodingPCM16:
+		return fmt.Sprintf("pcm_%d", f.SampleRate), "audio/pcm"
+	case audio.EncodingOpus:
+		return "opus_48000_64", "audio/ogg"
+	default:
+		return "mp3_44100_128", "audio/mpeg"
+	}
+}
+
+// StreamText faz um request POST para ElevenLabs e envia os bytes recebidos continuamente pelo canal out (MP3).
 func (t *ElevenLabsTTS) StreamText(ctx context.Context, text string, out chan<- []byte) error {
+	return t.StreamTextAs(ctx, text, audio.DefaultOutputFormat, out)
+}
+
+// StreamTextAs como StreamText, no formato pedido (um dos Formats)
+func (t *ElevenLabsTTS) StreamTextAs(ctx context.Context, text string, format audio.AudioFormat, out chan<- []byte) error {
+	return t.StreamTextWithVoice(ctx, text, "", format, out)
+}
+
+// StreamTextWithVoice como StreamTextAs, com outra voz (audio.VoiceTextToSpeechProvider)
+func (t *ElevenLabsTTS) StreamTextWithVoice(ctx context.Context, text string, voiceID string, format audio.AudioFormat, out chan<- []byte) error {
+	if voiceID == "" {
+		voiceID = t.voiceID
+	}
+	name, accept := outputFormat(format)
 	payload := map[string]interface{}{
 		"text":     text,
-		"model_id": "eleven_multilingual_v2",
+		"model_id": ElevenLabsTTSModel,
 		"voice_settings": map[string]interface{}{
 			"stability":        0.5,
 			"similarity_boost": 0.5,
 		},
-		"output_format": "mp3_44100_128",
 	}
 
 	body, err := json.Marshal(payload)
@@ -59,15 +105,14 @@ func (t *ElevenLabsTTS) StreamText(ctx context.Context, text string, out chan<-
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.apiURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(elevenLabsTTSURL, url.PathEscape(voiceID))+"?output_format="+name, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("xi-api-key", t.apiKey)
 	req.Header.Set("Content-Type", "application/json")
-	// Pedimos explicitly mp3 em baixa latência caso suportado
-	req.Header.Set("Accept", "audio/mpeg")
+	req.Header.Set("Accept", accept)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"extension-backend/internal/ai"
 	"extension-backend/internal/audio"
@@ -35,17 +36,16 @@ func NewGeminiLLM() (*GeminiLLM, error) {
 // GenerateStream wraps Gemini-2.0-flash using GenerateContentStream ensuring textual chunks
 // are piped incrementally to the TTS Engine (or WebSocket UI) for max speed.
 func (g *GeminiLLM) GenerateStream(ctx context.Context, history []audio.ConversationTurn, input string, out chan<- string) error {
-	systemPrompt := `Você é um tutor de aprendizado de idiomas em uma conversa de voz fluida e rápida.
-Regras:
-- Seja extremamente conciso. Responda em no máximo 1 a 2 sentenças curtas.
-- Fale naturalmente, sem usar formatação markdown (*, _, #) pois o texto vai direto para uma engine Text-To-Speech (TTS).
-- Foque na conversação prática. Responda de forma engajadora, não robótica.`
+	return g.GenerateStreamAs(ctx, audio.Persona{}, history, input, out)
+}
 
+// GenerateStreamAs como GenerateStream, com o tutor no papel do cenário (audio.PersonaLLMProvider)
+func (g *GeminiLLM) GenerateStreamAs(ctx context.Context, persona audio.Persona, history []audio.ConversationTurn, input string, out chan<- string) error {
 	var contents []*genai.Content
 	
 	contents = append(contents, &genai.Content{
 		Role:  "user",
-		Parts: []*genai.Part{{Text: systemPrompt}},
+		Parts: []*genai.Part{{Text: systemPrompt(persona)}},
 	})
 
 	contents = append(contents, &genai.Content{
@@ -87,3 +87,27 @@ Regras:
 
 	return nil
 }
+
+// systemPrompt instruções do tutor; a persona vem do catálogo de cenários (conteúdo nosso, não do usuário)
+func systemPrompt(persona audio.Persona) string {
+	var b strings.Builder
+	b.WriteString(`Você é um tutor de aprendizado de idiomas em uma conversa de voz fluida e rápida.
+Regras:
+- Seja extremamente conciso. Responda em no máximo 1 a 2 sentenças curtas.
+- Fale naturalmente, sem usar formatação markdown (*, _, #) pois o texto vai direto para uma engine Text-To-Speech (TTS).
+- Foque na conversação prática. Responda de forma engajadora, não robótica.`)
+
+	if persona.Language != "" {
+		fmt.Fprintf(&b, "\n- Converse sempre em %s, o idioma que o aluno está aprendendo, mesmo que ele use outro idioma.", persona.Language)
+	}
+	if persona.Level != "" {
+		fmt.Fprintf(&b, "\n- Use vocabulário e estruturas adequados ao nível CEFR %s.", persona.Level)
+	}
+	if persona.Prompt != "" {
+		fmt.Fprintf(&b, "\n\nCenário de role-play: %s\nFique no papel durante toda a conversa e comece pelo que a situação pede.", persona.Prompt)
+	}
+	if len(persona.TargetWords) > 0 {
+		fmt.Fprintf(&b, "\nCrie oportunidades naturais para o aluno usar estas palavras, sem dizê-las por ele: %s.", strings.Join(persona.TargetWords, ", "))
+	}
+	return b.String()
+}
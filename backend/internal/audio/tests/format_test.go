@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+
+	"github.com/gorilla/websocket"
+)
+
+// recordingSTTFactory guarda o PCM que chegou ao STT
+type recordingSTTFactory struct {
+	mu       sync.Mutex
+	received []byte
+}
+
+func (f *recordingSTTFactory) NewSession(ctx context.Context) (audio.STTSession, error) {
+	return &recordingSTTSession{factory: f}, nil
+}
+
+type recordingSTTSession struct {
+	factory *recordingSTTFactory
+}
+
+func (s *recordingSTTSession) SendAudio(data []byte) error {
+	s.factory.mu.Lock()
+	defer s.factory.mu.Unlock()
+	s.factory.received = append(s.factory.received, data...)
+	return nil
+}
+
+func (s *recordingSTTSession) Commit() error { return nil }
+
+func (s *recordingSTTSession) WaitForTranscript(ctx context.Context) (string, error) {
+	return "hello", nil
+}
+
+func (s *recordingSTTSession) Close() error { return nil }
+
+// pcmTTS gera 100 ms de PCM 16 kHz por frase; só sabe PCM 16 kHz além do MP3
+type pcmTTS struct{}
+
+func (m *pcmTTS) StreamText(ctx context.Context, text string, out chan<- []byte) error {
+	out <- []byte("mock-mp3")
+	return nil
+}
+
+func (m *pcmTTS) StreamTextAs(ctx context.Context, text string, format audio.AudioFormat, out chan<- []byte) error {
+	out <- make([]byte, 3200)
+	return nil
+}
+
+func (m *pcmTTS) Formats() []audio.AudioFormat {
+	return []audio.AudioFormat{audio.DefaultOutputFormat, {Encoding: audio.EncodingPCM16, SampleRate: 16000}}
+}
+
+// f32 gera PCM float32 48 kHz (senoide de 220 Hz)
+func f32(ms int) []byte {
+	n := 48 * ms
+	out := make([]byte, 4*n)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint32(out[4*i:], math.Float32bits(float32(0.3*math.Sin(2*math.Pi*220*float64(i)/48000))))
+	}
+	return out
+}
+
+func dialPipeline(t *testing.T, pipeline *processor.Pipeline) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade: %v", err)
+			return
+		}
+		pipeline.HandleWSConnection(r.Context(), conn, 1)
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestAudioPipeline_BinaryFramesWithResampling(t *testing.T) {
+	stt := &recordingSTTFactory{}
+	conn := dialPipeline(t, processor.NewPipeline(stt, &pcmTTS{}, &mockLLM{}, nil))
+
+	conn.WriteJSON(audio.WebsocketMessage{
+		Type:   "setup",
+		Input:  &audio.AudioFormat{Encoding: audio.EncodingPCMF32, SampleRate: 48000},
+		Output: &audio.AudioFormat{Encoding: audio.EncodingPCM16, SampleRate: 24000},
+		Binary: true,
+	})
+
+	var ack audio.WebsocketMessage
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if err := conn.ReadJSON(&ack); err != nil {
+		t.Fatalf("failed to read setup_ack: %v", err)
+	}
+	if ack.Type != "setup_ack" || ack.Output == nil || *ack.Output != (audio.AudioFormat{Encoding: audio.EncodingPCM16, SampleRate: 24000}) {
+		t.Fatalf("expected PCM 24 kHz to be negotiated, got %+v", ack)
+	}
+
+	// 100 ms de float32 48 kHz, cortado no meio de uma amostra
+	chunk := f32(100)
+	conn.WriteMessage(websocket.BinaryMessage, chunk[:1001])
+	conn.WriteMessage(websocket.BinaryMessage, chunk[1001:])
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	var audioBytes int
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("stream ended before tts_end: %v", err)
+		}
+		if msgType == websocket.BinaryMessage {
+			audioBytes += len(data)
+			continue
+		}
+		if strings.Contains(string(data), `"tts_end"`) {
+			break
+		}
+	}
+
+	stt.mu.Lock()
+	received := len(stt.received)
+	stt.mu.Unlock()
+	if received < 3190 || received > 3210 {
+		t.Errorf("expected ~3200 bytes of 16 kHz PCM at the STT, got %d", received)
+	}
+	if audioBytes < 4790 || audioBytes > 4810 {
+		t.Errorf("expected ~4800 bytes of 24 kHz PCM in binary frames, got %d", audioBytes)
+	}
+}
+
+func TestAudioPipeline_UnsupportedOutputFallsBackToMP3(t *testing.T) {
+	conn := dialPipeline(t, processor.NewPipeline(&mockSTTFactory{}, &mockTTS{}, &mockLLM{}, nil))
+
+	conn.WriteJSON(audio.WebsocketMessage{Type: "setup", Output: &audio.AudioFormat{Encoding: audio.EncodingOpus}})
+
+	var ack audio.WebsocketMessage
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if err := conn.ReadJSON(&ack); err != nil {
+		t.Fatalf("failed to read setup_ack: %v", err)
+	}
+	if ack.Output == nil || *ack.Output != audio.DefaultOutputFormat {
+		t.Errorf("expected MP3 fallback, got %+v", ack.Output)
+	}
+}
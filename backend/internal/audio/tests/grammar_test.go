@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+	"extension-backend/internal/audio/service"
+)
+
+// mockGrammar implements audio.GrammarChecker
+type mockGrammar struct {
+	corrections []audio.Correction
+	delay       time.Duration
+}
+
+func (m *mockGrammar) Check(ctx context.Context, utterance, language string) ([]audio.Correction, error) {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return m.corrections, nil
+}
+
+func TestGrammarChecker_ParsesAndFiltersCorrections(t *testing.T) {
+	provider := ai.NewFakeProvider(func(prompt string) (string, error) {
+		return "```json\n" + `{"corrections": [
+			{"original": " I goed ", "corrected": "I went", "explanation": "Passado irregular de go."},
+			{"original": "", "corrected": "the", "explanation": "sem trecho"},
+			{"original": "yesterday", "corrected": "Yesterday", "explanation": "maiúscula"}
+		]}` + "\n```", nil
+	})
+	checker := service.NewGrammarChecker(provider)
+
+	corrections, err := checker.Check(context.Background(), "I goed to the park yesterday", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(corrections) != 1 {
+		t.Fatalf("expected one correction, got %+v", corrections)
+	}
+	c := corrections[0]
+	if c.Original != "I goed" || c.Corrected != "I went" || c.Explanation != "Passado irregular de go." {
+		t.Errorf("unexpected correction: %+v", c)
+	}
+	if !strings.Contains(provider.Prompts()[0], "<<<UTTERANCE\nI goed to the park yesterday\n>>>") {
+		t.Errorf("expected utterance as a data block, got prompt:\n%s", provider.Prompts()[0])
+	}
+	if !strings.Contains(provider.Prompts()[0], "learner of English") {
+		t.Errorf("expected English as the default language, got prompt:\n%s", provider.Prompts()[0])
+	}
+
+	if _, err := checker.Check(context.Background(), "Yo soy cansado", "Español"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if prompt := provider.LastPrompt(); !strings.Contains(prompt, "learner of Español") || strings.Contains(prompt, "English") {
+		t.Errorf("expected the conversation language in the prompt, got:\n%s", prompt)
+	}
+}
+
+func TestAudioPipeline_SendsCorrectionsWithoutDelayingReply(t *testing.T) {
+	recorder := &memoryRecorder{ended: make(chan int, 1)}
+	grammar := &mockGrammar{
+		delay:       100 * time.Millisecond,
+		corrections: []audio.Correction{{Original: "I goed", Corrected: "I went", Explanation: "Passado irregular."}},
+	}
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "I goed home"}, &mockTTS{}, &mockLLM{}, nil).
+		WithRecorder(recorder, audio.Providers{}).
+		WithGrammar(grammar)
+
+	conn := dialPipeline(t, pipeline)
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	var order []string
+	var correction audio.WebsocketMessage
+	for correction.Type == "" {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read (got %v): %v", order, err)
+		}
+		order = append(order, msg.Type)
+		if msg.Type == "correction" {
+			correction = msg
+		}
+	}
+
+	// A resposta do tutor não espera a análise gramatical
+	if order[0] != "stt" || !containsType(order, "text") {
+		t.Errorf("expected reply before the correction, got %v", order)
+	}
+	if correction.Text != "I goed home" || len(correction.Corrections) != 1 {
+		t.Fatalf("unexpected correction message: %+v", correction)
+	}
+	// Turno 1 é a fala do usuário: a correção é gravada nele e volta com o id
+	if c := correction.Corrections[0]; c.ID != 100 || c.Corrected != "I went" {
+		t.Errorf("unexpected correction: %+v", c)
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.corrected[1]) != 1 {
+		t.Errorf("expected correction recorded on the user turn, got %+v", recorder.corrected)
+	}
+}
+
+func containsType(types []string, want string) bool {
+	for _, typ := range types {
+		if typ == want {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"extension-backend/internal/ai"
 	"extension-backend/internal/audio"
 	"extension-backend/internal/audio/processor"
+	"extension-backend/internal/events"
 
 	"github.com/gorilla/websocket"
 )
@@ -82,7 +85,7 @@ func TestAudioPipeline_FullTurn(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to upgrade: %v", err)
 		}
-		pipeline.HandleWSConnection(r.Context(), conn)
+		pipeline.HandleWSConnection(r.Context(), conn, 42)
 	}))
 	defer server.Close()
 
@@ -141,3 +144,178 @@ func TestAudioPipeline_FullTurn(t *testing.T) {
 		t.Errorf("Expected at least 1 tts_end event, got %d. Received: %v", received["tts_end"], received)
 	}
 }
+
+type quotaLLM struct{}
+
+func (m *quotaLLM) GenerateStream(ctx context.Context, history []audio.ConversationTurn, input string, out chan<- string) error {
+	return ai.ErrQuotaExceeded
+}
+
+// recordingPublisher guarda os eventos publicados
+type recordingPublisher struct {
+	published chan events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, e events.Event) error {
+	p.published <- e
+	return nil
+}
+
+func TestAudioPipeline_QuotaErrorIsPublishedForConnectionUser(t *testing.T) {
+	publisher := &recordingPublisher{published: make(chan events.Event, 1)}
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &mockTTS{}, &quotaLLM{}, nil).
+		WithEvents(publisher)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("failed to upgrade: %v", err)
+		}
+		pipeline.HandleWSConnection(r.Context(), conn, 42)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	for _, msg := range []audio.WebsocketMessage{
+		{Type: "setup"},
+		{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="},
+		{Type: "audio_end"},
+	} {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatalf("failed to send %s: %v", msg.Type, err)
+		}
+	}
+
+	select {
+	case e := <-publisher.published:
+		if e.Type != events.QuotaExceeded || e.UserID != 42 {
+			t.Errorf("expected quota event for user 42, got %s for user %d", e.Type, e.UserID)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("quota event was not published")
+	}
+}
+
+// blockingTTS entrega a primeira frase e trava na segunda até o turno ser cancelado
+type blockingTTS struct{}
+
+func (m *blockingTTS) StreamText(ctx context.Context, text string, out chan<- []byte) error {
+	if strings.HasPrefix(text, "First") {
+		out <- []byte("mock-mp3: " + text)
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type twoSentenceLLM struct{}
+
+func (m *twoSentenceLLM) GenerateStream(ctx context.Context, history []audio.ConversationTurn, input string, out chan<- string) error {
+	out <- "First sentence. "
+	out <- "Second sentence."
+	return nil
+}
+
+// memoryHistory audio.ConversationHistory em memória
+type memoryHistory struct {
+	mu    sync.Mutex
+	turns map[string][]audio.ConversationTurn // por conversa
+}
+
+func (h *memoryHistory) GetHistory(ctx context.Context, userID int, conversationID string) ([]audio.ConversationTurn, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]audio.ConversationTurn(nil), h.turns[conversationID]...), nil
+}
+
+func (h *memoryHistory) AppendTurn(ctx context.Context, userID int, conversationID string, role string, content string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.turns == nil {
+		h.turns = make(map[string][]audio.ConversationTurn)
+	}
+	h.turns[conversationID] = append(h.turns[conversationID], audio.ConversationTurn{Role: role, Content: content})
+	return nil
+}
+
+// conversations quantas conversas têm histórico
+func (h *memoryHistory) conversations() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.turns)
+}
+
+// only o histórico da única conversa
+func (h *memoryHistory) only() []audio.ConversationTurn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, turns := range h.turns {
+		return append([]audio.ConversationTurn(nil), turns...)
+	}
+	return nil
+}
+
+func TestAudioPipeline_InterruptCancelsTTSAndTruncatesHistory(t *testing.T) {
+	history := &memoryHistory{}
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &blockingTTS{}, &twoSentenceLLM{}, history)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("failed to upgrade: %v", err)
+		}
+		pipeline.HandleWSConnection(r.Context(), conn, 1)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	received := map[string]int{}
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("stream ended before tts_cancelled: %v (received %v)", err, received)
+		}
+		received[msg.Type]++
+		if msg.Type == "audio" && received["audio"] == 1 {
+			// Usuário volta a falar enquanto a segunda frase está no TTS
+			conn.WriteJSON(audio.WebsocketMessage{Type: "interrupt"})
+		}
+		if msg.Type == "tts_cancelled" {
+			break
+		}
+	}
+	if received["tts_end"] != 0 {
+		t.Errorf("expected no tts_end after interrupt, got %v", received)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		turns := history.only()
+		if len(turns) == 2 {
+			if turns[1].Role != "model" || turns[1].Content != "First sentence." {
+				t.Errorf("expected model turn truncated to the spoken sentence, got %+v", turns[1])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected user and model turns, got %+v", turns)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
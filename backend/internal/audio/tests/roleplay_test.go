@@ -0,0 +1,253 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+
+	"github.com/gorilla/websocket"
+)
+
+// mockCatalog implements audio.ScenarioCatalog
+type mockCatalog struct{}
+
+func (m *mockCatalog) GetScenario(ctx context.Context, id int) (*audio.Scenario, error) {
+	if id != 3 {
+		return nil, errors.New("scenario not found")
+	}
+	return &audio.Scenario{
+		ID:          3,
+		Slug:        "restaurant-order",
+		Title:       "Ordering at a restaurant",
+		Persona:     "You are a waiter.",
+		Language:    "en",
+		Level:       "A2",
+		VoiceID:     "waiterVoice",
+		TargetWords: []string{"menu", "order", "check in", "bill"},
+	}, nil
+}
+
+func (m *mockCatalog) GetLanguage(ctx context.Context, id int) (*audio.Language, error) {
+	switch id {
+	case 1:
+		return &audio.Language{ID: 1, Code: "en", Name: "English"}, nil
+	case 2:
+		return &audio.Language{ID: 2, Code: "es", Name: "Español"}, nil
+	}
+	return nil, errors.New("language not found")
+}
+
+// personaLLM implements audio.PersonaLLMProvider
+type personaLLM struct {
+	mockLLM
+	mu      sync.Mutex
+	persona audio.Persona
+}
+
+func (m *personaLLM) GenerateStreamAs(ctx context.Context, persona audio.Persona, history []audio.ConversationTurn, input string, out chan<- string) error {
+	m.mu.Lock()
+	m.persona = persona
+	m.mu.Unlock()
+	return m.GenerateStream(ctx, history, input, out)
+}
+
+// voiceTTS implements audio.VoiceTextToSpeechProvider
+type voiceTTS struct {
+	mockTTS
+	mu     sync.Mutex
+	voices []string
+}
+
+func (m *voiceTTS) StreamTextWithVoice(ctx context.Context, text string, voiceID string, format audio.AudioFormat, out chan<- []byte) error {
+	m.mu.Lock()
+	m.voices = append(m.voices, voiceID)
+	m.mu.Unlock()
+	return m.StreamText(ctx, text, out)
+}
+
+func TestAudioPipeline_ScenarioPersonaVoiceAndTargetWords(t *testing.T) {
+	recorder := &memoryRecorder{ended: make(chan int, 1)}
+	llm := &personaLLM{}
+	tts := &voiceTTS{}
+	factory := &mockSTTFactory{nextTranscript: "We checked in and I ordered from the menu"}
+	pipeline := processor.NewPipeline(factory, tts, llm, nil).
+		WithScenarios(&mockCatalog{}).
+		WithRecorder(recorder, audio.Providers{})
+
+	conn := dialPipeline(t, pipeline)
+	conn.WriteJSON(audio.WebsocketMessage{Type: "setup", ScenarioID: 3, LanguageID: 1})
+
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	var ack audio.WebsocketMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		t.Fatalf("failed to read setup_ack: %v", err)
+	}
+	if ack.Type != "setup_ack" || ack.Scenario == nil || ack.Scenario.Slug != "restaurant-order" || ack.Language == nil || ack.Language.Code != "en" {
+		t.Fatalf("unexpected setup_ack: %+v", ack)
+	}
+	if ack.Scenario.Persona != "" {
+		t.Errorf("persona prompt should not be sent to the client")
+	}
+	if ack.TargetWords == nil || len(ack.TargetWords.Remaining) != 4 || len(ack.TargetWords.Used) != 0 {
+		t.Errorf("unexpected initial progress: %+v", ack.TargetWords)
+	}
+
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	var progress *audio.TargetProgress
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if msg.Type == "target_words" {
+			progress = msg.TargetWords
+		}
+		if msg.Type == "tts_end" {
+			break
+		}
+	}
+	conn.Close()
+
+	// Por lema ("ordered" → order) e expressão em sequência ("checked in" → check in)
+	if progress == nil {
+		t.Fatal("expected a target_words message")
+	}
+	if want := []string{"menu", "order", "check in"}; !reflect.DeepEqual(progress.New, want) || !reflect.DeepEqual(progress.Used, want) {
+		t.Errorf("expected %v used, got %+v", want, progress)
+	}
+	if !reflect.DeepEqual(progress.Remaining, []string{"bill"}) {
+		t.Errorf("expected bill remaining, got %+v", progress.Remaining)
+	}
+
+	llm.mu.Lock()
+	persona := llm.persona
+	llm.mu.Unlock()
+	if persona.Prompt != "You are a waiter." || persona.Language != "English" || persona.Level != "A2" || len(persona.TargetWords) != 4 {
+		t.Errorf("unexpected persona: %+v", persona)
+	}
+
+	tts.mu.Lock()
+	voices := tts.voices
+	tts.mu.Unlock()
+	if len(voices) == 0 || voices[0] != "waiterVoice" {
+		t.Errorf("expected the scenario voice, got %v", voices)
+	}
+
+	<-recorder.ended
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.setup != (audio.ConversationSetup{ScenarioID: 3, Language: "en"}) {
+		t.Errorf("unexpected conversation setup: %+v", recorder.setup)
+	}
+	if len(recorder.targets[1]) != 3 {
+		t.Errorf("expected target words on the user turn, got %+v", recorder.targets)
+	}
+}
+
+func TestAudioPipeline_SetupRejectsScenarioInOtherLanguage(t *testing.T) {
+	pipeline := processor.NewPipeline(&mockSTTFactory{}, &voiceTTS{}, &personaLLM{}, nil).
+		WithScenarios(&mockCatalog{})
+
+	conn := dialPipeline(t, pipeline)
+	for _, setup := range []audio.WebsocketMessage{
+		{Type: "setup", ScenarioID: 3, LanguageID: 2},
+		{Type: "setup", ScenarioID: 404},
+		{Type: "setup", VoiceID: "../admin"},
+	} {
+		conn.WriteJSON(setup)
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if msg.Type != "error" {
+			t.Errorf("expected error for %+v, got %+v", setup, msg)
+		}
+	}
+}
+
+// historyLLM implements audio.PersonaLLMProvider e guarda quantos turnos de histórico recebeu
+type historyLLM struct {
+	mockLLM
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (m *historyLLM) GenerateStream(ctx context.Context, history []audio.ConversationTurn, input string, out chan<- string) error {
+	m.mu.Lock()
+	m.sizes = append(m.sizes, len(history))
+	m.mu.Unlock()
+	return m.mockLLM.GenerateStream(ctx, history, input, out)
+}
+
+func (m *historyLLM) GenerateStreamAs(ctx context.Context, persona audio.Persona, history []audio.ConversationTurn, input string, out chan<- string) error {
+	return m.GenerateStream(ctx, history, input, out)
+}
+
+func (m *historyLLM) last() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sizes[len(m.sizes)-1]
+}
+
+// speak envia um turno de fala e espera a resposta terminar
+func speak(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if msg.Type == "tts_end" {
+			return
+		}
+	}
+}
+
+func TestAudioPipeline_HistoryIsScopedByConnectionAndScenario(t *testing.T) {
+	history := &memoryHistory{}
+	llm := &historyLLM{}
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &mockTTS{}, llm, history).
+		WithScenarios(&mockCatalog{})
+
+	conn := dialPipeline(t, pipeline)
+	speak(t, conn)
+	speak(t, conn)
+	if got := llm.last(); got != 3 {
+		t.Fatalf("expected the second turn to see the first exchange, got %d turns", got)
+	}
+
+	// Setup com outro cenário: a conversa com o garçom não herda a anterior
+	conn.WriteJSON(audio.WebsocketMessage{Type: "setup", ScenarioID: 3})
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	var ack audio.WebsocketMessage
+	if err := conn.ReadJSON(&ack); err != nil || ack.Type != "setup_ack" {
+		t.Fatalf("expected setup_ack, got %+v (%v)", ack, err)
+	}
+	speak(t, conn)
+	if got := llm.last(); got != 1 {
+		t.Errorf("expected a new scenario to start without history, got %d turns", got)
+	}
+
+	// Outra conexão (outra aba) do mesmo usuário também começa do zero
+	other := dialPipeline(t, pipeline)
+	speak(t, other)
+	if got := llm.last(); got != 1 {
+		t.Errorf("expected a new connection to start without history, got %d turns", got)
+	}
+	if got := history.conversations(); got != 3 {
+		t.Errorf("expected 3 separate histories, got %d", got)
+	}
+}
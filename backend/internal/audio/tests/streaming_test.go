@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+
+	"github.com/gorilla/websocket"
+)
+
+// slowFirstTTS demora na primeira frase, para provar que o áudio sai na ordem das frases
+type slowFirstTTS struct {
+	started chan string
+}
+
+func (m *slowFirstTTS) StreamText(ctx context.Context, text string, out chan<- []byte) error {
+	m.started <- text
+	if strings.HasPrefix(text, "First") {
+		time.Sleep(50 * time.Millisecond)
+	}
+	out <- []byte(text)
+	return nil
+}
+
+// waitingLLM só termina a resposta depois que o TTS começou a primeira frase
+type waitingLLM struct {
+	ttsStarted <-chan string
+}
+
+func (m *waitingLLM) GenerateStream(ctx context.Context, history []audio.ConversationTurn, input string, out chan<- string) error {
+	out <- "First sen"
+	out <- "tence. Sec"
+	select {
+	case <-m.ttsStarted:
+	case <-time.After(2 * time.Second):
+		return errors.New("TTS did not start before the response finished")
+	}
+	out <- "ond sentence."
+	return nil
+}
+
+func TestAudioPipeline_StreamsSentencesToTTSInOrder(t *testing.T) {
+	tts := &slowFirstTTS{started: make(chan string, 10)}
+
+	metrics := processor.NewMetrics()
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, tts, &waitingLLM{ttsStarted: tts.started}, nil).
+		WithMetrics(metrics)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("failed to upgrade: %v", err)
+		}
+		pipeline.HandleWSConnection(r.Context(), conn, 1)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	var played []string
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("stream ended before tts_end: %v (played %v)", err, played)
+		}
+		if msg.Type == "audio" {
+			chunk, _ := base64.StdEncoding.DecodeString(msg.Audio)
+			played = append(played, string(chunk))
+		}
+		if msg.Type == "tts_end" {
+			break
+		}
+	}
+
+	if want := []string{"First sentence.", "Second sentence."}; strings.Join(played, "|") != strings.Join(want, "|") {
+		t.Errorf("expected audio in sentence order %v, got %v", want, played)
+	}
+	if metrics.Turns.Load() != 1 || metrics.LastTTFAMillis.Load() < 0 {
+		t.Errorf("expected one time-to-first-audio observation, got %d", metrics.Turns.Load())
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+)
+
+// memoryRecorder audio.TranscriptRecorder em memória
+type memoryRecorder struct {
+	mu        sync.Mutex
+	started   int
+	providers audio.Providers
+	setup     audio.ConversationSetup
+	turns     []audio.RecordedTurn
+	corrected map[int][]audio.Correction // turno → correções
+	targets   map[int][]string           // turno → palavras-alvo
+	ended     chan int
+}
+
+func (r *memoryRecorder) StartConversation(ctx context.Context, userID int, providers audio.Providers, setup audio.ConversationSetup) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started++
+	r.providers = providers
+	r.setup = setup
+	return 99, nil
+}
+
+func (r *memoryRecorder) RecordTurn(ctx context.Context, conversationID int, turn audio.RecordedTurn) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.turns = append(r.turns, turn)
+	return len(r.turns), nil
+}
+
+func (r *memoryRecorder) RecordCorrections(ctx context.Context, turnID int, corrections []audio.Correction) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.corrected == nil {
+		r.corrected = map[int][]audio.Correction{}
+	}
+	r.corrected[turnID] = append(r.corrected[turnID], corrections...)
+	ids := make([]int, len(corrections))
+	for i := range ids {
+		ids[i] = 100*turnID + i
+	}
+	return ids, nil
+}
+
+func (r *memoryRecorder) RecordTargetWords(ctx context.Context, turnID int, words []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.targets == nil {
+		r.targets = map[int][]string{}
+	}
+	r.targets[turnID] = append(r.targets[turnID], words...)
+	return nil
+}
+
+func (r *memoryRecorder) EndConversation(ctx context.Context, conversationID int) error {
+	r.ended <- conversationID
+	return nil
+}
+
+func TestAudioPipeline_RecordsTranscriptWithTimings(t *testing.T) {
+	recorder := &memoryRecorder{ended: make(chan int, 1)}
+	providers := audio.Providers{STT: "stt-model", LLM: "llm-model", TTS: "tts-model"}
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &mockTTS{}, &mockLLM{}, nil).
+		WithRecorder(recorder, providers)
+
+	conn := dialPipeline(t, pipeline)
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: "dGVzdC1hdWRpby1ieXRlcw=="})
+	conn.WriteJSON(audio.WebsocketMessage{Type: "audio_end"})
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if msg.Type == "tts_end" {
+			break
+		}
+	}
+	conn.Close()
+
+	select {
+	case id := <-recorder.ended:
+		if id != 99 {
+			t.Errorf("expected conversation 99 to end, got %d", id)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("conversation was not ended on disconnect")
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.started != 1 || recorder.providers != providers {
+		t.Errorf("expected one conversation with %+v, got %d with %+v", providers, recorder.started, recorder.providers)
+	}
+	if len(recorder.turns) != 2 {
+		t.Fatalf("expected user and model turns, got %+v", recorder.turns)
+	}
+	user, model := recorder.turns[0], recorder.turns[1]
+	if user.Role != "user" || user.Content != "hello" {
+		t.Errorf("unexpected user turn: %+v", user)
+	}
+	if model.Role != "model" || model.Content != "ai says: hello done." || model.Interrupted {
+		t.Errorf("unexpected model turn: %+v", model)
+	}
+	if model.TTFA <= 0 || model.Duration < model.TTFA || !model.StartedAt.Equal(user.StartedAt) {
+		t.Errorf("unexpected model timings: %+v", model)
+	}
+}
+
+func TestAudioPipeline_ConnectionWithoutTurnsIsNotRecorded(t *testing.T) {
+	recorder := &memoryRecorder{ended: make(chan int, 1)}
+	pipeline := processor.NewPipeline(&mockSTTFactory{}, &mockTTS{}, &mockLLM{}, nil).
+		WithRecorder(recorder, audio.Providers{})
+
+	conn := dialPipeline(t, pipeline)
+	conn.WriteJSON(audio.WebsocketMessage{Type: "setup"})
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	var ack audio.WebsocketMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		t.Fatalf("failed to read setup_ack: %v", err)
+	}
+	conn.Close()
+
+	select {
+	case <-recorder.ended:
+		t.Fatal("empty connection should not create a conversation")
+	case <-time.After(200 * time.Millisecond):
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.started != 0 {
+		t.Errorf("expected no conversation, got %d", recorder.started)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/audio/processor"
+
+	"github.com/gorilla/websocket"
+)
+
+// pcm gera PCM 16 kHz 16-bit: senoide de 220 Hz com amplitude amp (0 = silêncio)
+func pcm(ms int, amp float64) []byte {
+	n := 16 * ms
+	out := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		s := amp * math.Sin(2*math.Pi*220*float64(i)/16000)
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(s*32767)))
+	}
+	return out
+}
+
+func TestVAD_DetectsEndOfSpeechAfterSilence(t *testing.T) {
+	cfg := processor.DefaultVADConfig()
+	cfg.Silence = 300 * time.Millisecond
+	vad := processor.NewVAD(cfg)
+
+	if started, ended := vad.Process(pcm(200, 0)); started || ended {
+		t.Fatalf("silence alone must not start or end speech")
+	}
+	if started, _ := vad.Process(pcm(250, 0.3)); !started {
+		t.Fatalf("expected speech to start")
+	}
+	if _, ended := vad.Process(pcm(200, 0)); ended {
+		t.Fatalf("speech ended before the silence window")
+	}
+	if _, ended := vad.Process(pcm(120, 0)); !ended {
+		t.Errorf("expected end of speech after 300ms of silence")
+	}
+}
+
+func TestVAD_IgnoresLowEnergyNoise(t *testing.T) {
+	vad := processor.NewVAD(processor.DefaultVADConfig())
+	if started, _ := vad.Process(pcm(500, 0.005)); started {
+		t.Errorf("low-energy noise must not count as speech")
+	}
+}
+
+func TestAudioPipeline_VADClosesTurnWithoutAudioEnd(t *testing.T) {
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &mockTTS{}, &mockLLM{}, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Fatalf("failed to upgrade: %v", err)
+		}
+		pipeline.HandleWSConnection(r.Context(), conn, 1)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.WriteJSON(audio.WebsocketMessage{Type: "setup", VAD: &audio.VADOptions{SilenceMs: 300}})
+	for _, chunk := range [][]byte{pcm(100, 0.3), pcm(100, 0.3), pcm(100, 0.3), pcm(200, 0), pcm(200, 0)} {
+		conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: base64.StdEncoding.EncodeToString(chunk)})
+	}
+
+	received := map[string]int{}
+	for received["stt"] == 0 {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		var msg audio.WebsocketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("turn was not closed by the VAD: %v (received %v)", err, received)
+		}
+		received[msg.Type]++
+	}
+	if received["speech_end"] != 1 {
+		t.Errorf("expected speech_end before stt, got %v", received)
+	}
+}
+
+func TestAudioPipeline_InvalidSetupKeepsPreviousConfig(t *testing.T) {
+	pipeline := processor.NewPipeline(&mockSTTFactory{nextTranscript: "hello"}, &mockTTS{}, &mockLLM{}, nil)
+	conn := dialPipeline(t, pipeline)
+
+	// Setup com VAD e formato de entrada inválido: erro, e nada muda (VAD continua desligado)
+	conn.WriteJSON(audio.WebsocketMessage{
+		Type:  "setup",
+		VAD:   &audio.VADOptions{SilenceMs: 300},
+		Input: &audio.AudioFormat{Encoding: "mp3", SampleRate: 16000},
+	})
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	var msg audio.WebsocketMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "error" {
+		t.Fatalf("expected error for the invalid input format, got %+v, %v", msg, err)
+	}
+
+	for _, chunk := range [][]byte{pcm(100, 0.3), pcm(100, 0.3), pcm(100, 0.3), pcm(200, 0), pcm(200, 0)} {
+		conn.WriteJSON(audio.WebsocketMessage{Type: "audio", Audio: base64.StdEncoding.EncodeToString(chunk)})
+	}
+	conn.SetReadDeadline(time.Now().Add(700 * time.Millisecond))
+	if err := conn.ReadJSON(&msg); err == nil {
+		t.Fatalf("expected no VAD turn after a rejected setup, got %+v", msg)
+	}
+}
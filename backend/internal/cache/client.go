@@ -62,18 +62,75 @@ func (c *Client) Delete(ctx context.Context, keys ...string) error {
 	return c.rdb.Del(ctx, keys...).Err()
 }
 
-// DeleteByPattern remove chaves que correspondem ao padrão
-func (c *Client) DeleteByPattern(ctx context.Context, pattern string) error {
-	iter := c.rdb.Scan(ctx, 0, pattern, 0).Iterator()
-	var keys []string
-	for iter.Next(ctx) {
-		keys = append(keys, iter.Val())
-	}
-	if err := iter.Err(); err != nil {
-		return err
-	}
-	if len(keys) > 0 {
-		return c.rdb.Del(ctx, keys...).Err()
+// SetTagged armazena o valor e registra a chave nos sets de tag, numa única transação.
+// Cada tag expira junto com a entrada mais recente que a referencia.
+func (c *Client) SetTagged(ctx context.Context, key string, value string, ttl time.Duration, tags ...string) error {
+	_, err := c.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, key, value, ttl)
+		for _, tag := range tags {
+			p.SAdd(ctx, tag, key)
+			p.Expire(ctx, tag, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// invalidateTagsScript lê e apaga os sets de tag e as chaves registradas neles
+// atomicamente: um SetTagged entre a leitura e o UNLINK não perde a chave nova
+// (ou entra no set antes do script e é apagada, ou cria um set novo depois).
+// As chaves saem em lotes para não estourar o limite de argumentos do unpack.
+var invalidateTagsScript = redis.NewScript(`
+local deleted = {}
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call('SMEMBERS', tag)
+	for i = 1, #keys, 1000 do
+		redis.call('UNLINK', unpack(keys, i, math.min(i + 999, #keys)))
+	end
+	for _, key in ipairs(keys) do
+		deleted[#deleted + 1] = key
+	end
+	redis.call('UNLINK', tag)
+end
+return deleted
+`)
+
+// InvalidateTags remove as chaves registradas nas tags e as próprias tags
+// num script Lua atômico (sem SCAN no keyspace)
+func (c *Client) InvalidateTags(ctx context.Context, tags ...string) error {
+	_, err := c.InvalidateTagsKeys(ctx, tags...)
+	return err
+}
+
+// InvalidateTagsKeys como InvalidateTags, retornando as chaves apagadas
+func (c *Client) InvalidateTagsKeys(ctx context.Context, tags ...string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
 	}
-	return nil
+	return invalidateTagsScript.Run(ctx, c.rdb, tags).StringSlice()
+}
+
+// SetNX armazena o valor apenas se a chave não existir
+func (c *Client) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return c.rdb.SetNX(ctx, key, value, ttl).Result()
+}
+
+// deleteIfEqualsScript apaga a chave só se ela ainda guardar o valor informado
+var deleteIfEqualsScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// DeleteIfEquals apaga a chave só se o valor for o informado (compare-and-delete atômico).
+// Usado para soltar travas: quem perdeu a trava por expiração não apaga a do próximo dono.
+func (c *Client) DeleteIfEquals(ctx context.Context, key, value string) (bool, error) {
+	n, err := deleteIfEqualsScript.Run(ctx, c.rdb, []string{key}, value).Int()
+	return n == 1, err
+}
+
+// Redis expõe o client go-redis para módulos que usam recursos além de cache (streams, pub/sub)
+func (c *Client) Redis() *redis.Client {
+	return c.rdb
 }
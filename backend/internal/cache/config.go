@@ -1,6 +1,10 @@
 package cache
 
-import "time"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 // TTLs padrão para diferentes recursos
 const (
@@ -8,3 +12,11 @@ const (
 	ShortTTL   = 1 * time.Minute
 	LongTTL    = 15 * time.Minute
 )
+
+// MemoryBytesFromEnv limite do cache em memória (CACHE_L1_MAX_BYTES, em bytes)
+func MemoryBytesFromEnv() int {
+	if v, err := strconv.Atoi(os.Getenv("CACHE_L1_MAX_BYTES")); err == nil && v > 0 {
+		return v
+	}
+	return DefaultMemoryBytes
+}
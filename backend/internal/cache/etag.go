@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETag calcula o ETag forte do corpo da resposta
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified indica se o If-None-Match da request casa com o ETag
+// (comparação fraca, como manda a RFC 9110 para If-None-Match)
+func notModified(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter segura status e corpo até o middleware decidir o que enviar
+// (304, ETag, ...). Os headers vão direto para o ResponseWriter original.
+type bufferedWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       []byte
+}
+
+func newBufferedWriter(w http.ResponseWriter) *bufferedWriter {
+	return &bufferedWriter{ResponseWriter: w, statusCode: http.StatusOK}
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.statusCode = code
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
+
+func (w *bufferedWriter) ok() bool {
+	return w.statusCode >= 200 && w.statusCode < 300
+}
+
+// send envia a resposta guardada
+func (w *bufferedWriter) send() {
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	w.ResponseWriter.Write(w.body)
+}
+
+// captureWriter grava status, headers e corpo sem tocar em nenhuma conexão:
+// o handler roda uma vez e a resposta é repassada a várias requests
+type captureWriter struct {
+	header     http.Header
+	statusCode int
+	body       []byte
+}
+
+func newCaptureWriter() *captureWriter {
+	return &captureWriter{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (w *captureWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	w.statusCode = code
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
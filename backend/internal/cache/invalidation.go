@@ -1,29 +1,106 @@
 package cache
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"net/url"
+	"time"
+
+	"extension-backend/internal/events"
 )
 
-// InvalidateOn cria um middleware que invalida cache após mutações (POST/PUT/DELETE)
-func (c *Client) InvalidateOn(patterns ...string) func(http.Handler) http.Handler {
+// InvalidateOn cria um middleware que, após uma mutação bem-sucedida (POST/PUT/PATCH/DELETE),
+// invalida o cache dos recursos informados (prefixos do Middleware) do usuário da request.
+// A resposta só é enviada depois da invalidação (um GET logo em seguida já vê o dado novo)
+// e leva o ETag da nova listagem do primeiro recurso, se registrada com WithRepresentation.
+func (c *HTTPCache) InvalidateOn(resources ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			next.ServeHTTP(w, r)
-
-			// Invalida cache após mutações bem-sucedidas
-			if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodDelete {
-				go func() {
-					ctx := r.Context()
-					for _, pattern := range patterns {
-						if err := c.DeleteByPattern(ctx, pattern); err != nil {
-							log.Printf("[Cache] Failed to invalidate pattern %s: %v", pattern, err)
-						} else {
-							log.Printf("[Cache] Invalidated pattern %s", pattern)
-						}
-					}
-				}()
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := newBufferedWriter(w)
+			next.ServeHTTP(bw, r)
+			defer bw.send()
+			if !bw.ok() {
+				return
+			}
+
+			userID, ok := c.userOf(r)
+			if !ok {
+				return
+			}
+			// O ctx da request pode já estar cancelado (cliente desconectou)
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), writeTimeout)
+			defer cancel()
+			if err := c.Invalidate(ctx, userID, resources...); err != nil {
+				log.Printf("[Cache] Failed to invalidate %v for user %d: %v", resources, userID, err)
+				return
+			}
+			if etag, ok := c.refresh(r, userID, resources[0]); ok {
+				w.Header().Set("ETag", etag)
 			}
 		})
 	}
 }
+
+// representation GET de listagem de um recurso, regerado depois das mutações
+type representation struct {
+	path string
+	ttl  time.Duration
+	get  http.Handler
+}
+
+// WithRepresentation registra o GET de listagem do recurso (o mesmo handler e TTL do
+// Middleware da rota). As mutações do recurso com InvalidateOn regravam essa listagem
+// para o usuário e respondem com o ETag dela, o mesmo que o próximo GET devolve.
+func (c *HTTPCache) WithRepresentation(resource, path string, ttl time.Duration, get http.HandlerFunc) *HTTPCache {
+	c.representations[resource] = representation{path: path, ttl: ttl, get: get}
+	return c
+}
+
+// refresh roda o GET de listagem do recurso (sem query) com a autenticação da
+// mutação, grava a entrada nova e retorna o ETag dela
+func (c *HTTPCache) refresh(r *http.Request, userID int, resource string) (string, bool) {
+	rep, ok := c.representations[resource]
+	if !ok {
+		return "", false
+	}
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	get.URL = &url.URL{Path: rep.path}
+	get.RequestURI = rep.path
+	get.Body = http.NoBody
+	get.ContentLength = 0
+	get.Header.Del("Content-Type")
+
+	res := c.fill(rep.get, get, buildKey(resource, userID, get, nil), resource, userID, rep.ttl)
+	if !res.ok() {
+		return "", false
+	}
+	return ETag(res.body), true
+}
+
+// Invalidate apaga as entradas dos recursos de um usuário
+func (c *HTTPCache) Invalidate(ctx context.Context, userID int, resources ...string) error {
+	tags := make([]string, len(resources))
+	for i, res := range resources {
+		tags[i] = Tag(res, userID)
+	}
+	if err := c.store.InvalidateTags(ctx, tags...); err != nil {
+		return err
+	}
+	log.Printf("[Cache] Invalidated %v", tags)
+	return nil
+}
+
+// InvalidateOnEvent apaga os recursos do usuário do evento a cada evento do tipo
+// (mudanças feitas fora de uma request, ex: tradução salva em background)
+func (c *HTTPCache) InvalidateOnEvent(bus events.Bus, eventType string, resources ...string) {
+	bus.Subscribe(eventType, "cache-invalidator", func(ctx context.Context, e events.Event) error {
+		return c.Invalidate(ctx, e.UserID, resources...)
+	})
+}
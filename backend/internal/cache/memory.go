@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrMiss chave ausente ou expirada no MemoryStore
+var ErrMiss = errors.New("cache miss")
+
+// DefaultMemoryBytes limite padrão do MemoryStore (CACHE_L1_MAX_BYTES)
+const DefaultMemoryBytes = 32 << 20
+
+type memoryItem struct {
+	key     string
+	value   string
+	expires time.Time
+	tags    []string
+}
+
+// MemoryStore Store em memória com LRU limitado por bytes. É o L1 na frente
+// do Redis, ou o único nível quando o Redis não está disponível.
+type MemoryStore struct {
+	mu       sync.Mutex
+	maxBytes int
+	bytes    int
+	ll       *list.List
+	items    map[string]*list.Element
+	tags     map[string]map[string]struct{}
+}
+
+// NewMemoryStore cria um MemoryStore que guarda até maxBytes de valores
+func NewMemoryStore(maxBytes int) *MemoryStore {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMemoryBytes
+	}
+	return &MemoryStore{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		tags:     make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implementa Store
+func (m *MemoryStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return "", ErrMiss
+	}
+	item := el.Value.(*memoryItem)
+	if time.Now().After(item.expires) {
+		m.remove(el)
+		return "", ErrMiss
+	}
+	m.ll.MoveToFront(el)
+	return item.value, nil
+}
+
+// SetTagged implementa Store. Valores maiores que o limite inteiro não são guardados.
+func (m *MemoryStore) SetTagged(ctx context.Context, key string, value string, ttl time.Duration, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		m.remove(el)
+	}
+	if len(value) > m.maxBytes {
+		return nil
+	}
+
+	item := &memoryItem{key: key, value: value, expires: time.Now().Add(ttl), tags: tags}
+	m.items[key] = m.ll.PushFront(item)
+	m.bytes += len(value)
+	for _, tag := range tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = make(map[string]struct{})
+		}
+		m.tags[tag][key] = struct{}{}
+	}
+
+	for m.bytes > m.maxBytes {
+		m.remove(m.ll.Back())
+	}
+	return nil
+}
+
+// InvalidateTags implementa Store
+func (m *MemoryStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			if el, ok := m.items[key]; ok {
+				m.remove(el)
+			}
+		}
+		delete(m.tags, tag)
+	}
+	return nil
+}
+
+// Delete remove chaves (usado pelo TieredStore após invalidar o Redis)
+func (m *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := m.items[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+// Len número de entradas guardadas
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+func (m *MemoryStore) remove(el *list.Element) {
+	item := m.ll.Remove(el).(*memoryItem)
+	delete(m.items, item.key)
+	m.bytes -= len(item.value)
+	for _, tag := range item.tags {
+		if keys := m.tags[tag]; keys != nil {
+			delete(keys, item.key)
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
@@ -1,34 +1,80 @@
 package cache
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/sync/singleflight"
 )
 
-// cachedResponseWriter captura a resposta para armazenar em cache
-type cachedResponseWriter struct {
-	http.ResponseWriter
-	statusCode int
-	body       *bytes.Buffer
+// writeTimeout tempo máximo para gravar uma resposta no store
+const writeTimeout = 2 * time.Second
+
+// handlerTimeout tempo máximo do handler quando ele roda desacoplado da request
+// (líder de um MISS coalescido ou revalidação em background)
+const handlerTimeout = 30 * time.Second
+
+// UserFunc retorna o usuário autenticado da request. As chaves de cache são
+// sempre separadas por usuário; sem usuário a request não usa o cache.
+type UserFunc func(r *http.Request) (int, bool)
+
+// HTTPCache middlewares de cache HTTP (GET) e invalidação sobre um Store
+type HTTPCache struct {
+	store           Store
+	user            UserFunc
+	stale           time.Duration
+	group           singleflight.Group
+	representations map[string]representation
+}
+
+// NewHTTPCache cria o cache HTTP; user normalmente lê as claims do middleware.Auth
+func NewHTTPCache(store Store, user UserFunc) *HTTPCache {
+	return &HTTPCache{store: store, user: user, stale: ShortTTL, representations: make(map[string]representation)}
+}
+
+// WithStaleWhileRevalidate define por quanto tempo, depois do TTL, a entrada
+// antiga ainda é servida enquanto uma única request a revalida (0 desliga)
+func (c *HTTPCache) WithStaleWhileRevalidate(d time.Duration) *HTTPCache {
+	c.stale = d
+	return c
+}
+
+// entry resposta guardada no store, com o ETag calculado na gravação.
+// FreshUntil (unix ms) marca o fim do TTL; depois disso a entrada é STALE.
+type entry struct {
+	ETag        string `json:"etag"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+	FreshUntil  int64  `json:"fresh_until,omitempty"`
+}
+
+func (e entry) fresh(now time.Time) bool {
+	return e.FreshUntil == 0 || now.UnixMilli() < e.FreshUntil
 }
 
-func (w *cachedResponseWriter) WriteHeader(code int) {
-	w.statusCode = code
-	w.ResponseWriter.WriteHeader(code)
+// result resposta capturada de uma execução do handler
+type result struct {
+	status int
+	header http.Header
+	body   []byte
 }
 
-func (w *cachedResponseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
+func (res *result) ok() bool {
+	return res.status >= 200 && res.status < 300
 }
 
-// Middleware cria um middleware HTTP de cache para uma rota específica
-func (c *Client) Middleware(prefix string, ttl time.Duration) func(http.Handler) http.Handler {
+// Middleware cria um middleware HTTP de cache para uma rota específica.
+// vary lista headers da request que mudam a resposta: entram na chave e no header Vary.
+func (c *HTTPCache) Middleware(prefix string, ttl time.Duration, vary ...string) func(http.Handler) http.Handler {
+	varyHeader := strings.Join(append([]string{"Authorization", "Cookie"}, vary...), ", ")
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Só cacheia GET
@@ -37,51 +83,158 @@ func (c *Client) Middleware(prefix string, ttl time.Duration) func(http.Handler)
 				return
 			}
 
+			// Sem usuário não há escopo seguro para a chave
+			userID, ok := c.userOf(r)
+			if !ok {
+				w.Header().Set("X-Cache", "BYPASS")
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			ctx := r.Context()
-			key := buildKey(prefix, r)
-
-			// Tenta buscar do cache
-			cached, err := c.Get(ctx, key)
-			if err == nil {
-				log.Printf("[Cache] HIT %s", key)
-				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("X-Cache", "HIT")
-				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(cached))
+			key := buildKey(prefix, userID, r, vary)
+			w.Header().Set("Vary", varyHeader)
+			w.Header().Set("Cache-Control", "private, no-cache")
+
+			// Tenta buscar do cache; entrada vencida ainda é servida e revalidada em background
+			if e, ok := c.lookup(ctx, key); ok {
+				if e.fresh(time.Now()) {
+					log.Printf("[Cache] HIT %s", key)
+					w.Header().Set("X-Cache", "HIT")
+				} else {
+					log.Printf("[Cache] STALE %s", key)
+					w.Header().Set("X-Cache", "STALE")
+					c.revalidate(next, r, key, prefix, userID, ttl)
+				}
+				respond(w, r, e)
 				return
 			}
 
-			// Cache MISS - executa handler e captura resposta
+			// Cache MISS - requests simultâneas para a mesma chave esperam
+			// uma única execução do handler
 			log.Printf("[Cache] MISS %s", key)
-			recorder := &cachedResponseWriter{
-				ResponseWriter: w,
-				statusCode:     http.StatusOK,
-				body:           &bytes.Buffer{},
-			}
+			v, _, _ := c.group.Do(key, func() (any, error) {
+				return c.fill(next, r, key, prefix, userID, ttl), nil
+			})
+			res := v.(*result)
 
-			recorder.Header().Set("X-Cache", "MISS")
-			next.ServeHTTP(recorder, r)
-
-			// Só cacheia respostas de sucesso
-			if recorder.statusCode >= 200 && recorder.statusCode < 300 {
-				body := recorder.body.String()
-				go func() {
-					// Usa context.Background() pois o ctx do request é cancelado após a resposta
-					if err := c.Set(context.Background(), key, body, ttl); err != nil {
-						log.Printf("[Cache] Failed to cache %s: %v", key, err)
-					}
-				}()
+			for k, values := range res.header {
+				w.Header()[k] = values
+			}
+			w.Header().Set("X-Cache", "MISS")
+			if !res.ok() {
+				w.WriteHeader(res.status)
+				w.Write(res.body)
+				return
 			}
+			respond(w, r, entry{ETag: ETag(res.body), ContentType: res.header.Get("Content-Type"), Body: string(res.body)})
 		})
 	}
 }
 
-// buildKey gera a chave de cache baseada no path + query params
-func buildKey(prefix string, r *http.Request) string {
+// fill executa o handler desacoplado do ctx da request (o líder pode desconectar
+// com outras requests esperando) e grava a resposta se for de sucesso
+func (c *HTTPCache) fill(next http.Handler, r *http.Request, key, prefix string, userID int, ttl time.Duration) *result {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), handlerTimeout)
+	defer cancel()
+
+	rec := newCaptureWriter()
+	next.ServeHTTP(rec, r.Clone(ctx))
+	res := &result{status: rec.statusCode, header: rec.header, body: rec.body}
+	if !res.ok() {
+		return res
+	}
+
+	e := entry{
+		ETag:        ETag(res.body),
+		ContentType: res.header.Get("Content-Type"),
+		Body:        string(res.body),
+		FreshUntil:  time.Now().Add(ttl).UnixMilli(),
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return res
+	}
+	wctx, cancelWrite := context.WithTimeout(ctx, writeTimeout)
+	defer cancelWrite()
+	if err := c.store.SetTagged(wctx, key, string(data), ttl+c.stale, Tag(prefix, userID)); err != nil {
+		log.Printf("[Cache] Failed to cache %s: %v", key, err)
+	}
+	return res
+}
+
+// revalidate atualiza uma entrada STALE em background; só uma revalidação por
+// chave roda de cada vez, as demais requests seguem servindo a entrada antiga
+func (c *HTTPCache) revalidate(next http.Handler, r *http.Request, key, prefix string, userID int, ttl time.Duration) {
+	detached := detach(r)
+	c.group.DoChan("refresh:"+key, func() (any, error) {
+		return c.fill(next, detached, key, prefix, userID, ttl), nil
+	})
+}
+
+// detach copia a request para rodar depois que ela terminar. O chi devolve o
+// route context ao pool no fim da request e o reusa na próxima, então os
+// parâmetros de URL ({id}) são copiados para um route context próprio.
+func detach(r *http.Request) *http.Request {
+	ctx := context.WithoutCancel(r.Context())
+	if rctx := chi.RouteContext(ctx); rctx != nil {
+		fresh := chi.NewRouteContext()
+		fresh.Routes = rctx.Routes
+		fresh.RoutePath = rctx.RoutePath
+		fresh.RouteMethod = rctx.RouteMethod
+		fresh.RoutePatterns = append([]string(nil), rctx.RoutePatterns...)
+		fresh.URLParams.Keys = append([]string(nil), rctx.URLParams.Keys...)
+		fresh.URLParams.Values = append([]string(nil), rctx.URLParams.Values...)
+		ctx = context.WithValue(ctx, chi.RouteCtxKey, fresh)
+	}
+	return r.Clone(ctx)
+}
+
+// lookup busca a entrada; formato inválido (ex: gravado por versão antiga) conta como MISS
+func (c *HTTPCache) lookup(ctx context.Context, key string) (entry, bool) {
+	cached, err := c.store.Get(ctx, key)
+	if err != nil {
+		return entry{}, false
+	}
+	var e entry
+	if err := json.Unmarshal([]byte(cached), &e); err != nil || e.ETag == "" {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// respond envia a entrada, ou 304 se o cliente já tem essa versão
+func respond(w http.ResponseWriter, r *http.Request, e entry) {
+	w.Header().Set("ETag", e.ETag)
+	if notModified(r, e.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(e.Body))
+}
+
+func (c *HTTPCache) userOf(r *http.Request) (int, bool) {
+	if c.user == nil {
+		return 0, false
+	}
+	return c.user(r)
+}
+
+// buildKey gera a chave de cache: usuário + path + query (ordenada) + headers de vary
+func buildKey(prefix string, userID int, r *http.Request, vary []string) string {
 	raw := r.URL.Path
 	if r.URL.RawQuery != "" {
-		raw += "?" + r.URL.RawQuery
+		raw += "?" + r.URL.Query().Encode()
+	}
+	for _, h := range vary {
+		raw += "\n" + strings.ToLower(h) + ":" + r.Header.Get(h)
 	}
 	hash := sha256.Sum256([]byte(raw))
-	return fmt.Sprintf("cache:%s:%x", prefix, hash[:8])
+	return fmt.Sprintf("cache:%s:user:%d:%x", prefix, userID, hash[:8])
 }
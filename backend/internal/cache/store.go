@@ -0,0 +1,22 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Store armazenamento usado pelo cache HTTP. Client (Redis) implementa;
+// os testes usam um store em memória.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	SetTagged(ctx context.Context, key string, value string, ttl time.Duration, tags ...string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// Tag set de chaves de um recurso de um usuário (ex: tag:phrases:user:42)
+func Tag(resource string, userID int) string {
+	return fmt.Sprintf("tag:%s:user:%d", resource, userID)
+}
+
+var _ Store = (*Client)(nil)
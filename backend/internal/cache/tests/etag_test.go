@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"extension-backend/internal/cache"
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/user"
+)
+
+func getIfNoneMatch(t *testing.T, h http.Handler, tokens *user.TokenService, userID int, url, etag string) *httptest.ResponseRecorder {
+	t.Helper()
+	token, _ := tokens.GenerateAccessToken(&user.User{ID: userID})
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_NotModifiedOnHitAndMiss(t *testing.T) {
+	h, tokens := phrasesAPI(newMemStore())
+	url := "/api/v1/phrases"
+
+	first := get(t, h, tokens, 1, url)
+	etag := first.Header().Get("ETag")
+	if etag != cache.ETag(first.Body.Bytes()) {
+		t.Fatalf("expected strong ETag of the body, got %q", etag)
+	}
+
+	hit := getIfNoneMatch(t, h, tokens, 1, url, etag)
+	if hit.Code != http.StatusNotModified || hit.Header().Get("X-Cache") != "HIT" || hit.Body.Len() != 0 {
+		t.Errorf("expected 304 from cache, got %d %q %q", hit.Code, hit.Header().Get("X-Cache"), hit.Body.String())
+	}
+
+	// Invalidação apaga a entrada; o corpo gerado de novo é igual, então ainda 304
+	post(t, h, tokens, 1, url)
+	miss := getIfNoneMatch(t, h, tokens, 1, url, `"other", W/`+etag)
+	if miss.Code != http.StatusNotModified || miss.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected 304 on miss, got %d %q", miss.Code, miss.Header().Get("X-Cache"))
+	}
+
+	stale := getIfNoneMatch(t, h, tokens, 1, url, `"other"`)
+	if stale.Code != http.StatusOK || stale.Body.String() != first.Body.String() {
+		t.Errorf("expected full body for a different ETag, got %d", stale.Code)
+	}
+}
+
+func TestInvalidateOn_ReturnsNewETag(t *testing.T) {
+	tokens := user.NewTokenService()
+	httpCache := cache.NewHTTPCache(newMemStore(), middleware.UserID)
+	var total atomic.Int32
+	list := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"total":%d}}`, total.Load())
+	}
+	httpCache.WithRepresentation("phrases", "/api/v1/phrases", cache.DefaultTTL, list)
+	create := httpCache.InvalidateOn("phrases")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := total.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"data":{"id":%d}}`, n)
+	}))
+	h := middleware.Auth(tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			httpCache.Middleware("phrases", cache.DefaultTTL)(http.HandlerFunc(list)).ServeHTTP(w, r)
+			return
+		}
+		create.ServeHTTP(w, r)
+	}))
+	url := "/api/v1/phrases"
+	before := get(t, h, tokens, 1, url).Header().Get("ETag")
+
+	token, _ := tokens.GenerateAccessToken(&user.User{ID: 1})
+	req := httptest.NewRequest(http.MethodPost, url, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != `{"data":{"id":1}}` {
+		t.Fatalf("expected handler response to pass through, got %d %s", rec.Code, rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" || etag == before {
+		t.Fatalf("expected the ETag of the new list, got %q (before %q)", etag, before)
+	}
+	next := get(t, h, tokens, 1, url)
+	if got := next.Header().Get("ETag"); got != etag {
+		t.Errorf("expected the next GET to return the mutation ETag %q, got %q", etag, got)
+	}
+	if got := getIfNoneMatch(t, h, tokens, 1, url, etag); got.Code != http.StatusNotModified {
+		t.Errorf("expected 304 with the mutation ETag, got %d", got.Code)
+	}
+}
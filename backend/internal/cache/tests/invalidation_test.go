@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"extension-backend/internal/cache"
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/user"
+)
+
+// phrasesAPI GET cacheado + POST que invalida "phrases" (?fail=1 responde 400)
+func phrasesAPI(store cache.Store) (http.Handler, *user.TokenService) {
+	tokens := user.NewTokenService()
+	httpCache := cache.NewHTTPCache(store, middleware.UserID)
+	list := httpCache.Middleware("phrases", cache.DefaultTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	create := httpCache.InvalidateOn("phrases")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	return middleware.Auth(tokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			list.ServeHTTP(w, r)
+			return
+		}
+		create.ServeHTTP(w, r)
+	})), tokens
+}
+
+func post(t *testing.T, h http.Handler, tokens *user.TokenService, userID int, url string) {
+	t.Helper()
+	token, _ := tokens.GenerateAccessToken(&user.User{ID: userID})
+	req := httptest.NewRequest(http.MethodPost, url, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestInvalidateOn_OnlyDropsTheWritersEntries(t *testing.T) {
+	store := newMemStore()
+	h, tokens := phrasesAPI(store)
+	url := "/api/v1/phrases?limit=20"
+
+	get(t, h, tokens, 1, url)
+	get(t, h, tokens, 1, url+"&page=2")
+	get(t, h, tokens, 2, url)
+	if len(store.tags[cache.Tag("phrases", 1)]) != 2 {
+		t.Fatalf("expected 2 keys tagged for user 1, got %v", store.tags)
+	}
+
+	post(t, h, tokens, 1, "/api/v1/phrases")
+
+	if got := get(t, h, tokens, 1, url).Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("expected user 1 entry to be invalidated, got %q", got)
+	}
+	if got := get(t, h, tokens, 2, url).Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("expected user 2 entry to survive, got %q", got)
+	}
+}
+
+func TestInvalidateOn_SkipsFailedMutations(t *testing.T) {
+	h, tokens := phrasesAPI(newMemStore())
+	url := "/api/v1/phrases"
+
+	get(t, h, tokens, 1, url)
+	post(t, h, tokens, 1, url+"?fail=1")
+
+	if got := get(t, h, tokens, 1, url).Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("expected failed mutation to keep the cache, got %q", got)
+	}
+}
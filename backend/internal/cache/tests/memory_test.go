@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/cache"
+)
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryStore(30)
+
+	store.SetTagged(ctx, "a", strings.Repeat("a", 10), time.Minute)
+	store.SetTagged(ctx, "b", strings.Repeat("b", 10), time.Minute)
+	store.Get(ctx, "a") // "b" passa a ser o menos usado
+	store.SetTagged(ctx, "c", strings.Repeat("c", 15), time.Minute)
+
+	if _, err := store.Get(ctx, "b"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("expected b to be evicted, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := store.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to stay cached, got %v", key, err)
+		}
+	}
+}
+
+func TestMemoryStore_ExpiresAndInvalidatesTags(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryStore(0)
+
+	store.SetTagged(ctx, "short", "x", time.Millisecond)
+	store.SetTagged(ctx, "p1", "x", time.Minute, cache.Tag("phrases", 1))
+	store.SetTagged(ctx, "p2", "x", time.Minute, cache.Tag("phrases", 2))
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(ctx, "short"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("expected expired entry to miss, got %v", err)
+	}
+
+	store.InvalidateTags(ctx, cache.Tag("phrases", 1))
+	if _, err := store.Get(ctx, "p1"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("expected p1 to be invalidated, got %v", err)
+	}
+	if _, err := store.Get(ctx, "p2"); err != nil {
+		t.Errorf("expected p2 to survive, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"extension-backend/internal/cache"
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/user"
+)
+
+var errMiss = errors.New("miss")
+
+// memStore Store em memória (sem TTL)
+type memStore struct {
+	mu   sync.Mutex
+	data map[string]string
+	tags map[string][]string
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[string]string), tags: make(map[string][]string)}
+}
+
+func (s *memStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return "", errMiss
+	}
+	return v, nil
+}
+
+func (s *memStore) SetTagged(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	for _, tag := range tags {
+		s.tags[tag] = append(s.tags[tag], key)
+	}
+	return nil
+}
+
+func (s *memStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for _, k := range s.tags[tag] {
+			delete(s.data, k)
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// phrasesServer simula GET /phrases: cada usuário vê só as próprias frases
+func phrasesServer(t *testing.T, store cache.Store) (http.Handler, *user.TokenService) {
+	t.Helper()
+	tokens := user.NewTokenService()
+	httpCache := cache.NewHTTPCache(store, middleware.UserID)
+	list := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := middleware.GetUserFromContext(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[{"conteudo":"frase secreta do usuario %d"}]}`, claims.UserID)
+	})
+	return middleware.Auth(tokens)(httpCache.Middleware("phrases", cache.DefaultTTL)(list)), tokens
+}
+
+func get(t *testing.T, h http.Handler, tokens *user.TokenService, userID int, url string) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := tokens.GenerateAccessToken(&user.User{ID: userID})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_UserCannotReadAnotherUsersCachedPhrases(t *testing.T) {
+	h, tokens := phrasesServer(t, newMemStore())
+	url := "/api/v1/phrases?limit=20"
+
+	a := get(t, h, tokens, 1, url)
+	if a.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("expected first request to miss, got %q", a.Header().Get("X-Cache"))
+	}
+
+	b := get(t, h, tokens, 2, url)
+	if b.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("expected user 2 to miss user 1's entry, got %q", b.Header().Get("X-Cache"))
+	}
+	if want := `{"data":[{"conteudo":"frase secreta do usuario 2"}]}`; b.Body.String() != want {
+		t.Errorf("user 2 got %s", b.Body.String())
+	}
+
+	again := get(t, h, tokens, 1, url)
+	if again.Header().Get("X-Cache") != "HIT" || again.Body.String() != a.Body.String() {
+		t.Errorf("expected user 1 to hit own entry, got %q %s", again.Header().Get("X-Cache"), again.Body.String())
+	}
+	if vary := again.Header().Get("Vary"); vary != "Authorization, Cookie" {
+		t.Errorf("unexpected Vary %q", vary)
+	}
+}
+
+func TestMiddleware_QueryOrderSharesEntry(t *testing.T) {
+	h, tokens := phrasesServer(t, newMemStore())
+
+	get(t, h, tokens, 1, "/api/v1/phrases?limit=20&page=2")
+	rec := get(t, h, tokens, 1, "/api/v1/phrases?page=2&limit=20")
+	if rec.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected reordered query to hit, got %q", rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestMiddleware_BypassesWithoutUser(t *testing.T) {
+	store := newMemStore()
+	httpCache := cache.NewHTTPCache(store, middleware.UserID)
+	h := httpCache.Middleware("phrases", cache.DefaultTTL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/phrases", nil))
+	if rec.Header().Get("X-Cache") != "BYPASS" || len(store.data) != 0 {
+		t.Errorf("expected anonymous request to bypass cache, got %q with %d entries", rec.Header().Get("X-Cache"), len(store.data))
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"extension-backend/internal/cache"
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/user"
+)
+
+// countingServer GET /phrases que conta execuções; release segura o handler
+func countingServer(httpCache *cache.HTTPCache, ttl time.Duration, calls *atomic.Int32, release <-chan struct{}) (http.Handler, *user.TokenService) {
+	tokens := user.NewTokenService()
+	list := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"versao":%d}`, n)
+	})
+	return middleware.Auth(tokens)(httpCache.Middleware("phrases", ttl)(list)), tokens
+}
+
+func TestMiddleware_CoalescesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h, tokens := countingServer(cache.NewHTTPCache(cache.NewMemoryStore(0), middleware.UserID), cache.DefaultTTL, &calls, release)
+
+	const n = 10
+	bodies := make([]string, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bodies[i] = get(t, h, tokens, 1, "/api/v1/phrases").Body.String()
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected handler to run once, ran %d times", got)
+	}
+	for i, body := range bodies {
+		if body != `{"versao":1}` {
+			t.Errorf("request %d got %s", i, body)
+		}
+	}
+}
+
+func TestMiddleware_ServesStaleWhileRevalidating(t *testing.T) {
+	var calls atomic.Int32
+	httpCache := cache.NewHTTPCache(cache.NewMemoryStore(0), middleware.UserID).WithStaleWhileRevalidate(time.Minute)
+	h, tokens := countingServer(httpCache, 50*time.Millisecond, &calls, nil)
+	url := "/api/v1/phrases"
+
+	get(t, h, tokens, 1, url)
+	time.Sleep(60 * time.Millisecond)
+
+	stale := get(t, h, tokens, 1, url)
+	if stale.Header().Get("X-Cache") != "STALE" || stale.Body.String() != `{"versao":1}` {
+		t.Fatalf("expected stale body, got %q %s", stale.Header().Get("X-Cache"), stale.Body.String())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		rec := get(t, h, tokens, 1, url)
+		if rec.Body.String() == `{"versao":2}` {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry was not revalidated, last body %s", rec.Body.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected a single revalidation, handler ran %d times", got)
+	}
+}
+
+func TestMiddleware_RevalidationKeepsURLParams(t *testing.T) {
+	var calls atomic.Int32
+	var once sync.Once
+	started := make(chan struct{})
+	release := make(chan struct{})
+	tokens := user.NewTokenService()
+	httpCache := cache.NewHTTPCache(cache.NewMemoryStore(0), middleware.UserID).WithStaleWhileRevalidate(time.Minute)
+
+	router := chi.NewRouter()
+	router.Use(middleware.Auth(tokens))
+	router.With(httpCache.Middleware("phrases", 50*time.Millisecond)).Get("/api/v1/phrases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		// A revalidação de /phrases/1 espera outra request passar pelo router
+		if r.URL.Path == "/api/v1/phrases/1" && n > 1 {
+			once.Do(func() { close(started) })
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%q}`, chi.URLParam(r, "id"))
+	})
+
+	get(t, router, tokens, 1, "/api/v1/phrases/1")
+	time.Sleep(60 * time.Millisecond)
+	if rec := get(t, router, tokens, 1, "/api/v1/phrases/1"); rec.Header().Get("X-Cache") != "STALE" {
+		t.Fatalf("expected stale entry, got %q", rec.Header().Get("X-Cache"))
+	}
+
+	<-started
+	if rec := get(t, router, tokens, 1, "/api/v1/phrases/2"); rec.Body.String() != `{"id":"2"}` {
+		t.Fatalf("unexpected body for phrase 2: %s", rec.Body.String())
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		rec := get(t, router, tokens, 1, "/api/v1/phrases/1")
+		if rec.Header().Get("X-Cache") == "HIT" {
+			if rec.Body.String() != `{"id":"1"}` {
+				t.Errorf("revalidated entry for phrase 1 has %s", rec.Body.String())
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry was not revalidated")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// L1TTL quanto uma entrada do Redis fica no L1 desta instância. Invalidações
+// feitas por outra instância só chegam aqui depois desse prazo.
+const L1TTL = 5 * time.Second
+
+// remoteStore o que o TieredStore precisa do L2 (Client)
+type remoteStore interface {
+	Store
+	InvalidateTagsKeys(ctx context.Context, tags ...string) ([]string, error)
+}
+
+// TieredStore L1 em memória na frente do Redis
+type TieredStore struct {
+	l1 *MemoryStore
+	l2 remoteStore
+}
+
+// NewTieredStore cria o store em dois níveis
+func NewTieredStore(l1 *MemoryStore, l2 *Client) *TieredStore {
+	return &TieredStore{l1: l1, l2: l2}
+}
+
+// Get implementa Store: L1, depois Redis (copiando para o L1 por L1TTL)
+func (t *TieredStore) Get(ctx context.Context, key string) (string, error) {
+	if v, err := t.l1.Get(ctx, key); err == nil {
+		return v, nil
+	}
+	v, err := t.l2.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	t.l1.SetTagged(ctx, key, v, L1TTL)
+	return v, nil
+}
+
+// SetTagged implementa Store: grava nos dois níveis (L1 por no máximo L1TTL)
+func (t *TieredStore) SetTagged(ctx context.Context, key string, value string, ttl time.Duration, tags ...string) error {
+	t.l1.SetTagged(ctx, key, value, min(ttl, L1TTL), tags...)
+	return t.l2.SetTagged(ctx, key, value, ttl, tags...)
+}
+
+// InvalidateTags implementa Store. As chaves apagadas no Redis saem também do
+// L1, inclusive as que foram copiadas de lá sem tag.
+func (t *TieredStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	t.l1.InvalidateTags(ctx, tags...)
+	keys, err := t.l2.InvalidateTagsKeys(ctx, tags...)
+	t.l1.Delete(ctx, keys...)
+	return err
+}
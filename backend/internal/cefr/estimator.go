@@ -0,0 +1,202 @@
+package cefr
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"extension-backend/internal/vocabulary"
+)
+
+// Fontes de uma estimativa
+const (
+	SourceHeuristic = "heuristic"
+	SourceLLM       = "llm"
+)
+
+// Estimate resultado da estimativa de dificuldade de um texto
+type Estimate struct {
+	Nivel     Level    `json:"nivel"`
+	Score     float64  `json:"score"` // 1.0 (A1) – 6.0 (C2)
+	Palavras  int      `json:"palavras"`
+	PctA2     float64  `json:"pct_a2"`    // lemas fora da faixa A1
+	PctRaras  float64  `json:"pct_raras"` // lemas fora das listas de frequência
+	Gramatica []string `json:"gramatica,omitempty"`
+	Fonte     string   `json:"fonte"`
+}
+
+// grammarFeature estrutura gramatical com o nível em que costuma ser ensinada
+type grammarFeature struct {
+	name  string
+	level float64
+	re    *regexp.Regexp
+}
+
+var grammarFeatures = map[string][]grammarFeature{
+	"en": {
+		{"present_perfect", 2.5, regexp.MustCompile(`\b(have|has)\s+(\w+ly\s+)?(been|\w+ed|done|gone|seen|made|taken|known|written|given)\b`)},
+		{"past_continuous", 2, regexp.MustCompile(`\b(was|were)\s+\w+ing\b`)},
+		{"going_to", 2, regexp.MustCompile(`\b(am|is|are)\s+going\s+to\b`)},
+		{"passive", 3, regexp.MustCompile(`\b(is|are|was|were|been|be)\s+(\w+ed|done|made|taken|known|written|given|built|seen|found)\b(\s+by\b)?`)},
+		{"relative_clause", 3, regexp.MustCompile(`\b(which|whose|whom)\b`)},
+		{"second_conditional", 3, regexp.MustCompile(`\bif\b.{1,60}\bwould\b`)},
+		{"past_perfect", 3.5, regexp.MustCompile(`\bhad\s+(\w+ed|been|done|gone|seen|made|taken|known|written|given)\b`)},
+		{"modal_perfect", 4, regexp.MustCompile(`\b(must|should|could|might|would|may)\s+have\s+\w+`)},
+		{"passive_continuous", 4, regexp.MustCompile(`\b(is|are|was|were)\s+being\s+\w+ed\b`)},
+		{"third_conditional", 4, regexp.MustCompile(`\bif\b.{1,60}\bhad\b.{1,60}\bwould\s+have\b`)},
+		{"inversion", 5, regexp.MustCompile(`(^|[.;]\s*)(had|were|should)\s+(i|you|he|she|we|they|it)\b|\b(not only|no sooner|hardly had|rarely (do|does|did|have|has))\b`)},
+		{"cleft_subjunctive", 5, regexp.MustCompile(`\b(it is (essential|vital|imperative) that|were (i|he|she|it) to|lest)\b`)},
+	},
+	"pt": {
+		{"preterito_imperfeito", 2, regexp.MustCompile(`\b\w+(ava|avam|íamos|ia|iam)\b`)},
+		{"futuro_perifrastico", 2, regexp.MustCompile(`\b(vou|vai|vamos|vão)\s+\w+(ar|er|ir)\b`)},
+		{"voz_passiva", 3, regexp.MustCompile(`\b(foi|foram|é|são|será|serão)\s+\w+(ado|ada|ados|adas|ido|ida|idos|idas)\b`)},
+		{"relativa", 3, regexp.MustCompile(`\b(o qual|a qual|os quais|as quais|cujo|cuja|cujos|cujas)\b`)},
+		{"futuro_do_preterito", 3, regexp.MustCompile(`\b\w+(aria|eria|iria|ariam|eriam|iriam)\b`)},
+		{"mais_que_perfeito", 4, regexp.MustCompile(`\b(tinha|tinham|havia|haviam)\s+\w+(ado|ido)\b`)},
+		{"subjuntivo", 4, regexp.MustCompile(`\b(se|quando|embora|caso|talvez)\s+(\w+\s+)?\w+(asse|esse|isse|assem|essem|issem|armos|ermos|irmos)\b`)},
+		{"infinitivo_pessoal", 5, regexp.MustCompile(`\bpara\s+(eles|elas|nós)\s+\w+(arem|erem|irem|armos|ermos|irmos)\b`)},
+	},
+	"es": {
+		{"preterito_imperfecto", 2, regexp.MustCompile(`\b\w+(aba|aban|íamos|ía|ían)\b`)},
+		{"futuro_perifrastico", 2, regexp.MustCompile(`\b(voy|vas|va|vamos|van)\s+a\s+\w+(ar|er|ir)\b`)},
+		{"voz_pasiva", 3, regexp.MustCompile(`\b(fue|fueron|es|son|será|serán)\s+\w+(ado|ada|ados|adas|ido|ida|idos|idas)\b`)},
+		{"relativo", 3, regexp.MustCompile(`\b(el cual|la cual|los cuales|las cuales|cuyo|cuya|cuyos|cuyas)\b`)},
+		{"condicional", 3, regexp.MustCompile(`\b\w+(aría|ería|iría|arían|erían|irían)\b`)},
+		{"pluscuamperfecto", 4, regexp.MustCompile(`\b(había|habían)\s+\w+(ado|ido)\b`)},
+		{"subjuntivo", 4, regexp.MustCompile(`\b(que|cuando|aunque|ojalá|si)\s+(\w+\s+)?\w+(ara|iera|aran|ieran|ase|iese)\b`)},
+	},
+}
+
+var sentenceSplit = regexp.MustCompile(`[.!?;]+`)
+
+// Estimator calcula o nível CEFR de um texto combinando frequência das palavras,
+// tamanho das frases e estruturas gramaticais
+type Estimator struct{}
+
+// NewEstimator cria um novo Estimator
+func NewEstimator() *Estimator {
+	return &Estimator{}
+}
+
+// Estimate calcula a estimativa para o texto no idioma informado ("en", "pt-BR"...)
+func (e *Estimator) Estimate(text, idioma string) Estimate {
+	idioma = baseIdioma(idioma)
+	tokens := vocabulary.Tokenize(text, idioma)
+
+	est := Estimate{Palavras: len(tokens), Fonte: SourceHeuristic}
+	if len(tokens) == 0 {
+		est.Nivel, est.Score = A1, 1
+		return est
+	}
+
+	// Léxico: proporção de lemas fora das faixas mais frequentes
+	var a2, raras, conhecidos int
+	nomes := properNouns(text)
+	for _, t := range tokens {
+		// Nomes próprios não dizem nada sobre o vocabulário do texto
+		if nomes[t.Forma] {
+			continue
+		}
+		band, ok := bandFor(idioma, t.Lema)
+		if !ok {
+			break
+		}
+		conhecidos++
+		switch band {
+		case bandA2:
+			a2++
+		case bandRare:
+			raras++
+		}
+	}
+
+	var lexical float64
+	if conhecidos > 0 {
+		est.PctA2 = round2(float64(a2) / float64(conhecidos))
+		est.PctRaras = round2(float64(raras) / float64(conhecidos))
+		lexical = 1 + 1.5*est.PctA2 + 6*est.PctRaras
+	} else {
+		// Sem lista de frequência para o idioma: usa o tamanho médio das palavras
+		lexical = 1 + math.Max(0, avgWordLength(tokens)-4)
+	}
+
+	// Tamanho médio das frases (5 palavras ≈ A1, 30+ ≈ C2)
+	length := 1 + math.Max(0, avgSentenceLength(text, idioma)-5)/5
+
+	// Gramática: a estrutura mais avançada encontrada
+	grammar := 1.0
+	lower := strings.ToLower(text)
+	for _, f := range grammarFeatures[idioma] {
+		if f.re.MatchString(lower) {
+			est.Gramatica = append(est.Gramatica, f.name)
+			grammar = math.Max(grammar, f.level)
+		}
+	}
+
+	score := 0.6*lexical + 0.2*length + 0.2*grammar
+	// Frases muito curtas não têm como mostrar estrutura: o léxico decide
+	if len(tokens) <= 3 {
+		score = lexical
+	}
+	// Vocabulário raro ou uma estrutura avançada puxam o nível para perto deles
+	score = math.Max(score, math.Max(lexical-1, grammar-0.75))
+
+	est.Score = round2(math.Min(math.Max(score, 1), 6))
+	est.Nivel = FromScore(est.Score)
+	return est
+}
+
+func avgWordLength(tokens []vocabulary.Token) float64 {
+	total := 0
+	for _, t := range tokens {
+		total += len([]rune(t.Forma))
+	}
+	return float64(total) / float64(len(tokens))
+}
+
+func avgSentenceLength(text, idioma string) float64 {
+	var frases, palavras int
+	for _, s := range sentenceSplit.Split(text, -1) {
+		n := len(vocabulary.Tokenize(s, idioma))
+		if n == 0 {
+			continue
+		}
+		frases++
+		palavras += n
+	}
+	if frases == 0 {
+		return 0
+	}
+	return float64(palavras) / float64(frases)
+}
+
+// properNouns palavras com inicial maiúscula fora do início da frase (em minúsculo)
+func properNouns(text string) map[string]bool {
+	nomes := make(map[string]bool)
+	for _, s := range sentenceSplit.Split(text, -1) {
+		words := strings.FieldsFunc(s, func(r rune) bool {
+			return !unicode.IsLetter(r) && r != '\''
+		})
+		for i, w := range words {
+			if i > 0 && unicode.IsUpper([]rune(w)[0]) && w != "I" {
+				nomes[strings.ToLower(w)] = true
+			}
+		}
+	}
+	return nomes
+}
+
+// baseIdioma "pt-BR" -> "pt"
+func baseIdioma(idioma string) string {
+	idioma = strings.ToLower(strings.TrimSpace(idioma))
+	if i := strings.IndexAny(idioma, "-_"); i > 0 {
+		idioma = idioma[:i]
+	}
+	return idioma
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,74 @@
+package cefr
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+//go:embed data/*.txt
+var freqFiles embed.FS
+
+// Faixas de frequência de um lema
+const (
+	bandA1   = 1
+	bandA2   = 2
+	bandRare = 3
+)
+
+// freqList lema -> faixa, carregado sob demanda por idioma
+type freqList map[string]int
+
+var (
+	freqOnce  sync.Once
+	freqLists map[string]freqList
+)
+
+// bandFor retorna a faixa do lema; ok=false quando não há lista para o idioma
+func bandFor(idioma, lema string) (band int, ok bool) {
+	freqOnce.Do(loadFreqLists)
+	list, ok := freqLists[idioma]
+	if !ok {
+		return 0, false
+	}
+	if b, found := list[lema]; found {
+		return b, true
+	}
+	return bandRare, true
+}
+
+func loadFreqLists() {
+	freqLists = make(map[string]freqList)
+	entries, _ := freqFiles.ReadDir("data")
+	for _, e := range entries {
+		data, err := freqFiles.ReadFile("data/" + e.Name())
+		if err != nil {
+			continue
+		}
+
+		var words []string
+		sc := bufio.NewScanner(strings.NewReader(string(data)))
+		for sc.Scan() {
+			w := strings.TrimSpace(sc.Text())
+			if w == "" || strings.HasPrefix(w, "#") {
+				continue
+			}
+			words = append(words, strings.ToLower(w))
+		}
+
+		list := make(freqList, len(words))
+		half := len(words) / 2
+		for i, w := range words {
+			if _, dup := list[w]; dup {
+				continue
+			}
+			if i < half {
+				list[w] = bandA1
+			} else {
+				list[w] = bandA2
+			}
+		}
+		freqLists[strings.TrimSuffix(e.Name(), ".txt")] = list
+	}
+}
@@ -0,0 +1,12 @@
+package cefr
+
+import "context"
+
+// RepositoryInterface define o acesso aos níveis CEFR de frases e exercícios
+type RepositoryInterface interface {
+	GetFrase(ctx context.Context, fraseID int) (*Texto, error)
+	SetFraseNivel(ctx context.Context, fraseID int, nivel Level) error
+	ListFrasesSemNivel(ctx context.Context, limit int) ([]Texto, error)
+	ListExerciciosSemNivel(ctx context.Context, limit int) ([]ExercicioTexto, error)
+	SetExercicioNivel(ctx context.Context, exercicioID int, nivel Level) error
+}
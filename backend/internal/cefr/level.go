@@ -0,0 +1,68 @@
+package cefr
+
+import "strings"
+
+// Level nível do Quadro Europeu Comum de Referência (A1–C2)
+type Level string
+
+const (
+	A1 Level = "A1"
+	A2 Level = "A2"
+	B1 Level = "B1"
+	B2 Level = "B2"
+	C1 Level = "C1"
+	C2 Level = "C2"
+)
+
+// Levels em ordem crescente de dificuldade
+var Levels = []Level{A1, A2, B1, B2, C1, C2}
+
+// ParseLevel aceita "b2", " B2 " etc. Retorna false para valores desconhecidos.
+func ParseLevel(s string) (Level, bool) {
+	l := Level(strings.ToUpper(strings.TrimSpace(s)))
+	return l, l.Rank() > 0
+}
+
+// Rank posição do nível (A1=1 … C2=6); 0 para nível inválido
+func (l Level) Rank() int {
+	for i, lv := range Levels {
+		if lv == l {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// FromRank converte uma posição (1–6) em nível, limitando aos extremos
+func FromRank(rank int) Level {
+	rank = min(max(rank, 1), len(Levels))
+	return Levels[rank-1]
+}
+
+// FromScore converte a pontuação contínua do estimador (1.0–6.0) em nível
+func FromScore(score float64) Level {
+	switch {
+	case score < 1.75:
+		return A1
+	case score < 2.5:
+		return A2
+	case score < 3.25:
+		return B1
+	case score < 4.0:
+		return B2
+	case score < 4.75:
+		return C1
+	}
+	return C2
+}
+
+// ForProficiency nível-alvo a partir de preferencias_usuario.nivel_proficiencia
+func ForProficiency(nivel string) Level {
+	switch strings.ToLower(nivel) {
+	case "beginner":
+		return A2
+	case "advanced":
+		return C1
+	}
+	return B1
+}
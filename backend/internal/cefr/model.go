@@ -0,0 +1,15 @@
+package cefr
+
+// Texto frase a classificar
+type Texto struct {
+	ID       int
+	Conteudo string
+	Idioma   string
+}
+
+// ExercicioTexto exercício a classificar (idioma = idioma de aprendizado)
+type ExercicioTexto struct {
+	ID     int
+	Dados  map[string]interface{}
+	Idioma string
+}
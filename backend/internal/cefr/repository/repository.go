@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"extension-backend/internal/cefr"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX define an interface for database transactions.
+type DBTX interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type Repository struct {
+	db DBTX
+}
+
+func New(db DBTX) *Repository {
+	return &Repository{db: db}
+}
+
+// GetFrase busca o conteúdo e o idioma de uma frase
+func (r *Repository) GetFrase(ctx context.Context, fraseID int) (*cefr.Texto, error) {
+	var t cefr.Texto
+	err := r.db.QueryRow(ctx,
+		`SELECT id, conteudo, COALESCE(idioma_origem, 'en') FROM frases WHERE id = $1`, fraseID,
+	).Scan(&t.ID, &t.Conteudo, &t.Idioma)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SetFraseNivel grava o nível CEFR da frase
+func (r *Repository) SetFraseNivel(ctx context.Context, fraseID int, nivel cefr.Level) error {
+	_, err := r.db.Exec(ctx, `UPDATE frases SET nivel_cefr = $2 WHERE id = $1`, fraseID, string(nivel))
+	return err
+}
+
+// ListFrasesSemNivel retorna frases ainda não classificadas (backfill)
+func (r *Repository) ListFrasesSemNivel(ctx context.Context, limit int) ([]cefr.Texto, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, conteudo, COALESCE(idioma_origem, 'en')
+		FROM frases
+		WHERE nivel_cefr IS NULL
+		ORDER BY id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []cefr.Texto
+	for rows.Next() {
+		var t cefr.Texto
+		if err := rows.Scan(&t.ID, &t.Conteudo, &t.Idioma); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// ListExerciciosSemNivel retorna exercícios ainda não classificados com o código do idioma estudado
+func (r *Repository) ListExerciciosSemNivel(ctx context.Context, limit int) ([]cefr.ExercicioTexto, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT e.id, e.dados_exercicio, COALESCE(i.codigo, 'en')
+		FROM exercicios e
+		LEFT JOIN idiomas i ON i.id = e.idioma_id
+		WHERE e.nivel_cefr IS NULL
+		ORDER BY e.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []cefr.ExercicioTexto
+	for rows.Next() {
+		var ex cefr.ExercicioTexto
+		var dadosJSON []byte
+		if err := rows.Scan(&ex.ID, &dadosJSON, &ex.Idioma); err != nil {
+			return nil, err
+		}
+		if dadosJSON != nil {
+			json.Unmarshal(dadosJSON, &ex.Dados)
+		}
+		list = append(list, ex)
+	}
+	return list, rows.Err()
+}
+
+// SetExercicioNivel grava o nível CEFR do exercício
+func (r *Repository) SetExercicioNivel(ctx context.Context, exercicioID int, nivel cefr.Level) error {
+	_, err := r.db.Exec(ctx, `UPDATE exercicios SET nivel_cefr = $2 WHERE id = $1`, exercicioID, string(nivel))
+	return err
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"extension-backend/internal/cefr"
+)
+
+// backfillBatch quantas linhas o backfill classifica por consulta
+const backfillBatch = 200
+
+type Service struct {
+	repo      cefr.RepositoryInterface
+	estimator *cefr.Estimator
+	refiner   *cefr.Refiner
+}
+
+func New(repo cefr.RepositoryInterface) *Service {
+	return &Service{repo: repo, estimator: cefr.NewEstimator()}
+}
+
+// WithRefiner habilita o refinamento por LLM nas frases novas
+func (s *Service) WithRefiner(refiner *cefr.Refiner) *Service {
+	s.refiner = refiner
+	return s
+}
+
+// IndexPhrase estima e grava o nível CEFR de uma frase (processor.Indexer)
+func (s *Service) IndexPhrase(ctx context.Context, fraseID int) error {
+	frase, err := s.repo.GetFrase(ctx, fraseID)
+	if err != nil {
+		return fmt.Errorf("phrase %d not found: %w", fraseID, err)
+	}
+
+	est := s.estimator.Estimate(frase.Conteudo, frase.Idioma)
+	if s.refiner != nil {
+		// Falha no modelo mantém a estimativa heurística
+		if refined, err := s.refiner.Refine(ctx, frase.Conteudo, frase.Idioma, est); err != nil {
+			log.Printf("[CEFR] Refinement failed for phrase %d: %v", fraseID, err)
+		} else {
+			est = refined
+		}
+	}
+
+	if err := s.repo.SetFraseNivel(ctx, fraseID, est.Nivel); err != nil {
+		return fmt.Errorf("failed to save level for phrase %d: %w", fraseID, err)
+	}
+
+	log.Printf("[CEFR] Phrase %d classified as %s (%s)", fraseID, est.Nivel, est.Fonte)
+	return nil
+}
+
+// Backfill classifica frases e exercícios que ainda não têm nível (apenas heurística).
+// Retorna quantas frases e quantos exercícios foram classificados.
+func (s *Service) Backfill(ctx context.Context) (frases int, exercicios int, err error) {
+	for {
+		batch, err := s.repo.ListFrasesSemNivel(ctx, backfillBatch)
+		if err != nil {
+			return frases, exercicios, fmt.Errorf("failed to list unclassified phrases: %w", err)
+		}
+		for _, f := range batch {
+			nivel := s.estimator.Estimate(f.Conteudo, f.Idioma).Nivel
+			if err := s.repo.SetFraseNivel(ctx, f.ID, nivel); err != nil {
+				return frases, exercicios, fmt.Errorf("failed to save level for phrase %d: %w", f.ID, err)
+			}
+			frases++
+		}
+		if len(batch) < backfillBatch {
+			break
+		}
+	}
+
+	for {
+		batch, err := s.repo.ListExerciciosSemNivel(ctx, backfillBatch)
+		if err != nil {
+			return frases, exercicios, fmt.Errorf("failed to list unclassified exercises: %w", err)
+		}
+		for _, ex := range batch {
+			nivel := s.estimator.Estimate(cefr.ExerciseText(ex.Dados), ex.Idioma).Nivel
+			if err := s.repo.SetExercicioNivel(ctx, ex.ID, nivel); err != nil {
+				return frases, exercicios, fmt.Errorf("failed to save level for exercise %d: %w", ex.ID, err)
+			}
+			exercicios++
+		}
+		if len(batch) < backfillBatch {
+			break
+		}
+	}
+
+	return frases, exercicios, nil
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/cefr"
+)
+
+func TestEstimate_OrdersTextsByDifficulty(t *testing.T) {
+	e := cefr.NewEstimator()
+
+	cases := []struct {
+		text   string
+		idioma string
+		min    cefr.Level
+		max    cefr.Level
+	}{
+		{"I like my cat.", "en", cefr.A1, cefr.A1},
+		{"She has a big house and two dogs.", "en", cefr.A1, cefr.A2},
+		{"If I had known about the meeting, I would have come earlier.", "en", cefr.B2, cefr.C1},
+		{"Notwithstanding the ostensible consensus, the committee's deliberations remained conspicuously inconclusive.", "en", cefr.C1, cefr.C2},
+		{"Se eu tivesse dinheiro, compraria uma casa que fosse grande.", "pt-BR", cefr.B1, cefr.B2},
+		{"Hola, me llamo Ana.", "es", cefr.A1, cefr.A2},
+	}
+
+	for _, c := range cases {
+		est := e.Estimate(c.text, c.idioma)
+		if est.Nivel.Rank() < c.min.Rank() || est.Nivel.Rank() > c.max.Rank() {
+			t.Errorf("%q: expected %s–%s, got %s (score %.2f)", c.text, c.min, c.max, est.Nivel, est.Score)
+		}
+		if est.Fonte != cefr.SourceHeuristic {
+			t.Errorf("%q: expected heuristic source, got %s", c.text, est.Fonte)
+		}
+	}
+}
+
+func TestEstimate_EmptyText(t *testing.T) {
+	est := cefr.NewEstimator().Estimate("  ...  ", "en")
+	if est.Nivel != cefr.A1 || est.Palavras != 0 {
+		t.Errorf("expected A1 with no words, got %+v", est)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if lv, ok := cefr.ParseLevel(" b2 "); !ok || lv != cefr.B2 {
+		t.Errorf("expected B2, got %q %v", lv, ok)
+	}
+	if _, ok := cefr.ParseLevel("D1"); ok {
+		t.Error("expected D1 to be invalid")
+	}
+}
+
+func TestExerciseText_SkipsInstructionsAndTranslations(t *testing.T) {
+	text := cefr.ExerciseText(map[string]interface{}{
+		"instrucao": "Escolha a opção correta",
+		"frase":     "The cat sleeps",
+		"opcoes":    []interface{}{"on the sofa", map[string]interface{}{"texto": "in the box", "traducao": "na caixa"}},
+	})
+	want := "The cat sleeps\non the sofa\nin the box"
+	if text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+}
+
+func TestRefine_MovesAtMostOneLevel(t *testing.T) {
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) {
+		return `{"nivel": "C2"}`, nil
+	})
+	est := cefr.Estimate{Nivel: cefr.A2, Score: 2.1, Fonte: cefr.SourceHeuristic}
+
+	refined, err := cefr.NewRefiner(llm).Refine(context.Background(), "some text", "en", est)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refined.Nivel != cefr.B1 || refined.Fonte != cefr.SourceLLM {
+		t.Errorf("expected B1 from llm, got %s (%s)", refined.Nivel, refined.Fonte)
+	}
+}
+
+func TestRefine_InvalidResponseKeepsEstimate(t *testing.T) {
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) {
+		return `{"nivel": "expert"}`, nil
+	})
+	est := cefr.Estimate{Nivel: cefr.B1, Fonte: cefr.SourceHeuristic}
+
+	refined, err := cefr.NewRefiner(llm).Refine(context.Background(), "some text", "en", est)
+	if err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+	if refined.Nivel != cefr.B1 || refined.Fonte != cefr.SourceHeuristic {
+		t.Errorf("expected original estimate, got %+v", refined)
+	}
+}
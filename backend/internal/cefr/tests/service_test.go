@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/cefr"
+	"extension-backend/internal/cefr/repository"
+	"extension-backend/internal/cefr/service"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestIndexPhrase_SavesRefinedLevel(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) {
+		return `{"nivel": "A2"}`, nil
+	})
+	svc := service.New(repository.New(mock)).WithRefiner(cefr.NewRefiner(llm))
+
+	mock.ExpectQuery("SELECT id, conteudo, (.+) FROM frases WHERE id").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "conteudo", "idioma_origem"}).
+			AddRow(10, "I like my cat.", "en"))
+	mock.ExpectExec("UPDATE frases SET nivel_cefr").
+		WithArgs(10, "A2").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	if err := svc.IndexPhrase(context.Background(), 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBackfill_ClassifiesPhrasesAndExercises(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	svc := service.New(repository.New(mock))
+
+	mock.ExpectQuery("SELECT id, conteudo, (.+) FROM frases WHERE nivel_cefr IS NULL").
+		WithArgs(200).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "conteudo", "idioma_origem"}).
+			AddRow(1, "I like my cat.", "en"))
+	mock.ExpectExec("UPDATE frases SET nivel_cefr").
+		WithArgs(1, "A1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	mock.ExpectQuery("SELECT e.id, e.dados_exercicio, (.+) FROM exercicios e LEFT JOIN idiomas i").
+		WithArgs(200).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "dados_exercicio", "codigo"}).
+			AddRow(5, []byte(`{"frase": "I like my cat.", "instrucao": "Notwithstanding the ostensible consensus"}`), "en"))
+	mock.ExpectExec("UPDATE exercicios SET nivel_cefr").
+		WithArgs(5, "A1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	frases, exercicios, err := svc.Backfill(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frases != 1 || exercicios != 1 {
+		t.Errorf("expected 1 phrase and 1 exercise, got %d and %d", frases, exercicios)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
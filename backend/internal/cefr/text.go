@@ -0,0 +1,55 @@
+package cefr
+
+import (
+	"sort"
+	"strings"
+)
+
+// skipKeys campos de dados_exercicio que não são conteúdo no idioma estudado
+// (instruções, traduções, metadados)
+var skipKeys = map[string]bool{
+	"instrucao":     true,
+	"explicacao":    true,
+	"pt":            true,
+	"tags":          true,
+	"time":          true,
+	"timeLimit":     true,
+	"traducao":      true,
+	"palavras_alvo": true,
+}
+
+// ExerciseText junta os textos de dados_exercicio para estimar o nível do exercício
+func ExerciseText(dados map[string]interface{}) string {
+	var parts []string
+	collectText(dados, &parts)
+	return strings.Join(parts, "\n")
+}
+
+func collectText(v interface{}, parts *[]string) {
+	switch t := v.(type) {
+	case string:
+		if s := strings.TrimSpace(t); s != "" {
+			*parts = append(*parts, s)
+		}
+	case []interface{}:
+		for _, item := range t {
+			collectText(item, parts)
+		}
+	case []string:
+		for _, item := range t {
+			collectText(item, parts)
+		}
+	case map[string]interface{}:
+		// Ordem estável para que a mesma entrada gere sempre o mesmo nível
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			if !skipKeys[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectText(t[k], parts)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package conversation
+
+import (
+	"context"
+
+	"extension-backend/internal/audio"
+	"extension-backend/internal/phrase"
+)
+
+// RepositoryInterface define as operações de acesso a dados das conversas
+type RepositoryInterface interface {
+	Create(ctx context.Context, c NovaConversa) (int, error)
+	AddTurno(ctx context.Context, conversaID int, t Turno) (int, error)
+	AddCorrecoes(ctx context.Context, turnoID int, correcoes []Correcao) ([]int, error)
+	End(ctx context.Context, conversaID int) error
+	List(ctx context.Context, userID int, params ListParams) ([]Conversa, error)
+	Get(ctx context.Context, userID, conversaID int) (*Conversa, error)
+	ListTurnos(ctx context.Context, conversaID int) ([]Turno, error)
+	GetTurno(ctx context.Context, conversaID, turnoID int) (*Turno, error)
+	ListCorrecoes(ctx context.Context, conversaID int) ([]Correcao, error)
+	GetCorrecao(ctx context.Context, userID, correcaoID int) (*Correcao, error)
+	SaveCorrecaoFrase(ctx context.Context, correcaoID int, frase *phrase.Phrase, detalhes *phrase.PhraseDetails) error
+	AddPalavrasAlvo(ctx context.Context, turnoID int, palavras []string) error
+	ListPalavrasAlvo(ctx context.Context, conversaID int) ([]PalavraAlvo, error)
+	ListCenarios(ctx context.Context, params CenarioParams) ([]Cenario, error)
+	GetCenario(ctx context.Context, id int) (*Cenario, error)
+	GetIdioma(ctx context.Context, id int) (*Idioma, error)
+}
+
+// ServiceInterface define a lógica de negócio das conversas; também grava
+// as conversas do pipeline de voz e fornece o catálogo de cenários
+type ServiceInterface interface {
+	audio.TranscriptRecorder
+	audio.ScenarioCatalog
+	ListScenarios(ctx context.Context, params CenarioParams) ([]Cenario, error)
+	List(ctx context.Context, userID int, params ListParams) ([]Conversa, error)
+	Get(ctx context.Context, userID, conversaID int) (*ConversaDetalhe, error)
+	SavePhrase(ctx context.Context, userID, conversaID int, input SavePhraseInput) (*phrase.Phrase, error)
+	SaveCorrection(ctx context.Context, userID, correcaoID int, input SaveCorrectionInput) (*phrase.Phrase, error)
+}
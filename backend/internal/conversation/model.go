@@ -0,0 +1,132 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound conversa (ou turno) inexistente ou de outro usuário
+	ErrNotFound = errors.New("conversation not found")
+	// ErrInvalidSentence trecho que não está no turno escolhido
+	ErrInvalidSentence = errors.New("sentence not found in turn")
+	// ErrCorrectionSaved correção que já virou frase
+	ErrCorrectionSaved = errors.New("correction already saved as phrase")
+	// ErrScenarioNotFound cenário inexistente ou desativado
+	ErrScenarioNotFound = errors.New("scenario not found")
+	// ErrLanguageNotFound idioma inexistente ou desativado
+	ErrLanguageNotFound = errors.New("language not found")
+)
+
+// Papéis de um turno (mesmos valores do histórico do LLM)
+const (
+	PapelUser  = "user"
+	PapelModel = "model"
+)
+
+// Conversa sessão de conversa por voz (GET /conversations)
+type Conversa struct {
+	ID          int        `json:"id"`
+	UsuarioID   int        `json:"usuario_id"`
+	STTProvider string     `json:"stt_provider"`
+	LLMProvider string     `json:"llm_provider"`
+	TTSProvider string     `json:"tts_provider"`
+	CenarioID   *int       `json:"cenario_id,omitempty"`
+	Idioma      string     `json:"idioma,omitempty"`
+	IniciadaEm  time.Time  `json:"iniciada_em"`
+	EncerradaEm *time.Time `json:"encerrada_em,omitempty"`
+	TotalTurnos int        `json:"total_turnos"`
+	Previa      string     `json:"previa,omitempty"` // primeira fala do usuário
+}
+
+// Turno fala do usuário ou resposta do modelo, com os tempos do pipeline
+type Turno struct {
+	ID           int        `json:"id"`
+	Papel        string     `json:"papel"`
+	Conteudo     string     `json:"conteudo"`
+	IniciadoEm   time.Time  `json:"iniciado_em"`
+	DuracaoMs    int        `json:"duracao_ms"`
+	TTFAMs       *int       `json:"ttfa_ms,omitempty"`
+	Interrompido bool       `json:"interrompido"`
+	Correcoes    []Correcao `json:"correcoes,omitempty"`     // só turnos do usuário
+	PalavrasAlvo []string   `json:"palavras_alvo,omitempty"` // palavras do cenário usadas pela primeira vez no turno
+}
+
+// Correcao erro gramatical numa fala do usuário, apontado durante a conversa
+type Correcao struct {
+	ID         int    `json:"id"`
+	TurnoID    int    `json:"turno_id"`
+	Original   string `json:"original"`
+	Corrigido  string `json:"corrigido"`
+	Explicacao string `json:"explicacao"`
+	FraseID    *int   `json:"frase_id,omitempty"` // frase criada a partir da correção
+}
+
+// ConversaDetalhe conversa com todos os turnos (GET /conversations/{id})
+type ConversaDetalhe struct {
+	Conversa
+	Turnos []Turno `json:"turnos"`
+}
+
+// NovaConversa dados gravados quando a conversa começa
+type NovaConversa struct {
+	UsuarioID   int
+	STTProvider string
+	LLMProvider string
+	TTSProvider string
+	CenarioID   int    // 0 = conversa livre
+	Idioma      string // código do idioma
+}
+
+// Cenario cenário de role-play do catálogo (GET /conversations/scenarios)
+type Cenario struct {
+	ID           int      `json:"id"`
+	Slug         string   `json:"slug"`
+	Titulo       string   `json:"titulo"`
+	Descricao    string   `json:"descricao"`
+	Persona      string   `json:"-"` // prompt do tutor: só o pipeline usa
+	PalavrasAlvo []string `json:"palavras_alvo"`
+	NivelCEFR    *string  `json:"nivel_cefr,omitempty"`
+	Idioma       *string  `json:"idioma,omitempty"`
+	VoiceID      *string  `json:"voice_id,omitempty"`
+}
+
+// Idioma linha da tabela idiomas
+type Idioma struct {
+	ID     int
+	Codigo string
+	Nome   string
+}
+
+// PalavraAlvo palavra do cenário usada num turno
+type PalavraAlvo struct {
+	TurnoID int
+	Palavra string
+}
+
+// CenarioParams filtro do GET /conversations/scenarios
+type CenarioParams struct {
+	Idioma string // código; vazio = todos (cenários sem idioma aparecem sempre)
+	Nivel  string // nível CEFR
+}
+
+// ListParams paginação do GET /conversations
+type ListParams struct {
+	Limit  int
+	Offset int
+}
+
+// SavePhraseInput trecho de um turno para salvar como frase
+// (POST /conversations/{id}/phrases); sem conteudo, o turno inteiro
+type SavePhraseInput struct {
+	TurnoID       int    `json:"turno_id"`
+	Conteudo      string `json:"conteudo"`
+	IdiomaOrigem  string `json:"idioma_origem"`
+	IdiomaDestino string `json:"idioma_destino"`
+}
+
+// SaveCorrectionInput idioma da frase criada a partir de uma correção
+// (POST /conversations/corrections/{id}/phrase)
+type SaveCorrectionInput struct {
+	IdiomaOrigem string `json:"idioma_origem"`
+}
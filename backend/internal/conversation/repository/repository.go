@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"extension-backend/internal/conversation"
+	"extension-backend/internal/phrase"
+	phraseRepo "extension-backend/internal/phrase/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX define an interface for database transactions.
+type DBTX interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type Repository struct {
+	db DBTX
+}
+
+func New(db DBTX) *Repository {
+	return &Repository{db: db}
+}
+
+// Create abre uma conversa e retorna o id
+func (r *Repository) Create(ctx context.Context, c conversation.NovaConversa) (int, error) {
+	query := `
+		INSERT INTO conversas (usuario_id, stt_provider, llm_provider, tts_provider, cenario_id, idioma)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, 0), NULLIF($6, ''))
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.QueryRow(ctx, query, c.UsuarioID, c.STTProvider, c.LLMProvider, c.TTSProvider, c.CenarioID, c.Idioma).Scan(&id)
+	return id, err
+}
+
+// AddTurno grava um turno no fim da conversa e retorna o id
+func (r *Repository) AddTurno(ctx context.Context, conversaID int, t conversation.Turno) (int, error) {
+	query := `
+		INSERT INTO conversa_turnos (conversa_id, papel, conteudo, iniciado_em, duracao_ms, ttfa_ms, interrompido)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.QueryRow(ctx, query, conversaID, t.Papel, t.Conteudo, t.IniciadoEm, t.DuracaoMs, t.TTFAMs, t.Interrompido).Scan(&id)
+	return id, err
+}
+
+// AddCorrecoes grava as correções de um turno e retorna os ids na mesma ordem
+func (r *Repository) AddCorrecoes(ctx context.Context, turnoID int, correcoes []conversation.Correcao) ([]int, error) {
+	query := `
+		INSERT INTO conversa_correcoes (turno_id, original, corrigido, explicacao)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	ids := make([]int, 0, len(correcoes))
+	for _, c := range correcoes {
+		var id int
+		if err := r.db.QueryRow(ctx, query, turnoID, c.Original, c.Corrigido, c.Explicacao).Scan(&id); err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// End marca o fim da conversa (a primeira vez vale)
+func (r *Repository) End(ctx context.Context, conversaID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE conversas SET encerrada_em = NOW() WHERE id = $1 AND encerrada_em IS NULL`, conversaID)
+	return err
+}
+
+// resumoQuery conversa com a contagem de turnos e a primeira fala do usuário
+const resumoQuery = `
+	SELECT c.id, c.usuario_id, COALESCE(c.stt_provider, ''), COALESCE(c.llm_provider, ''), COALESCE(c.tts_provider, ''),
+	       c.cenario_id, COALESCE(c.idioma, ''), c.iniciada_em, c.encerrada_em,
+	       (SELECT COUNT(*) FROM conversa_turnos t WHERE t.conversa_id = c.id) AS total_turnos,
+	       COALESCE((
+	           SELECT t.conteudo FROM conversa_turnos t
+	           WHERE t.conversa_id = c.id AND t.papel = 'user'
+	           ORDER BY t.id LIMIT 1
+	       ), '') AS previa
+	FROM conversas c
+	WHERE c.usuario_id = $1
+`
+
+func scanConversa(row pgx.Row) (*conversation.Conversa, error) {
+	var c conversation.Conversa
+	err := row.Scan(
+		&c.ID, &c.UsuarioID, &c.STTProvider, &c.LLMProvider, &c.TTSProvider,
+		&c.CenarioID, &c.Idioma, &c.IniciadaEm, &c.EncerradaEm, &c.TotalTurnos, &c.Previa,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List retorna as conversas do usuário, mais recentes primeiro
+func (r *Repository) List(ctx context.Context, userID int, params conversation.ListParams) ([]conversation.Conversa, error) {
+	query := resumoQuery + `
+		ORDER BY c.iniciada_em DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversas := []conversation.Conversa{}
+	for rows.Next() {
+		c, err := scanConversa(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversas = append(conversas, *c)
+	}
+	return conversas, rows.Err()
+}
+
+// Get busca uma conversa do usuário
+func (r *Repository) Get(ctx context.Context, userID, conversaID int) (*conversation.Conversa, error) {
+	c, err := scanConversa(r.db.QueryRow(ctx, resumoQuery+` AND c.id = $2`, userID, conversaID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, conversation.ErrNotFound
+	}
+	return c, err
+}
+
+const turnoColumns = `id, papel, conteudo, iniciado_em, duracao_ms, ttfa_ms, interrompido`
+
+func scanTurno(row pgx.Row) (*conversation.Turno, error) {
+	var t conversation.Turno
+	if err := row.Scan(&t.ID, &t.Papel, &t.Conteudo, &t.IniciadoEm, &t.DuracaoMs, &t.TTFAMs, &t.Interrompido); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTurnos retorna os turnos da conversa em ordem
+func (r *Repository) ListTurnos(ctx context.Context, conversaID int) ([]conversation.Turno, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+turnoColumns+` FROM conversa_turnos WHERE conversa_id = $1 ORDER BY id`, conversaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	turnos := []conversation.Turno{}
+	for rows.Next() {
+		t, err := scanTurno(rows)
+		if err != nil {
+			return nil, err
+		}
+		turnos = append(turnos, *t)
+	}
+	return turnos, rows.Err()
+}
+
+// GetTurno busca um turno da conversa
+func (r *Repository) GetTurno(ctx context.Context, conversaID, turnoID int) (*conversation.Turno, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+turnoColumns+` FROM conversa_turnos WHERE conversa_id = $1 AND id = $2`, conversaID, turnoID)
+	t, err := scanTurno(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, conversation.ErrNotFound
+	}
+	return t, err
+}
+
+const correcaoColumns = `cc.id, cc.turno_id, cc.original, cc.corrigido, cc.explicacao, cc.frase_id`
+
+func scanCorrecao(row pgx.Row) (*conversation.Correcao, error) {
+	var c conversation.Correcao
+	if err := row.Scan(&c.ID, &c.TurnoID, &c.Original, &c.Corrigido, &c.Explicacao, &c.FraseID); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListCorrecoes retorna as correções de todos os turnos da conversa, em ordem
+func (r *Repository) ListCorrecoes(ctx context.Context, conversaID int) ([]conversation.Correcao, error) {
+	query := `
+		SELECT ` + correcaoColumns + `
+		FROM conversa_correcoes cc
+		JOIN conversa_turnos t ON t.id = cc.turno_id
+		WHERE t.conversa_id = $1
+		ORDER BY cc.turno_id, cc.id
+	`
+
+	rows, err := r.db.Query(ctx, query, conversaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	correcoes := []conversation.Correcao{}
+	for rows.Next() {
+		c, err := scanCorrecao(rows)
+		if err != nil {
+			return nil, err
+		}
+		correcoes = append(correcoes, *c)
+	}
+	return correcoes, rows.Err()
+}
+
+// GetCorrecao busca uma correção de uma conversa do usuário
+func (r *Repository) GetCorrecao(ctx context.Context, userID, correcaoID int) (*conversation.Correcao, error) {
+	query := `
+		SELECT ` + correcaoColumns + `
+		FROM conversa_correcoes cc
+		JOIN conversa_turnos t ON t.id = cc.turno_id
+		JOIN conversas c ON c.id = t.conversa_id
+		WHERE c.usuario_id = $1 AND cc.id = $2
+	`
+
+	c, err := scanCorrecao(r.db.QueryRow(ctx, query, userID, correcaoID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, conversation.ErrNotFound
+	}
+	return c, err
+}
+
+// SaveCorrecaoFrase cria a frase e os detalhes de uma correção e liga a correção a ela,
+// numa transação. A correção só é ligada se ainda não tem frase: quem chega depois
+// recebe ErrCorrectionSaved e nada do que inseriu fica gravado.
+func (r *Repository) SaveCorrecaoFrase(ctx context.Context, correcaoID int, frase *phrase.Phrase, detalhes *phrase.PhraseDetails) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := saveCorrecaoFrase(ctx, tx, correcaoID, frase, detalhes); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func saveCorrecaoFrase(ctx context.Context, tx pgx.Tx, correcaoID int, frase *phrase.Phrase, detalhes *phrase.PhraseDetails) error {
+	frases := phraseRepo.NewWithDB(tx)
+	if err := frases.Create(ctx, frase); err != nil {
+		return err
+	}
+	detalhes.FraseID = frase.ID
+	if err := frases.CreateDetails(ctx, detalhes); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE conversa_correcoes SET frase_id = $2 WHERE id = $1 AND frase_id IS NULL`, correcaoID, frase.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return conversation.ErrCorrectionSaved
+	}
+	return nil
+}
+
+// AddPalavrasAlvo grava as palavras do cenário usadas pela primeira vez no turno
+func (r *Repository) AddPalavrasAlvo(ctx context.Context, turnoID int, palavras []string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO conversa_palavras_alvo (turno_id, palavra)
+		SELECT $1, unnest($2::text[])
+	`, turnoID, palavras)
+	return err
+}
+
+// ListPalavrasAlvo retorna as palavras-alvo usadas em todos os turnos da conversa
+func (r *Repository) ListPalavrasAlvo(ctx context.Context, conversaID int) ([]conversation.PalavraAlvo, error) {
+	query := `
+		SELECT pa.turno_id, pa.palavra
+		FROM conversa_palavras_alvo pa
+		JOIN conversa_turnos t ON t.id = pa.turno_id
+		WHERE t.conversa_id = $1
+		ORDER BY pa.turno_id, pa.id
+	`
+
+	rows, err := r.db.Query(ctx, query, conversaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	palavras := []conversation.PalavraAlvo{}
+	for rows.Next() {
+		var p conversation.PalavraAlvo
+		if err := rows.Scan(&p.TurnoID, &p.Palavra); err != nil {
+			return nil, err
+		}
+		palavras = append(palavras, p)
+	}
+	return palavras, rows.Err()
+}
+
+const cenarioColumns = `id, slug, titulo, descricao, persona, palavras_alvo, nivel_cefr, idioma, voice_id`
+
+func scanCenario(row pgx.Row) (*conversation.Cenario, error) {
+	var c conversation.Cenario
+	err := row.Scan(&c.ID, &c.Slug, &c.Titulo, &c.Descricao, &c.Persona, &c.PalavrasAlvo, &c.NivelCEFR, &c.Idioma, &c.VoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListCenarios retorna os cenários ativos, do nível mais fácil ao mais difícil
+func (r *Repository) ListCenarios(ctx context.Context, params conversation.CenarioParams) ([]conversation.Cenario, error) {
+	query := `
+		SELECT ` + cenarioColumns + `
+		FROM cenarios
+		WHERE ativo
+		  AND ($1 = '' OR idioma IS NULL OR idioma = $1)
+		  AND ($2 = '' OR nivel_cefr = $2)
+		ORDER BY nivel_cefr NULLS FIRST, titulo
+	`
+
+	rows, err := r.db.Query(ctx, query, params.Idioma, params.Nivel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cenarios := []conversation.Cenario{}
+	for rows.Next() {
+		c, err := scanCenario(rows)
+		if err != nil {
+			return nil, err
+		}
+		cenarios = append(cenarios, *c)
+	}
+	return cenarios, rows.Err()
+}
+
+// GetCenario busca um cenário ativo
+func (r *Repository) GetCenario(ctx context.Context, id int) (*conversation.Cenario, error) {
+	c, err := scanCenario(r.db.QueryRow(ctx, `SELECT `+cenarioColumns+` FROM cenarios WHERE id = $1 AND ativo`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, conversation.ErrScenarioNotFound
+	}
+	return c, err
+}
+
+// GetIdioma busca um idioma ativo
+func (r *Repository) GetIdioma(ctx context.Context, id int) (*conversation.Idioma, error) {
+	var i conversation.Idioma
+	err := r.db.QueryRow(ctx, `SELECT id, codigo, nome FROM idiomas WHERE id = $1 AND ativo IS NOT FALSE`, id).Scan(&i.ID, &i.Codigo, &i.Nome)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, conversation.ErrLanguageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
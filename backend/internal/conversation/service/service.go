@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"extension-backend/internal/ai/processor"
+	"extension-backend/internal/audio"
+	"extension-backend/internal/conversation"
+	"extension-backend/internal/events"
+	"extension-backend/internal/phrase"
+	"extension-backend/internal/shared"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	// maxPhraseRunes mesmo limite do POST /phrases
+	maxPhraseRunes = 100
+)
+
+// PhraseCreator cria as frases salvas a partir das conversas (phrase.ServiceInterface)
+type PhraseCreator interface {
+	Create(ctx context.Context, input phrase.CreateInput) (*phrase.Phrase, error)
+}
+
+// Translator dispara a tradução da frase salva em background (processor.Processor)
+type Translator interface {
+	ProcessAsync(req processor.Request)
+}
+
+type Service struct {
+	repo       conversation.RepositoryInterface
+	phrases    PhraseCreator
+	translator Translator
+	events     events.Publisher
+}
+
+func New(repo conversation.RepositoryInterface) *Service {
+	return &Service{repo: repo}
+}
+
+// WithPhrases permite salvar trechos das conversas como frases
+func (s *Service) WithPhrases(phrases PhraseCreator) *Service {
+	s.phrases = phrases
+	return s
+}
+
+// WithEvents publica PhraseCreated para as frases criadas a partir das correções
+// (as de trechos passam pelo phrase.Service, que já publica)
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
+
+// WithTranslator traduz as frases salvas das conversas, como o POST /phrases
+func (s *Service) WithTranslator(translator Translator) *Service {
+	s.translator = translator
+	return s
+}
+
+// StartConversation abre a conversa de uma conexão do pipeline (audio.TranscriptRecorder)
+func (s *Service) StartConversation(ctx context.Context, userID int, providers audio.Providers, setup audio.ConversationSetup) (int, error) {
+	id, err := s.repo.Create(ctx, conversation.NovaConversa{
+		UsuarioID:   userID,
+		STTProvider: providers.STT,
+		LLMProvider: providers.LLM,
+		TTSProvider: providers.TTS,
+		CenarioID:   setup.ScenarioID,
+		Idioma:      setup.Language,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return id, nil
+}
+
+// RecordTurn grava um turno com os tempos medidos pelo pipeline e retorna o id
+func (s *Service) RecordTurn(ctx context.Context, conversationID int, turn audio.RecordedTurn) (int, error) {
+	t := conversation.Turno{
+		Papel:        turn.Role,
+		Conteudo:     turn.Content,
+		IniciadoEm:   turn.StartedAt,
+		DuracaoMs:    int(turn.Duration.Milliseconds()),
+		Interrompido: turn.Interrupted,
+	}
+	if turn.TTFA > 0 {
+		ttfa := int(turn.TTFA.Milliseconds())
+		t.TTFAMs = &ttfa
+	}
+	id, err := s.repo.AddTurno(ctx, conversationID, t)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save turn: %w", err)
+	}
+	return id, nil
+}
+
+// RecordCorrections grava as correções gramaticais de um turno do usuário e
+// retorna os ids (usados para salvar a correção como frase)
+func (s *Service) RecordCorrections(ctx context.Context, turnID int, corrections []audio.Correction) ([]int, error) {
+	correcoes := make([]conversation.Correcao, len(corrections))
+	for i, c := range corrections {
+		correcoes[i] = conversation.Correcao{Original: c.Original, Corrigido: c.Corrected, Explicacao: c.Explanation}
+	}
+	ids, err := s.repo.AddCorrecoes(ctx, turnID, correcoes)
+	if err != nil {
+		return ids, fmt.Errorf("failed to save corrections: %w", err)
+	}
+	return ids, nil
+}
+
+// RecordTargetWords grava as palavras do cenário usadas pela primeira vez num turno do usuário
+func (s *Service) RecordTargetWords(ctx context.Context, turnID int, words []string) error {
+	if err := s.repo.AddPalavrasAlvo(ctx, turnID, words); err != nil {
+		return fmt.Errorf("failed to save target words: %w", err)
+	}
+	return nil
+}
+
+// EndConversation marca o fim da conversa (cliente desconectou)
+func (s *Service) EndConversation(ctx context.Context, conversationID int) error {
+	return s.repo.End(ctx, conversationID)
+}
+
+// List retorna as conversas do usuário, mais recentes primeiro
+func (s *Service) List(ctx context.Context, userID int, params conversation.ListParams) ([]conversation.Conversa, error) {
+	if params.Limit <= 0 {
+		params.Limit = defaultLimit
+	}
+	params.Limit = min(params.Limit, maxLimit)
+	params.Offset = max(params.Offset, 0)
+
+	conversas, err := s.repo.List(ctx, userID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	return conversas, nil
+}
+
+// Get retorna a conversa com todos os turnos; ErrNotFound se não for do usuário
+func (s *Service) Get(ctx context.Context, userID, conversaID int) (*conversation.ConversaDetalhe, error) {
+	c, err := s.repo.Get(ctx, userID, conversaID)
+	if err != nil {
+		return nil, err
+	}
+
+	turnos, err := s.repo.ListTurnos(ctx, conversaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list turns: %w", err)
+	}
+	correcoes, err := s.repo.ListCorrecoes(ctx, conversaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corrections: %w", err)
+	}
+
+	palavras, err := s.repo.ListPalavrasAlvo(ctx, conversaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target words: %w", err)
+	}
+
+	porTurno := make(map[int][]conversation.Correcao)
+	for _, cc := range correcoes {
+		porTurno[cc.TurnoID] = append(porTurno[cc.TurnoID], cc)
+	}
+	palavrasPorTurno := make(map[int][]string)
+	for _, p := range palavras {
+		palavrasPorTurno[p.TurnoID] = append(palavrasPorTurno[p.TurnoID], p.Palavra)
+	}
+	for i := range turnos {
+		turnos[i].Correcoes = porTurno[turnos[i].ID]
+		turnos[i].PalavrasAlvo = palavrasPorTurno[turnos[i].ID]
+	}
+	return &conversation.ConversaDetalhe{Conversa: *c, Turnos: turnos}, nil
+}
+
+// SavePhrase salva um trecho de um turno (ou o turno inteiro) como frase do usuário.
+// A tradução parte do conteúdo salvo, não do corpo da request, e recebe o turno como
+// contexto do prompt; a frase em si não guarda o turno (frases não têm contexto).
+func (s *Service) SavePhrase(ctx context.Context, userID, conversaID int, input conversation.SavePhraseInput) (*phrase.Phrase, error) {
+	if s.phrases == nil {
+		return nil, errors.New("phrase service not configured")
+	}
+
+	if _, err := s.repo.Get(ctx, userID, conversaID); err != nil {
+		return nil, err
+	}
+	turno, err := s.repo.GetTurno(ctx, conversaID, input.TurnoID)
+	if err != nil {
+		return nil, err
+	}
+
+	conteudo := strings.TrimSpace(input.Conteudo)
+	if conteudo == "" {
+		conteudo = turno.Conteudo
+	} else if !strings.Contains(turno.Conteudo, conteudo) {
+		return nil, conversation.ErrInvalidSentence
+	}
+
+	idioma := input.IdiomaOrigem
+	if idioma == "" {
+		idioma = "en"
+	}
+	destino := input.IdiomaDestino
+	if destino == "" {
+		destino = "pt-BR"
+	}
+
+	conteudo = shared.TruncateRunes(conteudo, maxPhraseRunes)
+	created, err := s.phrases.Create(ctx, phrase.CreateInput{
+		UsuarioID:    userID,
+		Conteudo:     conteudo,
+		IdiomaOrigem: idioma,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.translator != nil {
+		s.translator.ProcessAsync(processor.Request{
+			PhraseID:      created.ID,
+			UserID:        userID,
+			Conteudo:      conteudo,
+			IdiomaOrigem:  idioma,
+			IdiomaDestino: destino,
+			Contexto:      turno.Conteudo,
+		})
+	}
+	return created, nil
+}
+
+// SaveCorrection salva a forma correta de uma correção como frase do usuário. O que foi
+// dito vai para o verso do card ("original → corrigido") junto com a explicação da regra.
+// A frase, os detalhes e a ligação com a correção são gravados numa transação.
+func (s *Service) SaveCorrection(ctx context.Context, userID, correcaoID int, input conversation.SaveCorrectionInput) (*phrase.Phrase, error) {
+	correcao, err := s.repo.GetCorrecao(ctx, userID, correcaoID)
+	if err != nil {
+		return nil, err
+	}
+	if correcao.FraseID != nil {
+		return nil, conversation.ErrCorrectionSaved
+	}
+
+	idioma := input.IdiomaOrigem
+	if idioma == "" {
+		idioma = "en"
+	}
+
+	frase := &phrase.Phrase{
+		UsuarioID:    userID,
+		Conteudo:     shared.TruncateRunes(correcao.Corrigido, maxPhraseRunes),
+		IdiomaOrigem: idioma,
+	}
+	detalhes := &phrase.PhraseDetails{
+		TraducaoCompleta: correcao.Original + " → " + correcao.Corrigido,
+		Explicacao:       correcao.Explicacao,
+	}
+	if err := s.repo.SaveCorrecaoFrase(ctx, correcao.ID, frase, detalhes); err != nil {
+		if errors.Is(err, conversation.ErrCorrectionSaved) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save correction as phrase: %w", err)
+	}
+
+	s.publishPhraseCreated(ctx, frase)
+	return frase, nil
+}
+
+// publishPhraseCreated avisa os assinantes (como o phrase.Service faz no Create);
+// falha no bus não desfaz a frase criada
+func (s *Service) publishPhraseCreated(ctx context.Context, p *phrase.Phrase) {
+	if s.events == nil {
+		return
+	}
+	e, err := events.New(events.PhraseCreated, p.UsuarioID, events.PhraseCreatedPayload{
+		PhraseID: p.ID,
+		Conteudo: p.Conteudo,
+		Idioma:   p.IdiomaOrigem,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Conversation] Failed to publish %s for phrase %d: %v", events.PhraseCreated, p.ID, err)
+	}
+}
+
+// ListScenarios retorna o catálogo de cenários ativos
+func (s *Service) ListScenarios(ctx context.Context, params conversation.CenarioParams) ([]conversation.Cenario, error) {
+	params.Idioma = strings.TrimSpace(params.Idioma)
+	cenarios, err := s.repo.ListCenarios(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenarios: %w", err)
+	}
+	return cenarios, nil
+}
+
+// GetScenario cenário escolhido no setup do pipeline (audio.ScenarioCatalog)
+func (s *Service) GetScenario(ctx context.Context, id int) (*audio.Scenario, error) {
+	c, err := s.repo.GetCenario(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &audio.Scenario{
+		ID:          c.ID,
+		Slug:        c.Slug,
+		Title:       c.Titulo,
+		Persona:     c.Persona,
+		Language:    deref(c.Idioma),
+		Level:       deref(c.NivelCEFR),
+		VoiceID:     deref(c.VoiceID),
+		TargetWords: c.PalavrasAlvo,
+	}, nil
+}
+
+// GetLanguage idioma escolhido no setup do pipeline (audio.ScenarioCatalog)
+func (s *Service) GetLanguage(ctx context.Context, id int) (*audio.Language, error) {
+	i, err := s.repo.GetIdioma(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &audio.Language{ID: i.ID, Code: i.Codigo, Name: i.Nome}, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,386 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"extension-backend/internal/ai/processor"
+	"extension-backend/internal/audio"
+	"extension-backend/internal/conversation"
+	"extension-backend/internal/conversation/repository"
+	"extension-backend/internal/conversation/service"
+	"extension-backend/internal/phrase"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type fakePhrases struct {
+	created []phrase.CreateInput
+}
+
+func (f *fakePhrases) Create(ctx context.Context, input phrase.CreateInput) (*phrase.Phrase, error) {
+	f.created = append(f.created, input)
+	return &phrase.Phrase{ID: 50, UsuarioID: input.UsuarioID, Conteudo: input.Conteudo}, nil
+}
+
+type fakeTranslator struct {
+	requests []processor.Request
+}
+
+func (f *fakeTranslator) ProcessAsync(req processor.Request) {
+	f.requests = append(f.requests, req)
+}
+
+func setupService(t *testing.T) (pgxmock.PgxPoolIface, *service.Service, *fakePhrases) {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	phrases := &fakePhrases{}
+	return mock, service.New(repository.New(mock)).WithPhrases(phrases), phrases
+}
+
+var conversaColumns = []string{
+	"id", "usuario_id", "stt_provider", "llm_provider", "tts_provider",
+	"cenario_id", "idioma", "iniciada_em", "encerrada_em", "total_turnos", "previa",
+}
+
+var turnoColumns = []string{"id", "papel", "conteudo", "iniciado_em", "duracao_ms", "ttfa_ms", "interrompido"}
+
+var correcaoColumns = []string{"id", "turno_id", "original", "corrigido", "explicacao", "frase_id"}
+
+func TestRecordTurn_SavesTimingsInMilliseconds(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	started := time.Now()
+	ttfa := 850
+	mock.ExpectQuery("INSERT INTO conversa_turnos").
+		WithArgs(3, "model", "Sure.", started, 1200, &ttfa, true).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(12))
+
+	id, err := svc.RecordTurn(context.Background(), 3, audio.RecordedTurn{
+		Role:        "model",
+		Content:     "Sure.",
+		StartedAt:   started,
+		Duration:    1200 * time.Millisecond,
+		TTFA:        850 * time.Millisecond,
+		Interrupted: true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != 12 {
+		t.Errorf("expected turn id 12, got %d", id)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRecordCorrections_ReturnsIDsInOrder(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("INSERT INTO conversa_correcoes").
+		WithArgs(12, "I goed", "I went", "Passado irregular.").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery("INSERT INTO conversa_correcoes").
+		WithArgs(12, "a apple", "an apple", "An antes de vogal.").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(5))
+
+	ids, err := svc.RecordCorrections(context.Background(), 12, []audio.Correction{
+		{Original: "I goed", Corrected: "I went", Explanation: "Passado irregular."},
+		{Original: "a apple", Corrected: "an apple", Explanation: "An antes de vogal."},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
+
+func TestGetConversation_OtherUserIsNotFound(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM conversas c WHERE c.usuario_id = \\$1 AND c.id = \\$2").
+		WithArgs(7, 3).
+		WillReturnRows(pgxmock.NewRows(conversaColumns))
+
+	_, err := svc.Get(context.Background(), 7, 3)
+	if !errors.Is(err, conversation.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetConversation_ReturnsTurnsInOrder(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	now := time.Now()
+	cenarioID := 1
+	mock.ExpectQuery("SELECT (.+) FROM conversas c WHERE c.usuario_id = \\$1 AND c.id = \\$2").
+		WithArgs(7, 3).
+		WillReturnRows(pgxmock.NewRows(conversaColumns).
+			AddRow(3, 7, "elevenlabs/scribe", "gemini/flash", "elevenlabs/v2", &cenarioID, "en", now, &now, 2, "hello"))
+	ttfa := 900
+	mock.ExpectQuery("SELECT (.+) FROM conversa_turnos WHERE conversa_id = \\$1 ORDER BY id").
+		WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(turnoColumns).
+			AddRow(1, "user", "hello", now, 300, nil, false).
+			AddRow(2, "model", "Hi there.", now, 1500, &ttfa, false))
+	mock.ExpectQuery("SELECT (.+) FROM conversa_correcoes cc JOIN conversa_turnos t (.+) WHERE t.conversa_id = \\$1").
+		WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(correcaoColumns).
+			AddRow(4, 1, "hello", "Hello!", "Saudação.", nil))
+	mock.ExpectQuery("SELECT (.+) FROM conversa_palavras_alvo pa JOIN conversa_turnos t (.+) WHERE t.conversa_id = \\$1").
+		WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"turno_id", "palavra"}).
+			AddRow(1, "menu"))
+
+	detalhe, err := svc.Get(context.Background(), 7, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if detalhe.TotalTurnos != 2 || detalhe.LLMProvider != "gemini/flash" || len(detalhe.Turnos) != 2 {
+		t.Fatalf("unexpected conversation: %+v", detalhe)
+	}
+	if detalhe.Turnos[0].TTFAMs != nil || *detalhe.Turnos[1].TTFAMs != 900 {
+		t.Errorf("unexpected turns: %+v", detalhe.Turnos)
+	}
+	if len(detalhe.Turnos[0].Correcoes) != 1 || detalhe.Turnos[1].Correcoes != nil {
+		t.Errorf("expected the correction on the user turn, got %+v", detalhe.Turnos)
+	}
+	if len(detalhe.Turnos[0].PalavrasAlvo) != 1 || *detalhe.CenarioID != 1 || detalhe.Idioma != "en" {
+		t.Errorf("expected scenario and target words, got %+v", detalhe)
+	}
+}
+
+func expectOwnedTurn(mock pgxmock.PgxPoolIface, conteudo string) {
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM conversas c WHERE c.usuario_id = \\$1 AND c.id = \\$2").
+		WithArgs(7, 3).
+		WillReturnRows(pgxmock.NewRows(conversaColumns).
+			AddRow(3, 7, "", "", "", nil, "", now, nil, 2, "hello"))
+	mock.ExpectQuery("SELECT (.+) FROM conversa_turnos WHERE conversa_id = \\$1 AND id = \\$2").
+		WithArgs(3, 2).
+		WillReturnRows(pgxmock.NewRows(turnoColumns).
+			AddRow(2, "model", conteudo, now, 1500, nil, false))
+}
+
+func TestSavePhrase_SentenceFromTurnWithTurnAsContext(t *testing.T) {
+	mock, svc, phrases := setupService(t)
+	defer mock.Close()
+
+	expectOwnedTurn(mock, "Hi there. How was your weekend?")
+
+	created, err := svc.SavePhrase(context.Background(), 7, 3, conversation.SavePhraseInput{
+		TurnoID:  2,
+		Conteudo: " How was your weekend? ",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.ID != 50 || len(phrases.created) != 1 {
+		t.Fatalf("expected phrase to be created, got %+v", phrases.created)
+	}
+	input := phrases.created[0]
+	if input.UsuarioID != 7 || input.Conteudo != "How was your weekend?" || input.IdiomaOrigem != "en" {
+		t.Errorf("unexpected phrase input: %+v", input)
+	}
+}
+
+func TestSavePhrase_WholeTurnIsTranslatedWithTurnAsContext(t *testing.T) {
+	mock, svc, phrases := setupService(t)
+	defer mock.Close()
+	translator := &fakeTranslator{}
+	svc.WithTranslator(translator)
+
+	turn := "I usually spend my weekends hiking in the mountains with my friends, and sometimes we camp near the lake until Sunday night."
+	expectOwnedTurn(mock, turn)
+
+	if _, err := svc.SavePhrase(context.Background(), 7, 3, conversation.SavePhraseInput{TurnoID: 2}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(translator.requests) != 1 {
+		t.Fatalf("expected one translation, got %d", len(translator.requests))
+	}
+	req := translator.requests[0]
+	if req.PhraseID != 50 || req.UserID != 7 || req.Contexto != turn || req.IdiomaOrigem != "en" || req.IdiomaDestino != "pt-BR" {
+		t.Errorf("unexpected translation request: %+v", req)
+	}
+	if req.Conteudo == "" || req.Conteudo != phrases.created[0].Conteudo || len([]rune(req.Conteudo)) > 100 {
+		t.Errorf("expected translation of the saved (truncated) content, got %q", req.Conteudo)
+	}
+}
+
+func TestSavePhrase_RejectsTextNotInTurn(t *testing.T) {
+	mock, svc, phrases := setupService(t)
+	defer mock.Close()
+
+	expectOwnedTurn(mock, "Hi there.")
+
+	_, err := svc.SavePhrase(context.Background(), 7, 3, conversation.SavePhraseInput{TurnoID: 2, Conteudo: "something else"})
+	if !errors.Is(err, conversation.ErrInvalidSentence) {
+		t.Errorf("expected ErrInvalidSentence, got %v", err)
+	}
+	if len(phrases.created) != 0 {
+		t.Errorf("expected no phrase, got %+v", phrases.created)
+	}
+}
+
+func expectOwnedCorrection(mock pgxmock.PgxPoolIface, fraseID *int) {
+	mock.ExpectQuery("SELECT (.+) FROM conversa_correcoes cc (.+) WHERE c.usuario_id = \\$1 AND cc.id = \\$2").
+		WithArgs(7, 4).
+		WillReturnRows(pgxmock.NewRows(correcaoColumns).
+			AddRow(4, 1, "I goed", "I went", "Passado irregular de go.", fraseID))
+}
+
+// expectCorrectionPhrase frase e detalhes da correção 4 gravados na transação
+func expectCorrectionPhrase(mock pgxmock.PgxPoolIface) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO frases").
+		WithArgs(7, "I went", "en", "", "").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "capturado_em"}).AddRow(50, time.Now()))
+	mock.ExpectQuery("INSERT INTO frase_detalhes").
+		WithArgs(50, "I goed → I went", "Passado irregular de go.", pgxmock.AnyArg(), "").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "processado_em"}).AddRow(1, time.Now()))
+}
+
+func TestSaveCorrection_CreatesPhraseWithExplanation(t *testing.T) {
+	mock, svc, phrases := setupService(t)
+	defer mock.Close()
+
+	expectOwnedCorrection(mock, nil)
+	expectCorrectionPhrase(mock)
+	mock.ExpectExec("UPDATE conversa_correcoes SET frase_id = \\$2 WHERE id = \\$1 AND frase_id IS NULL").
+		WithArgs(4, 50).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	created, err := svc.SaveCorrection(context.Background(), 7, 4, conversation.SaveCorrectionInput{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.ID != 50 || created.Conteudo != "I went" || created.IdiomaOrigem != "en" {
+		t.Errorf("unexpected phrase: %+v", created)
+	}
+	if len(phrases.created) != 0 {
+		t.Errorf("correction phrase must be created inside the transaction, got %+v", phrases.created)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSaveCorrection_ConcurrentSaveRollsBack(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	// Outra request ligou a correção entre a leitura e o UPDATE
+	expectOwnedCorrection(mock, nil)
+	expectCorrectionPhrase(mock)
+	mock.ExpectExec("UPDATE conversa_correcoes SET frase_id").
+		WithArgs(4, 50).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectRollback()
+
+	_, err := svc.SaveCorrection(context.Background(), 7, 4, conversation.SaveCorrectionInput{})
+	if !errors.Is(err, conversation.ErrCorrectionSaved) {
+		t.Errorf("expected ErrCorrectionSaved, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestSaveCorrection_AlreadySaved(t *testing.T) {
+	mock, svc, phrases := setupService(t)
+	defer mock.Close()
+
+	fraseID := 50
+	expectOwnedCorrection(mock, &fraseID)
+
+	_, err := svc.SaveCorrection(context.Background(), 7, 4, conversation.SaveCorrectionInput{})
+	if !errors.Is(err, conversation.ErrCorrectionSaved) {
+		t.Errorf("expected ErrCorrectionSaved, got %v", err)
+	}
+	if len(phrases.created) != 0 {
+		t.Errorf("expected no phrase, got %+v", phrases.created)
+	}
+}
+
+var cenarioColumns = []string{"id", "slug", "titulo", "descricao", "persona", "palavras_alvo", "nivel_cefr", "idioma", "voice_id"}
+
+func TestStartConversation_SavesScenarioAndLanguage(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("INSERT INTO conversas").
+		WithArgs(7, "stt", "llm", "tts", 3, "en").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(10))
+
+	id, err := svc.StartConversation(context.Background(), 7,
+		audio.Providers{STT: "stt", LLM: "llm", TTS: "tts"},
+		audio.ConversationSetup{ScenarioID: 3, Language: "en"})
+	if err != nil || id != 10 {
+		t.Fatalf("expected conversation 10, got %d (%v)", id, err)
+	}
+}
+
+func TestGetScenario_MapsCatalogRowForPipeline(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	nivel, idioma := "A2", "en"
+	mock.ExpectQuery("SELECT (.+) FROM cenarios WHERE id = \\$1 AND ativo").
+		WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(cenarioColumns).
+			AddRow(3, "restaurant-order", "Ordering at a restaurant", "", "You are a waiter.", []string{"menu", "bill"}, &nivel, &idioma, nil))
+
+	scenario, err := svc.GetScenario(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if scenario.Persona != "You are a waiter." || scenario.Level != "A2" || scenario.Language != "en" || scenario.VoiceID != "" || len(scenario.TargetWords) != 2 {
+		t.Errorf("unexpected scenario: %+v", scenario)
+	}
+}
+
+func TestGetScenario_InactiveIsNotFound(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM cenarios WHERE id = \\$1 AND ativo").
+		WithArgs(9).
+		WillReturnRows(pgxmock.NewRows(cenarioColumns))
+
+	_, err := svc.GetScenario(context.Background(), 9)
+	if !errors.Is(err, conversation.ErrScenarioNotFound) {
+		t.Errorf("expected ErrScenarioNotFound, got %v", err)
+	}
+}
+
+func TestListScenarios_FiltersByLanguageAndLevel(t *testing.T) {
+	mock, svc, _ := setupService(t)
+	defer mock.Close()
+
+	nivel := "B1"
+	mock.ExpectQuery("SELECT (.+) FROM cenarios WHERE ativo").
+		WithArgs("en", "B1").
+		WillReturnRows(pgxmock.NewRows(cenarioColumns).
+			AddRow(4, "job-interview", "Job interview", "", "You are a hiring manager.", []string{"salary"}, &nivel, nil, nil))
+
+	cenarios, err := svc.ListScenarios(context.Background(), conversation.CenarioParams{Idioma: " en ", Nivel: "B1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cenarios) != 1 || cenarios[0].Slug != "job-interview" {
+		t.Errorf("unexpected scenarios: %+v", cenarios)
+	}
+}
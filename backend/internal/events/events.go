@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Tipos de evento publicados no bus
+const (
+	TranslationCompleted = "translation.completed"
+	TranslationFailed    = "translation.failed"
+	PhraseCreated        = "phrase.created"
+	ReviewSubmitted      = "review.submitted"
+	ExerciseCompleted    = "exercise.completed"
+	StreakUpdated        = "streak.updated"
+	QuotaExceeded        = "quota.exceeded"
+	GroupChanged         = "group.changed"
+)
+
+// Event envelope comum a todos os eventos. O payload fica em JSON para que o
+// mesmo evento trafegue pelo bus local e pelo Redis Streams.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int             `json:"user_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// New monta um evento serializando o payload
+func New(eventType string, userID int, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:         newID(),
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Payload:    data,
+	}, nil
+}
+
+// Decode desserializa o payload no tipo esperado pelo assinante
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// TranslationCompletedPayload tradução salva com sucesso
+type TranslationCompletedPayload struct {
+	PhraseID    int               `json:"phrase_id"`
+	Translation string            `json:"translation"`
+	Explanation string            `json:"explanation,omitempty"`
+	Slices      map[string]string `json:"slices,omitempty"`
+	Model       string            `json:"model,omitempty"`
+}
+
+// TranslationFailedPayload tradução ou persistência falhou.
+// Reset indica que parciais já foram enviadas e o cliente deve descartá-las.
+type TranslationFailedPayload struct {
+	PhraseID int    `json:"phrase_id"`
+	Error    string `json:"error"`
+	Reset    bool   `json:"reset,omitempty"`
+}
+
+// PhraseCreatedPayload frase capturada pelo usuário
+type PhraseCreatedPayload struct {
+	PhraseID int    `json:"phrase_id"`
+	Conteudo string `json:"conteudo"`
+	Idioma   string `json:"idioma"`
+}
+
+// ReviewSubmittedPayload resposta a um flashcard do Anki
+type ReviewSubmittedPayload struct {
+	AnkiID         int    `json:"anki_id"`
+	FraseID        int    `json:"frase_id"`
+	Nota           int    `json:"nota"`
+	NovoIntervalo  int    `json:"novo_intervalo"`
+	Estado         string `json:"estado"`
+	ProximaRevisao string `json:"proxima_revisao"`
+	Due            *int   `json:"due,omitempty"` // cards para revisar agora, se conhecido
+}
+
+// ExerciseCompletedPayload exercício concluído pelo usuário
+type ExerciseCompletedPayload struct {
+	ExercicioID int `json:"exercicio_id"`
+	CatalogoID  int `json:"catalogo_id,omitempty"`
+	Score       int `json:"score,omitempty"`
+	Pontos      int `json:"pontos"`
+}
+
+// StreakUpdatedPayload nova ofensiva do usuário
+type StreakUpdatedPayload struct {
+	OfensivaDias   int `json:"ofensiva_dias"`
+	MelhorOfensiva int `json:"melhor_ofensiva"`
+}
+
+// QuotaExceededPayload cota de um recurso externo esgotada (ex: provider de IA)
+type QuotaExceededPayload struct {
+	Quota   string `json:"quota"`
+	Message string `json:"message"`
+}
+
+// Ações de GroupChangedPayload
+const (
+	GroupCreated       = "created"
+	GroupUpdated       = "updated"
+	GroupDeleted       = "deleted"
+	GroupPhraseAdded   = "phrase_added"
+	GroupPhraseRemoved = "phrase_removed"
+)
+
+// GroupChangedPayload grupo criado, alterado ou com frases adicionadas/removidas
+type GroupChangedPayload struct {
+	GrupoID   int    `json:"grupo_id"`
+	NomeGrupo string `json:"nome_grupo,omitempty"`
+	FraseID   int    `json:"frase_id,omitempty"`
+	Action    string `json:"action"`
+}
+
+// Handler processa um evento; erro faz o bus tentar de novo
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher publica eventos (o que os serviços precisam conhecer)
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Bus publica eventos e entrega cada um a todos os assinantes do tipo.
+// Cada assinante (name) recebe os eventos isoladamente: a falha de um não
+// afeta os outros.
+type Bus interface {
+	Publisher
+	Subscribe(eventType, name string, h Handler)
+	Close() error
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+// queueSize eventos pendentes por assinante antes de Publish bloquear
+const queueSize = 256
+
+// ErrClosed publicação depois do Close
+var ErrClosed = errors.New("event bus closed")
+
+type subscription struct {
+	eventType string
+	name      string
+	handler   Handler
+	queue     chan Event
+}
+
+// LocalBus bus em memória: cada assinante tem sua fila e seu worker, então
+// eventos chegam em ordem para cada assinante e um handler lento ou com falha
+// não atrasa os outros.
+type LocalBus struct {
+	policy RetryPolicy
+
+	mu     sync.RWMutex
+	subs   []*subscription
+	closed bool
+	done   chan struct{} // fechado pelo Close: libera quem espera fila cheia
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewLocal cria um bus em memória
+func NewLocal(policy RetryPolicy) *LocalBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LocalBus{policy: policy, done: make(chan struct{}), ctx: ctx, cancel: cancel}
+}
+
+// Subscribe registra um handler para um tipo de evento
+func (b *LocalBus) Subscribe(eventType, name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	sub := &subscription{eventType: eventType, name: name, handler: h, queue: make(chan Event, queueSize)}
+	b.subs = append(b.subs, sub)
+
+	b.wg.Add(1)
+	go b.worker(sub)
+}
+
+// Publish enfileira o evento para todos os assinantes do tipo. A lista é copiada
+// sob o lock e o envio acontece fora dele: um handler que publica com a fila cheia
+// não trava o Close nem os outros publicadores.
+func (b *LocalBus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	var subs []*subscription
+	for _, sub := range b.subs {
+		if sub.eventType == e.Type {
+			subs = append(subs, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.queue <- e:
+		case <-b.done:
+			return ErrClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close para de aceitar eventos e espera os workers esvaziarem as filas
+func (b *LocalBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	b.cancel()
+	return nil
+}
+
+// worker entrega os eventos da fila; depois do Close entrega o que já estava nela e sai
+func (b *LocalBus) worker(sub *subscription) {
+	defer b.wg.Done()
+	for {
+		select {
+		case e := <-sub.queue:
+			b.deliver(sub, e)
+		case <-b.done:
+			for {
+				select {
+				case e := <-sub.queue:
+					b.deliver(sub, e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *LocalBus) deliver(sub *subscription, e Event) {
+	if err := b.policy.run(b.ctx, sub.name, sub.handler, e); err != nil {
+		log.Printf("[Events] Dropping %s %s for handler %s: %v", e.Type, e.ID, sub.name, err)
+	}
+}
@@ -0,0 +1,216 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	streamPrefix = "events:"
+	deadStream   = "events:dead"
+	streamMaxLen = 10000
+	readBlock    = 2 * time.Second
+	readCount    = 20
+
+	// Mensagens sem ACK há mais de claimIdle (consumidor que caiu, ou instância que
+	// não voltou) são assumidas por quem estiver consumindo; conferido a cada claimEvery
+	claimIdle  = time.Minute
+	claimEvery = 30 * time.Second
+)
+
+// RedisBus bus sobre Redis Streams: um stream por tipo de evento e um consumer
+// group por assinante. Eventos sobrevivem a restarts e são divididos entre as
+// instâncias da API que assinam com o mesmo nome: o consumidor tem o nome do
+// host (o mesmo depois de um restart) e pendências paradas de qualquer
+// consumidor são reassumidas com XAUTOCLAIM. Eventos que esgotam as
+// tentativas vão para o stream events:dead.
+type RedisBus struct {
+	rdb      *redis.Client
+	policy   RetryPolicy
+	consumer string
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRedis cria um bus sobre Redis Streams
+func NewRedis(rdb *redis.Client, policy RetryPolicy) *RedisBus {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "api"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisBus{
+		rdb:      rdb,
+		policy:   policy,
+		consumer: host,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Publish adiciona o evento ao stream do tipo
+func (b *RedisBus) Publish(ctx context.Context, e Event) error {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamPrefix + e.Type,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]any{"event": data},
+	}).Err()
+}
+
+// Subscribe cria (se preciso) o consumer group e começa a consumir o stream
+func (b *RedisBus) Subscribe(eventType, name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	stream := streamPrefix + eventType
+	err := b.rdb.XGroupCreateMkStream(b.ctx, stream, name, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		log.Printf("[Events] Failed to create group %s on %s: %v", name, stream, err)
+		return
+	}
+
+	b.wg.Add(1)
+	go b.consume(stream, name, h)
+}
+
+// Close para os consumidores e espera o processamento em andamento terminar
+func (b *RedisBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
+
+func (b *RedisBus) consume(stream, group string, h Handler) {
+	defer b.wg.Done()
+
+	// Primeiro reprocessa o que ficou pendente (sem ACK) deste consumidor, depois lê novos
+	lastID := "0"
+	var lastClaim time.Time
+	for b.ctx.Err() == nil {
+		if time.Since(lastClaim) >= claimEvery {
+			b.claim(stream, group, h)
+			lastClaim = time.Now()
+		}
+
+		res, err := b.rdb.XReadGroup(b.ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{stream, lastID},
+			Count:    readCount,
+			Block:    readBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			log.Printf("[Events] Failed to read %s (group %s): %v", stream, group, err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var msgs []redis.XMessage
+		for _, s := range res {
+			msgs = append(msgs, s.Messages...)
+		}
+		if len(msgs) == 0 && lastID == "0" {
+			lastID = ">"
+			continue
+		}
+
+		for _, msg := range msgs {
+			b.handle(stream, group, h, msg)
+		}
+	}
+}
+
+// claim assume e processa as mensagens paradas há mais de claimIdle em outros
+// consumidores do grupo (ex: instância que caiu e não voltou com o mesmo nome)
+func (b *RedisBus) claim(stream, group string, h Handler) {
+	start := "0-0"
+	for b.ctx.Err() == nil {
+		msgs, next, err := b.rdb.XAutoClaim(b.ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: b.consumer,
+			MinIdle:  claimIdle,
+			Start:    start,
+			Count:    readCount,
+		}).Result()
+		if err != nil {
+			if b.ctx.Err() == nil {
+				log.Printf("[Events] Failed to claim pending on %s (group %s): %v", stream, group, err)
+			}
+			return
+		}
+		for _, msg := range msgs {
+			b.handle(stream, group, h, msg)
+		}
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+func (b *RedisBus) handle(stream, group string, h Handler, msg redis.XMessage) {
+	raw, _ := msg.Values["event"].(string)
+
+	var e Event
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		log.Printf("[Events] Invalid message %s on %s: %v", msg.ID, stream, err)
+	} else if err := b.policy.run(b.ctx, group, h, e); err != nil {
+		if b.ctx.Err() != nil {
+			// Desligando: sem ACK, a mensagem é reprocessada no próximo start
+			return
+		}
+		log.Printf("[Events] Moving %s %s to %s after handler %s failed: %v", e.Type, e.ID, deadStream, group, err)
+		b.rdb.XAdd(context.Background(), &redis.XAddArgs{
+			Stream: deadStream,
+			MaxLen: streamMaxLen,
+			Approx: true,
+			Values: map[string]any{"event": raw, "handler": group, "error": err.Error()},
+		})
+	}
+
+	if err := b.rdb.XAck(context.Background(), stream, group, msg.ID).Err(); err != nil {
+		log.Printf("[Events] Failed to ack %s on %s: %v", msg.ID, stream, err)
+	}
+}
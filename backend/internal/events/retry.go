@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RetryPolicy quantas vezes um handler é executado e o intervalo inicial entre tentativas
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration // dobra a cada tentativa
+}
+
+// DefaultRetryPolicy 3 tentativas: imediata, +200ms, +400ms
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 200 * time.Millisecond}
+
+// run executa o handler com retry; panics viram erro para não derrubar o worker
+func (p RetryPolicy) run(ctx context.Context, name string, h Handler, e Event) error {
+	attempts := max(p.MaxAttempts, 1)
+	backoff := p.Backoff
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = safeCall(ctx, h, e); err == nil {
+			return nil
+		}
+		log.Printf("[Events] Handler %s failed on %s %s (attempt %d/%d): %v", name, e.Type, e.ID, i, attempts, err)
+		if i == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+func safeCall(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, e)
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"extension-backend/internal/events"
+)
+
+var fastRetry = events.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+func publish(t *testing.T, bus events.Bus, eventType string, payload any) {
+	t.Helper()
+	e, err := events.New(eventType, 7, payload)
+	if err != nil {
+		t.Fatalf("failed to build event: %v", err)
+	}
+	if err := bus.Publish(context.Background(), e); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+}
+
+func TestLocalBus_DeliversToSubscribersOfType(t *testing.T) {
+	bus := events.NewLocal(fastRetry)
+
+	var got events.TranslationCompletedPayload
+	var userID int
+	var other atomic.Int32
+	bus.Subscribe(events.TranslationCompleted, "a", func(ctx context.Context, e events.Event) error {
+		userID = e.UserID
+		return e.Decode(&got)
+	})
+	bus.Subscribe(events.PhraseCreated, "b", func(ctx context.Context, e events.Event) error {
+		other.Add(1)
+		return nil
+	})
+
+	publish(t, bus, events.TranslationCompleted, events.TranslationCompletedPayload{PhraseID: 3, Translation: "olá"})
+	bus.Close()
+
+	if userID != 7 || got.PhraseID != 3 || got.Translation != "olá" {
+		t.Errorf("unexpected delivery: user %d payload %+v", userID, got)
+	}
+	if other.Load() != 0 {
+		t.Errorf("expected phrase.created handler not to run, ran %d time(s)", other.Load())
+	}
+}
+
+func TestLocalBus_RetriesFailedHandler(t *testing.T) {
+	bus := events.NewLocal(fastRetry)
+
+	var calls atomic.Int32
+	bus.Subscribe(events.ReviewSubmitted, "flaky", func(ctx context.Context, e events.Event) error {
+		if calls.Add(1) < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	publish(t, bus, events.ReviewSubmitted, events.ReviewSubmittedPayload{AnkiID: 1, Nota: 3})
+	bus.Close()
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestLocalBus_IsolatesHandlers(t *testing.T) {
+	bus := events.NewLocal(fastRetry)
+
+	var mu sync.Mutex
+	var received []int
+	bus.Subscribe(events.PhraseCreated, "broken", func(ctx context.Context, e events.Event) error {
+		panic("boom")
+	})
+	bus.Subscribe(events.PhraseCreated, "healthy", func(ctx context.Context, e events.Event) error {
+		var p events.PhraseCreatedPayload
+		if err := e.Decode(&p); err != nil {
+			return err
+		}
+		mu.Lock()
+		received = append(received, p.PhraseID)
+		mu.Unlock()
+		return nil
+	})
+
+	for id := 1; id <= 3; id++ {
+		publish(t, bus, events.PhraseCreated, events.PhraseCreatedPayload{PhraseID: id})
+	}
+	bus.Close()
+
+	if len(received) != 3 || received[0] != 1 || received[2] != 3 {
+		t.Errorf("expected healthy handler to get 1,2,3 in order, got %v", received)
+	}
+}
+
+func TestLocalBus_PublishAfterClose(t *testing.T) {
+	bus := events.NewLocal(fastRetry)
+	bus.Close()
+
+	e, _ := events.New(events.PhraseCreated, 1, events.PhraseCreatedPayload{PhraseID: 1})
+	if err := bus.Publish(context.Background(), e); !errors.Is(err, events.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestLocalBus_CloseWithHandlerPublishingIntoFullQueue(t *testing.T) {
+	bus := events.NewLocal(fastRetry)
+
+	gate := make(chan struct{})
+	publishing := make(chan struct{})
+	var first atomic.Bool
+	bus.Subscribe(events.PhraseCreated, "echo", func(ctx context.Context, e events.Event) error {
+		if first.CompareAndSwap(false, true) {
+			<-gate
+			// Publica de dentro do handler com a própria fila cheia
+			again, _ := events.New(events.PhraseCreated, 1, events.PhraseCreatedPayload{PhraseID: 0})
+			close(publishing)
+			bus.Publish(ctx, again)
+		}
+		return nil
+	})
+
+	// Um evento no handler e a fila (256) cheia
+	for id := 1; id <= 257; id++ {
+		publish(t, bus, events.PhraseCreated, events.PhraseCreatedPayload{PhraseID: id})
+	}
+	close(gate)
+	<-publishing
+	time.Sleep(20 * time.Millisecond) // handler bloqueado no envio
+
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close deadlocked with a handler publishing into its full queue")
+	}
+}
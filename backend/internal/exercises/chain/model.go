@@ -0,0 +1,101 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Autores de um turno
+const (
+	AutorUsuario = "usuario"
+	AutorIA      = "ia"
+)
+
+// Status de uma sessão
+const (
+	StatusAtiva      = "ativa"
+	StatusFinalizada = "finalizada"
+)
+
+// Limites da sessão
+const (
+	DefaultMaxPalavras = 16
+	MinMaxPalavras     = 6
+	MaxMaxPalavras     = 40
+	maxPalavraLength   = 30
+
+	sessionTTL  = 30 * time.Minute
+	finishedTTL = 10 * time.Minute
+	lockTTL     = time.Minute // Turn/Finish chamam o LLM com a sessão travada
+	keyPrefix   = "chain:session:"
+)
+
+var (
+	ErrSessionNotFound = errors.New("chain session not found")
+	ErrSessionFinished = errors.New("chain session already finished")
+	ErrSessionFull     = errors.New("chain session reached max length")
+	ErrSessionBusy     = errors.New("chain session is being updated")
+	ErrInvalidWord     = errors.New("invalid word")
+	ErrNotChainCatalog = errors.New("exercise is not a sentence chain")
+)
+
+// Store é o subconjunto de cache.Client usado para guardar as sessões.
+// Get deve retornar redis.Nil quando a chave não existir.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	DeleteIfEquals(ctx context.Context, key, value string) (bool, error)
+}
+
+// Turno uma palavra adicionada à frase
+type Turno struct {
+	Autor   string    `json:"autor"`
+	Palavra string    `json:"palavra"`
+	Em      time.Time `json:"em"`
+}
+
+// Resultado avaliação do juiz ao finalizar
+type Resultado struct {
+	Score          int    `json:"score"` // 0-100
+	Gramatical     bool   `json:"gramatical"`
+	FraseCorrigida string `json:"frase_corrigida,omitempty"`
+	Feedback       string `json:"feedback,omitempty"`
+	Pontos         int    `json:"pontos"`
+}
+
+// Session sessão de SentenceChain guardada no Redis
+type Session struct {
+	ID             string     `json:"id"`
+	UsuarioID      int        `json:"usuario_id"`
+	ExercicioID    int        `json:"exercicio_id"`
+	CatalogoID     int        `json:"catalogo_id"`
+	PalavraInicial string     `json:"palavra_inicial"`
+	MaxPalavras    int        `json:"max_palavras"`
+	Turnos         []Turno    `json:"turnos"`
+	Status         string     `json:"status"`
+	Resultado      *Resultado `json:"resultado,omitempty"`
+	CriadaEm       time.Time  `json:"criada_em"`
+	AtualizadaEm   time.Time  `json:"atualizada_em"`
+}
+
+// StartInput body do POST /exercises/chain/sessions
+type StartInput struct {
+	ExercicioID int `json:"exercicio_id"`
+	MaxPalavras int `json:"max_palavras"`
+}
+
+// TurnInput body do POST /exercises/chain/sessions/{id}/turn
+type TurnInput struct {
+	Palavra string `json:"palavra"`
+}
+
+// TurnResult resposta de um turno
+type TurnResult struct {
+	Session   *Session `json:"session"`
+	Frase     string   `json:"frase"`
+	PalavraIA string   `json:"palavra_ia,omitempty"`
+	Completa  bool     `json:"completa"` // atingiu max_palavras
+}
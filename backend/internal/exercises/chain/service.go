@@ -0,0 +1,362 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/events"
+	"extension-backend/internal/exercises"
+	"extension-backend/internal/exercises/generator"
+	"extension-backend/internal/shared"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Player escolhe a próxima palavra da IA (ai.Service)
+type Player interface {
+	ChainNextWord(ctx context.Context, req ai.ChainRequest) (*ai.ChainResponse, error)
+}
+
+// Service gerencia sessões de SentenceChain: turnos, avaliação e pontuação
+type Service struct {
+	store  Store
+	repo   exercises.RepositoryInterface
+	player Player
+	judge  generator.LLM
+	events events.Publisher
+}
+
+// New cria um novo Service
+func New(store Store, repo exercises.RepositoryInterface, player Player, judge generator.LLM) *Service {
+	return &Service{store: store, repo: repo, player: player, judge: judge}
+}
+
+// WithEvents publica ExerciseCompleted quando uma sessão é finalizada
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
+
+// Start abre uma sessão a partir de um exercício do catálogo sentencechain
+func (s *Service) Start(ctx context.Context, userID int, input StartInput) (*Session, error) {
+	ex, err := s.repo.GetByID(ctx, input.ExercicioID)
+	if err != nil {
+		return nil, fmt.Errorf("exercise not found: %w", err)
+	}
+	if ex.UsuarioID != nil && *ex.UsuarioID != userID {
+		return nil, fmt.Errorf("exercise not found")
+	}
+
+	catalogo, err := s.repo.GetCatalogoByID(ctx, ex.CatalogoID)
+	if err != nil {
+		return nil, fmt.Errorf("catalogo %d not found: %w", ex.CatalogoID, err)
+	}
+	if generator.NormalizeCatalogName(catalogo.Nome) != "sentencechain" {
+		return nil, ErrNotChainCatalog
+	}
+
+	inicial, _ := ex.DadosExercicio["palavra_inicial"].(string)
+	inicial = strings.TrimSpace(inicial)
+	if inicial == "" {
+		return nil, fmt.Errorf("exercise %d has no palavra_inicial", ex.ID)
+	}
+
+	maxPalavras := input.MaxPalavras
+	if maxPalavras <= 0 {
+		maxPalavras = DefaultMaxPalavras
+	}
+	maxPalavras = min(max(maxPalavras, MinMaxPalavras), MaxMaxPalavras)
+
+	now := time.Now()
+	session := &Session{
+		ID:             shared.GenerateToken(16),
+		UsuarioID:      userID,
+		ExercicioID:    ex.ID,
+		CatalogoID:     ex.CatalogoID,
+		PalavraInicial: inicial,
+		MaxPalavras:    maxPalavras,
+		Turnos:         []Turno{},
+		Status:         StatusAtiva,
+		CriadaEm:       now,
+		AtualizadaEm:   now,
+	}
+	if err := s.save(ctx, session, sessionTTL); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Chain] Session %s started for user %d (exercise %d)", session.ID, userID, ex.ID)
+	return session, nil
+}
+
+// Get retorna uma sessão do usuário
+func (s *Service) Get(ctx context.Context, userID int, sessionID string) (*Session, error) {
+	data, err := s.store.Get(ctx, keyPrefix+sessionID)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chain session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("failed to decode chain session: %w", err)
+	}
+	// Sessão de outro usuário é tratada como inexistente
+	if session.UsuarioID != userID {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+// Turn registra a palavra do usuário e, se ainda houver espaço, a resposta da IA
+func (s *Service) Turn(ctx context.Context, userID int, sessionID string, input TurnInput) (*TurnResult, error) {
+	unlock, err := s.lock(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := s.Get(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != StatusAtiva {
+		return nil, ErrSessionFinished
+	}
+	if len(session.Turnos) >= session.MaxPalavras {
+		return nil, ErrSessionFull
+	}
+
+	palavra, err := ValidateWord(input.Palavra)
+	if err != nil {
+		return nil, err
+	}
+	session.addTurn(AutorUsuario, palavra)
+
+	result := &TurnResult{Session: session}
+	if len(session.Turnos) < session.MaxPalavras {
+		resp, err := s.player.ChainNextWord(ctx, ai.ChainRequest{SentenceSoFar: session.Frase()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AI word: %w", err)
+		}
+		// O modelo às vezes devolve mais de uma palavra; fica só com a primeira válida
+		fields := strings.Fields(resp.NextWord)
+		if len(fields) > 0 {
+			if iaWord, err := ValidateWord(fields[0]); err == nil {
+				session.addTurn(AutorIA, iaWord)
+				result.PalavraIA = iaWord
+			}
+		}
+	}
+
+	if err := s.save(ctx, session, sessionTTL); err != nil {
+		return nil, err
+	}
+
+	result.Frase = session.Frase()
+	result.Completa = len(session.Turnos) >= session.MaxPalavras
+	return result, nil
+}
+
+// Finish encerra a sessão, pede a nota ao juiz e credita proef_base proporcional à nota.
+// Chamadas repetidas devolvem o mesmo resultado sem pontuar de novo.
+func (s *Service) Finish(ctx context.Context, userID int, sessionID string) (*Session, error) {
+	session, err := s.Get(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == StatusFinalizada {
+		return session, nil
+	}
+
+	// Mesma trava do Turn: um turno em andamento não regrava a sessão como ativa
+	unlock, err := s.lock(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err = s.Get(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == StatusFinalizada {
+		return session, nil
+	}
+
+	// Marca de pontuação: dura tanto quanto a sessão ativa, então nunca expira antes
+	// dela e a sessão não pode ser pontuada de novo se gravar o resultado falhar
+	finishKey := keyPrefix + sessionID + ":finish"
+	ok, err := s.store.SetNX(ctx, finishKey, "1", sessionTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark chain session as finished: %w", err)
+	}
+	if !ok {
+		return nil, ErrSessionFinished
+	}
+
+	resultado, err := s.score(ctx, userID, session)
+	if err != nil {
+		// Nada foi creditado: libera para o usuário tentar de novo
+		if delErr := s.store.Delete(ctx, finishKey); delErr != nil {
+			log.Printf("[Chain] Failed to release finish mark for session %s: %v", sessionID, delErr)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.MarkExerciseAsViewed(ctx, userID, session.ExercicioID); err != nil {
+		log.Printf("[Chain] Failed to mark exercise %d as viewed: %v", session.ExercicioID, err)
+	}
+
+	session.Status = StatusFinalizada
+	session.Resultado = resultado
+	session.AtualizadaEm = time.Now()
+	if err := s.save(ctx, session, finishedTTL); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Chain] Session %s finished: score=%d pontos=%d", session.ID, resultado.Score, resultado.Pontos)
+	s.publishCompleted(ctx, userID, session)
+	return session, nil
+}
+
+// publishCompleted avisa os assinantes; falha no bus não desfaz a pontuação
+func (s *Service) publishCompleted(ctx context.Context, userID int, session *Session) {
+	if s.events == nil {
+		return
+	}
+	e, err := events.New(events.ExerciseCompleted, userID, events.ExerciseCompletedPayload{
+		ExercicioID: session.ExercicioID,
+		CatalogoID:  session.CatalogoID,
+		Score:       session.Resultado.Score,
+		Pontos:      session.Resultado.Pontos,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Chain] Failed to publish %s for session %s: %v", events.ExerciseCompleted, session.ID, err)
+	}
+}
+
+// score avalia a frase e credita os pontos do catálogo
+func (s *Service) score(ctx context.Context, userID int, session *Session) (*Resultado, error) {
+	resultado, err := s.evaluate(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	if resultado.Score == 0 {
+		return resultado, nil
+	}
+
+	rec, err := s.repo.GetRecompensa(ctx, session.CatalogoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalog reward: %w", err)
+	}
+	resultado.Pontos = Pontos(rec, resultado.Score)
+	if resultado.Pontos > 0 {
+		if err := s.repo.AwardProficiency(ctx, userID, rec.TipoID, resultado.Pontos); err != nil {
+			return nil, fmt.Errorf("failed to award proficiency: %w", err)
+		}
+	}
+	return resultado, nil
+}
+
+// Frase monta a frase atual (palavra inicial + turnos)
+func (s *Session) Frase() string {
+	words := make([]string, 0, len(s.Turnos)+1)
+	words = append(words, s.PalavraInicial)
+	for _, t := range s.Turnos {
+		words = append(words, t.Palavra)
+	}
+	return strings.Join(words, " ")
+}
+
+// palavrasUsuario conta quantas palavras o usuário adicionou
+func (s *Session) palavrasUsuario() int {
+	n := 0
+	for _, t := range s.Turnos {
+		if t.Autor == AutorUsuario {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Session) addTurn(autor, palavra string) {
+	now := time.Now()
+	s.Turnos = append(s.Turnos, Turno{Autor: autor, Palavra: palavra, Em: now})
+	s.AtualizadaEm = now
+}
+
+// lock trava a sessão para um Turn ou Finish de cada vez; quem chega com a sessão
+// travada recebe ErrSessionBusy. A trava expira sozinha (lockTTL) se o processo cair.
+// Ela guarda um token do dono: se o LLM passar do lockTTL e outra request pegar a
+// trava, o unlock da primeira não apaga a da segunda.
+func (s *Service) lock(ctx context.Context, sessionID string) (func(), error) {
+	key := keyPrefix + sessionID + ":lock"
+	token := shared.GenerateToken(16)
+	ok, err := s.store.SetNX(ctx, key, token, lockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock chain session: %w", err)
+	}
+	if !ok {
+		return nil, ErrSessionBusy
+	}
+	return func() {
+		if _, err := s.store.DeleteIfEquals(context.WithoutCancel(ctx), key, token); err != nil {
+			log.Printf("[Chain] Failed to release lock for session %s: %v", sessionID, err)
+		}
+	}, nil
+}
+
+func (s *Service) save(ctx context.Context, session *Session, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode chain session: %w", err)
+	}
+	if err := s.store.Set(ctx, keyPrefix+session.ID, string(data), ttl); err != nil {
+		return fmt.Errorf("failed to save chain session: %w", err)
+	}
+	return nil
+}
+
+// ValidateWord aceita uma única palavra com ao menos uma letra.
+// Pontuação colada à palavra é permitida ("happy.").
+func ValidateWord(w string) (string, error) {
+	w = ai.NormalizeInput(w, 0)
+	if w == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidWord)
+	}
+	if strings.ContainsFunc(w, unicode.IsSpace) {
+		return "", fmt.Errorf("%w: only one word per turn", ErrInvalidWord)
+	}
+	if len([]rune(w)) > maxPalavraLength {
+		return "", fmt.Errorf("%w: too long", ErrInvalidWord)
+	}
+	if !strings.ContainsFunc(w, unicode.IsLetter) {
+		return "", fmt.Errorf("%w: must contain letters", ErrInvalidWord)
+	}
+	if strings.Contains(w, "<<<") || strings.Contains(w, ">>>") {
+		return "", fmt.Errorf("%w: forbidden characters", ErrInvalidWord)
+	}
+	return w, nil
+}
+
+// Pontos converte a nota (0-100) em proficiência: proef_base proporcional à nota,
+// mais proef_bonus quando a nota é 90 ou mais
+func Pontos(rec *exercises.Recompensa, score int) int {
+	pontos := (rec.ProefBase*score + 50) / 100
+	if score >= 90 {
+		pontos += rec.ProefBonus
+	}
+	return pontos
+}
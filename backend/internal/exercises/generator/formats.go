@@ -0,0 +1,285 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kind é o tipo JSON esperado de um campo de dados_exercicio
+type kind int
+
+const (
+	kindString kind = iota
+	kindNumber
+	kindBool
+	kindStringList
+	kindObject
+	kindObjectList
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindString:
+		return "string"
+	case kindNumber:
+		return "number"
+	case kindBool:
+		return "boolean"
+	case kindStringList:
+		return "array of strings"
+	case kindObject:
+		return "object"
+	case kindObjectList:
+		return "array of objects"
+	}
+	return "unknown"
+}
+
+// field descreve um campo do JSON de um formato de exercício
+type field struct {
+	name     string
+	kind     kind
+	optional bool
+	minItems int
+	fields   []field // para kindObject / kindObjectList
+}
+
+// Format descreve o shape de dados_exercicio de um catálogo
+type Format struct {
+	Catalogo  string // nome normalizado do catálogo (ex: "nexusconnect")
+	Descricao string // explicação para o modelo
+	Exemplo   string // exemplo JSON de um item
+	fields    []field
+	check     func(item map[string]interface{}) error
+}
+
+// formats são os catálogos que aceitam geração personalizada.
+// EchoWrite fica de fora pois depende de áudio gravado.
+var formats = map[string]Format{
+	"logicbreaker": {
+		Catalogo:  "logicbreaker",
+		Descricao: "A short text with a logical flaw. \"palavra_alvo\" is the exact excerpt of the text that breaks the logic; the learner has to find it.",
+		Exemplo:   `{"texto": "I was freezing, so I took off my coat.", "instrucao": "Ache a falha lógica", "palavra_alvo": "took off"}`,
+		fields: []field{
+			{name: "texto", kind: kindString},
+			{name: "instrucao", kind: kindString},
+			{name: "palavra_alvo", kind: kindString},
+		},
+		check: func(item map[string]interface{}) error {
+			return inText("texto", item["palavra_alvo"].(string))(item)
+		},
+	},
+	"claritysprint": {
+		Catalogo:  "claritysprint",
+		Descricao: "A text padded with noise words. \"palavras_erradas\" lists the exact noise excerpts the learner must remove; \"tempo_leitura\" is the time limit in seconds.",
+		Exemplo:   `{"instrucao": "Remova o ruído da frase", "texto_completo": "In my honest opinion, I truly believe that it is very important.", "palavras_erradas": ["honest", "truly", "very"], "tempo_leitura": 60}`,
+		fields: []field{
+			{name: "instrucao", kind: kindString},
+			{name: "texto_completo", kind: kindString},
+			{name: "palavras_erradas", kind: kindStringList, minItems: 1},
+			{name: "tempo_leitura", kind: kindNumber},
+		},
+		check: func(item map[string]interface{}) error {
+			for _, w := range item["palavras_erradas"].([]interface{}) {
+				if err := inText("texto_completo", w.(string))(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	"nexusconnect": {
+		Catalogo:  "nexusconnect",
+		Descricao: "A central word and options; the learner selects every option semantically connected to it.",
+		Exemplo:   `{"instrucao": "Conecte as palavras relacionadas", "palavra_central": "weather", "tema": "clima", "opcoes": [{"texto": "rain", "correta": true}, {"texto": "chair", "correta": false}]}`,
+		fields: []field{
+			{name: "instrucao", kind: kindString},
+			{name: "palavra_central", kind: kindString},
+			{name: "tema", kind: kindString, optional: true},
+			{name: "opcoes", kind: kindObjectList, minItems: 2, fields: []field{
+				{name: "texto", kind: kindString},
+				{name: "correta", kind: kindBool},
+			}},
+		},
+		check: func(item map[string]interface{}) error {
+			for _, o := range item["opcoes"].([]interface{}) {
+				if o.(map[string]interface{})["correta"] == true {
+					return nil
+				}
+			}
+			return fmt.Errorf("opcoes must contain at least one correct option")
+		},
+	},
+	"wordmemory": {
+		Catalogo:  "wordmemory",
+		Descricao: "A list of word pairs to memorize. \"en\" holds the word in the language being learned and \"pt\" its translation in the learner's native language.",
+		Exemplo:   `{"data": {"wordList": [{"en": "apple", "pt": "maçã"}, {"en": "bread", "pt": "pão"}, {"en": "milk", "pt": "leite"}], "timeLimit": 60}}`,
+		fields: []field{
+			{name: "data", kind: kindObject, fields: []field{
+				{name: "wordList", kind: kindObjectList, minItems: 3, fields: []field{
+					{name: "en", kind: kindString},
+					{name: "pt", kind: kindString},
+				}},
+				{name: "timeLimit", kind: kindNumber},
+			}},
+		},
+	},
+	"key": {
+		Catalogo:  "key",
+		Descricao: "The learner types a target word from its description using a virtual keyboard with distractor letters.",
+		Exemplo:   `{"instrucao": "Digite a palavra", "descricao": "A fruit that keeps the doctor away", "resposta": "apple", "distratores": "xqz", "tags": ["food"]}`,
+		fields: []field{
+			{name: "instrucao", kind: kindString},
+			{name: "descricao", kind: kindString},
+			{name: "resposta", kind: kindString},
+			{name: "distratores", kind: kindString},
+			{name: "tags", kind: kindStringList, optional: true},
+		},
+	},
+	"connection": {
+		Catalogo:  "connection",
+		Descricao: "A sentence with a gap for a connector word; the learner picks the right connector.",
+		Exemplo:   `{"data": {"sentence": "I stayed home ___ it was raining.", "connector": ["because", "but", "although"], "answer": "because", "time": "30"}}`,
+		fields: []field{
+			{name: "data", kind: kindObject, fields: []field{
+				{name: "sentence", kind: kindString},
+				{name: "connector", kind: kindStringList, minItems: 2},
+				{name: "answer", kind: kindString},
+				{name: "time", kind: kindString},
+			}},
+		},
+		check: func(item map[string]interface{}) error {
+			return answerInOptions("answer", "connector")(item["data"].(map[string]interface{}))
+		},
+	},
+	"sentencechain": {
+		Catalogo:  "sentencechain",
+		Descricao: "A cooperative sentence-building game that starts from one word.",
+		Exemplo:   `{"instrucao": "Construa uma frase alternando palavras com a IA", "palavra_inicial": "Yesterday"}`,
+		fields: []field{
+			{name: "instrucao", kind: kindString},
+			{name: "palavra_inicial", kind: kindString},
+		},
+	},
+}
+
+// aliases nomes de exibição que o catálogo grava com outro nome
+// (KeyBurst é "key" em exercicios_catalogo, como no keyMap do frontend)
+var aliases = map[string]string{
+	"keyburst": "key",
+}
+
+// NormalizeCatalogName converte "Nexus Connect" em "nexusconnect" (mesma regra do frontend)
+func NormalizeCatalogName(nome string) string {
+	return strings.ToLower(strings.Join(strings.Fields(nome), ""))
+}
+
+// FormatFor retorna o formato de um catálogo pelo nome
+func FormatFor(catalogoNome string) (Format, bool) {
+	nome := NormalizeCatalogName(catalogoNome)
+	if alias, ok := aliases[nome]; ok {
+		nome = alias
+	}
+	f, ok := formats[nome]
+	return f, ok
+}
+
+// Validate confere se um item gerado segue o shape do catálogo
+func (f Format) Validate(item map[string]interface{}) error {
+	if err := validateFields(item, f.fields, ""); err != nil {
+		return err
+	}
+	if f.check != nil {
+		return f.check(item)
+	}
+	return nil
+}
+
+func validateFields(obj map[string]interface{}, fields []field, prefix string) error {
+	for _, fd := range fields {
+		path := prefix + fd.name
+		v, ok := obj[fd.name]
+		if !ok || v == nil {
+			if fd.optional {
+				continue
+			}
+			return fmt.Errorf("missing field %q", path)
+		}
+		if err := validateValue(v, fd, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateValue(v interface{}, fd field, path string) error {
+	switch fd.kind {
+	case kindString:
+		s, ok := v.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return fmt.Errorf("field %q must be a non-empty %s", path, fd.kind)
+		}
+	case kindNumber:
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("field %q must be a %s", path, fd.kind)
+		}
+	case kindBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("field %q must be a %s", path, fd.kind)
+		}
+	case kindStringList:
+		list, ok := v.([]interface{})
+		if !ok || len(list) < fd.minItems {
+			return fmt.Errorf("field %q must be an %s with at least %d items", path, fd.kind, fd.minItems)
+		}
+		for i, e := range list {
+			if s, ok := e.(string); !ok || strings.TrimSpace(s) == "" {
+				return fmt.Errorf("field %s[%d] must be a non-empty string", path, i)
+			}
+		}
+	case kindObject:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("field %q must be an %s", path, fd.kind)
+		}
+		return validateFields(obj, fd.fields, path+".")
+	case kindObjectList:
+		list, ok := v.([]interface{})
+		if !ok || len(list) < fd.minItems {
+			return fmt.Errorf("field %q must be an %s with at least %d items", path, fd.kind, fd.minItems)
+		}
+		for i, e := range list {
+			obj, ok := e.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("field %s[%d] must be an object", path, i)
+			}
+			if err := validateFields(obj, fd.fields, fmt.Sprintf("%s[%d].", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// inText garante que o trecho aparece no campo de texto (sem diferenciar maiúsculas)
+func inText(textField, excerpt string) func(map[string]interface{}) error {
+	return func(item map[string]interface{}) error {
+		if !strings.Contains(strings.ToLower(item[textField].(string)), strings.ToLower(excerpt)) {
+			return fmt.Errorf("%q is not in %s", excerpt, textField)
+		}
+		return nil
+	}
+}
+
+// answerInOptions garante que a resposta correta está entre as opções
+func answerInOptions(answerField, optionsField string) func(map[string]interface{}) error {
+	return func(item map[string]interface{}) error {
+		answer := item[answerField].(string)
+		for _, o := range item[optionsField].([]interface{}) {
+			if o.(string) == answer {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s %q is not one of %s", answerField, answer, optionsField)
+	}
+}
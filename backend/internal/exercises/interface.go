@@ -10,13 +10,24 @@ type RepositoryInterface interface {
 	// Catálogo
 	ListCatalogo(ctx context.Context) ([]CatalogoItem, error)
 	GetCatalogoByTipo(ctx context.Context, tipoID int) ([]CatalogoItem, error)
+	GetCatalogoByID(ctx context.Context, id int) (*CatalogoItem, error)
+	GetCatalogoByNome(ctx context.Context, nome string) (*CatalogoItem, error)
 
 	// Exercícios individuais
 	GetByID(ctx context.Context, id int) (*Exercicio, error)
 	GetByCatalogoID(ctx context.Context, catalogoID int, limit int) ([]Exercicio, error)
 	GetByCatalogoAndUserLanguages(ctx context.Context, catalogoID int, userID int, limit int) ([]Exercicio, error)
+	GetByCatalogoAdaptive(ctx context.Context, catalogoID int, userID int, limit int, nivel string, alvo int) ([]Exercicio, error)
 	MarkExerciseAsViewed(ctx context.Context, userID int, exercicioID int) error
 	ListHistorias(ctx context.Context, userID int, limit int) ([]Exercicio, error)
+
+	// Exercícios gerados por usuário
+	Create(ctx context.Context, input NovoExercicio) (*Exercicio, error)
+	GetPerfilUsuario(ctx context.Context, userID int) (*PerfilUsuario, error)
+
+	// Pontuação
+	GetRecompensa(ctx context.Context, catalogoID int) (*Recompensa, error)
+	AwardProficiency(ctx context.Context, userID int, tipoID int, pontos int) error
 }
 
 // ServiceInterface define a lógica de negócio de exercícios
@@ -27,6 +38,9 @@ type ServiceInterface interface {
 	// Pega até N exercícios de um catálogo (quando clica num exercício)
 	GetExerciciosByCatalogo(ctx context.Context, catalogoID int, userID int, limit int) ([]Exercicio, error)
 
+	// Igual ao anterior, filtrando por nível CEFR ("" = adaptativo ao nível do usuário)
+	GetExerciciosByNivel(ctx context.Context, catalogoID int, userID int, limit int, nivel string) ([]Exercicio, error)
+
 	// Pega um exercício por ID
 	GetByID(ctx context.Context, id int) (*Exercicio, error)
 
@@ -28,9 +28,43 @@ type Exercicio struct {
 	CatalogoID     int                    `json:"catalogo_id"`
 	DadosExercicio map[string]interface{} `json:"dados_exercicio"`
 	Nivel          int                    `json:"nivel"`
+	NivelCEFR      *string                `json:"nivel_cefr,omitempty"`
 	CriadoEm       time.Time             `json:"criado_em"`
 }
 
+// NovoExercicio dados para inserir um exercício gerado para um usuário
+type NovoExercicio struct {
+	UsuarioID      int
+	CatalogoID     int
+	DadosExercicio map[string]interface{}
+	Nivel          int
+	NivelCEFR      string // "" quando não estimado
+	IdiomaID       int
+	IdiomaIDOrigem int
+}
+
+// PerfilUsuario idiomas e nível do usuário, usados para gerar exercícios personalizados
+type PerfilUsuario struct {
+	UsuarioID           int    `json:"usuario_id"`
+	IdiomaOrigemID      int    `json:"idioma_origem_id"`
+	IdiomaOrigem        string `json:"idioma_origem"`
+	IdiomaAprendizadoID int    `json:"idioma_aprendizado_id"`
+	IdiomaAprendizado   string `json:"idioma_aprendizado"`
+	NivelProficiencia   string `json:"nivel_proficiencia"`
+}
+
+// NivelNumerico converte o nível de proficiência textual para exercicios.nivel
+func (p PerfilUsuario) NivelNumerico() int {
+	switch p.NivelProficiencia {
+	case "beginner":
+		return 1
+	case "advanced":
+		return 3
+	default:
+		return 2
+	}
+}
+
 // ── Response DTOs para o frontend ──────────────────────────────
 
 // CatalogoItem junta catálogo + tipo para listagem no frontend
@@ -49,3 +83,11 @@ type TipoComCatalogo struct {
 	Tipo      TipoExercicio   `json:"tipo"`
 	Catalogos []CatalogoItem  `json:"catalogos"`
 }
+
+// Recompensa pontuação de proficiência configurada no catálogo (exercicios_catalogo.proef_*)
+type Recompensa struct {
+	CatalogoID int `json:"catalogo_id"`
+	TipoID     int `json:"tipo_id"`
+	ProefBase  int `json:"proef_base"`
+	ProefBonus int `json:"proef_bonus"`
+}
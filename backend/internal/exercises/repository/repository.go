@@ -227,6 +227,55 @@ func (r *Repository) GetByCatalogoAndUserLanguages(ctx context.Context, catalogo
 	return list, rows.Err()
 }
 
+// GetByCatalogoAdaptive igual a GetByCatalogoAndUserLanguages, mas com nível CEFR:
+// filtra por `nivel` (se informado) e prioriza exercícios mais próximos do nível-alvo
+// (`alvo`, 1=A1 … 6=C2). Exercícios ainda sem classificação contam como no alvo.
+func (r *Repository) GetByCatalogoAdaptive(ctx context.Context, catalogoID int, userID int, limit int, nivel string, alvo int) ([]exercises.Exercicio, error) {
+	query := `
+		SELECT e.id, e.usuario_id, e.catalogo_id, e.dados_exercicio, e.nivel, e.nivel_cefr, e.criado_em
+		FROM exercicios e
+		JOIN usuarios u ON u.id = $2
+		LEFT JOIN exercicios_visualizados ev ON ev.exercicio_id = e.id AND ev.usuario_id = $2
+		WHERE e.catalogo_id = $1
+		  AND e.idioma_id_origem = u.idioma_origem_id
+		  AND e.idioma_id = u.idioma_aprendizado_id
+		  AND (e.usuario_id = $2 OR e.usuario_id IS NULL)
+		  AND ev.exercicio_id IS NULL
+		  AND ($4 = '' OR e.nivel_cefr = $4)
+		ORDER BY ABS(COALESCE(array_position(ARRAY['A1','A2','B1','B2','C1','C2'], e.nivel_cefr), $5) - $5), RANDOM()
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, catalogoID, userID, limit, nivel, alvo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []exercises.Exercicio
+	for rows.Next() {
+		var ex exercises.Exercicio
+		var dadosJSON []byte
+
+		if err := rows.Scan(
+			&ex.ID, &ex.UsuarioID, &ex.CatalogoID,
+			&dadosJSON, &ex.Nivel, &ex.NivelCEFR, &ex.CriadoEm,
+		); err != nil {
+			return nil, err
+		}
+
+		if dadosJSON != nil {
+			json.Unmarshal(dadosJSON, &ex.DadosExercicio)
+		}
+
+		list = append(list, ex)
+	}
+	if len(list) == 0 {
+		return nil, errors.New("no exercises found")
+	}
+	return list, rows.Err()
+}
+
 // MarkExerciseAsViewed insere o exercício na tabela de já visualizados para o usuário
 func (r *Repository) MarkExerciseAsViewed(ctx context.Context, userID int, exercicioID int) error {
 	query := `
@@ -280,3 +329,144 @@ func (r *Repository) ListHistorias(ctx context.Context, userID int, limit int) (
 	}
 	return list, rows.Err()
 }
+
+// GetCatalogoByID busca um item do catálogo pelo ID
+func (r *Repository) GetCatalogoByID(ctx context.Context, id int) (*exercises.CatalogoItem, error) {
+	query := `
+		SELECT c.id, c.nome, COALESCE(c.descricao, ''), c.tipo_id, t.nome, c.ativo, c.img
+		FROM exercicios_catalogo c
+		JOIN tipos_exercicio t ON t.id = c.tipo_id
+		WHERE c.id = $1
+	`
+
+	var item exercises.CatalogoItem
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&item.ID, &item.Nome, &item.Descricao, &item.TipoID, &item.TipoNome, &item.Ativo, &item.Img,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetCatalogoByNome busca um item do catálogo pelo nome (ex: "historia")
+func (r *Repository) GetCatalogoByNome(ctx context.Context, nome string) (*exercises.CatalogoItem, error) {
+	query := `
+		SELECT c.id, c.nome, COALESCE(c.descricao, ''), c.tipo_id, t.nome, c.ativo, c.img
+		FROM exercicios_catalogo c
+		JOIN tipos_exercicio t ON t.id = c.tipo_id
+		WHERE c.nome = $1
+	`
+
+	var item exercises.CatalogoItem
+	err := r.db.QueryRow(ctx, query, nome).Scan(
+		&item.ID, &item.Nome, &item.Descricao, &item.TipoID, &item.TipoNome, &item.Ativo, &item.Img,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create insere um exercício pertencente a um usuário
+func (r *Repository) Create(ctx context.Context, input exercises.NovoExercicio) (*exercises.Exercicio, error) {
+	dadosJSON, err := json.Marshal(input.DadosExercicio)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO exercicios (usuario_id, catalogo_id, dados_exercicio, nivel, idioma_id, idioma_id_origem, nivel_cefr)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING id, criado_em
+	`
+
+	usuarioID := input.UsuarioID
+	ex := exercises.Exercicio{
+		UsuarioID:      &usuarioID,
+		CatalogoID:     input.CatalogoID,
+		DadosExercicio: input.DadosExercicio,
+		Nivel:          input.Nivel,
+	}
+	if input.NivelCEFR != "" {
+		nivel := input.NivelCEFR
+		ex.NivelCEFR = &nivel
+	}
+
+	err = r.db.QueryRow(ctx, query,
+		input.UsuarioID, input.CatalogoID, dadosJSON, input.Nivel, input.IdiomaID, input.IdiomaIDOrigem,
+		input.NivelCEFR,
+	).Scan(&ex.ID, &ex.CriadoEm)
+	if err != nil {
+		return nil, err
+	}
+	return &ex, nil
+}
+
+// GetPerfilUsuario retorna idiomas (IDs e códigos) e nível de proficiência do usuário
+func (r *Repository) GetPerfilUsuario(ctx context.Context, userID int) (*exercises.PerfilUsuario, error) {
+	query := `
+		SELECT u.id,
+		       COALESCE(u.idioma_origem_id, 0), COALESCE(io.codigo, ''),
+		       COALESCE(u.idioma_aprendizado_id, 0), COALESCE(ia.codigo, ''),
+		       COALESCE(p.nivel_proficiencia, 'intermediate')
+		FROM usuarios u
+		LEFT JOIN idiomas io ON io.id = u.idioma_origem_id
+		LEFT JOIN idiomas ia ON ia.id = u.idioma_aprendizado_id
+		LEFT JOIN preferencias_usuario p ON p.usuario_id = u.id
+		WHERE u.id = $1
+	`
+
+	var p exercises.PerfilUsuario
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&p.UsuarioID,
+		&p.IdiomaOrigemID, &p.IdiomaOrigem,
+		&p.IdiomaAprendizadoID, &p.IdiomaAprendizado,
+		&p.NivelProficiencia,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ── Pontuação ──────────────────────────────────────────────────
+
+// GetRecompensa retorna proef_base/proef_bonus e o tipo de um item do catálogo
+func (r *Repository) GetRecompensa(ctx context.Context, catalogoID int) (*exercises.Recompensa, error) {
+	query := `
+		SELECT id, tipo_id, COALESCE(proef_base, 0), COALESCE(proef_bonus, 0)
+		FROM exercicios_catalogo
+		WHERE id = $1
+	`
+
+	var rec exercises.Recompensa
+	err := r.db.QueryRow(ctx, query, catalogoID).Scan(&rec.CatalogoID, &rec.TipoID, &rec.ProefBase, &rec.ProefBonus)
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// AwardProficiency soma pontos em usuario_estatisticas e usuario_estatisticas_tipo,
+// criando as linhas na primeira vez. Um único comando: as duas tabelas mudam juntas
+// e requests simultâneas não duplicam linhas (índices únicos da migration 008).
+func (r *Repository) AwardProficiency(ctx context.Context, userID int, tipoID int, pontos int) error {
+	_, err := r.db.Exec(ctx, `
+		WITH geral AS (
+			INSERT INTO usuario_estatisticas (usuario_id, total_exercicios, total_prof, ultima_atividade)
+			VALUES ($1, 1, $3, CURRENT_TIMESTAMP)
+			ON CONFLICT (usuario_id) DO UPDATE SET
+				total_prof = COALESCE(usuario_estatisticas.total_prof, 0) + EXCLUDED.total_prof,
+				total_exercicios = COALESCE(usuario_estatisticas.total_exercicios, 0) + 1,
+				ultima_atividade = CURRENT_TIMESTAMP,
+				atualizado_em = CURRENT_TIMESTAMP
+		)
+		INSERT INTO usuario_estatisticas_tipo (usuario_id, tipo_exercicio_id, total_respostas, proef_ganho)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (usuario_id, tipo_exercicio_id) DO UPDATE SET
+			proef_ganho = COALESCE(usuario_estatisticas_tipo.proef_ganho, 0) + EXCLUDED.proef_ganho,
+			total_respostas = COALESCE(usuario_estatisticas_tipo.total_respostas, 0) + 1
+	`, userID, tipoID, pontos)
+	return err
+}
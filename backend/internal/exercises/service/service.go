@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"extension-backend/internal/cefr"
 	"extension-backend/internal/exercises"
 )
 
@@ -59,13 +60,31 @@ func (s *Service) ListTiposComCatalogo(ctx context.Context) ([]exercises.TipoCom
 	return result, nil
 }
 
-// GetExerciciosByCatalogo retorna até `limit` exercícios de um catálogo
+// GetExerciciosByCatalogo retorna até `limit` exercícios de um catálogo,
+// priorizando os mais próximos do nível do usuário
 func (s *Service) GetExerciciosByCatalogo(ctx context.Context, catalogoID int, userID int, limit int) ([]exercises.Exercicio, error) {
+	return s.GetExerciciosByNivel(ctx, catalogoID, userID, limit, "")
+}
+
+// GetExerciciosByNivel retorna até `limit` exercícios de um catálogo no nível CEFR pedido.
+// Sem nível, o alvo vem da proficiência do usuário (preferencias_usuario).
+func (s *Service) GetExerciciosByNivel(ctx context.Context, catalogoID int, userID int, limit int, nivel string) ([]exercises.Exercicio, error) {
 	if limit <= 0 || limit > 10 {
 		limit = 3
 	}
 
-	exs, err := s.repo.GetByCatalogoAndUserLanguages(ctx, catalogoID, userID, limit)
+	alvo := cefr.B1
+	if nivel != "" {
+		lv, ok := cefr.ParseLevel(nivel)
+		if !ok {
+			return nil, fmt.Errorf("invalid cefr level %q", nivel)
+		}
+		nivel, alvo = string(lv), lv
+	} else if perfil, err := s.repo.GetPerfilUsuario(ctx, userID); err == nil {
+		alvo = cefr.ForProficiency(perfil.NivelProficiencia)
+	}
+
+	exs, err := s.repo.GetByCatalogoAdaptive(ctx, catalogoID, userID, limit, nivel, alvo.Rank())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exercises for catalogo %d and user %d: %w", catalogoID, userID, err)
 	}
@@ -75,7 +94,8 @@ func (s *Service) GetExerciciosByCatalogo(ctx context.Context, catalogoID int, u
 	return exs, nil
 }
 
-// MarkExerciseAsViewed registra que um usuário visualizou um exercício
+// MarkExerciseAsViewed registra que um usuário visualizou um exercício.
+// Visualizar não é concluir: não publica ExerciseCompleted nem conta na ofensiva.
 func (s *Service) MarkExerciseAsViewed(ctx context.Context, userID int, exercicioID int) error {
 	if err := s.repo.MarkExerciseAsViewed(ctx, userID, exercicioID); err != nil {
 		return fmt.Errorf("failed to mark exercise %d as viewed for user %d: %w", exercicioID, userID, err)
@@ -0,0 +1,298 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/exercises"
+	"extension-backend/internal/exercises/chain"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// memStore implementa chain.Store em memória (TTL ignorado)
+type memStore struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[string]string)}
+}
+
+func (m *memStore) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return v, nil
+}
+
+func (m *memStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	return nil
+}
+
+func (m *memStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.data[key]; ok {
+		return false, nil
+	}
+	m.data[key] = value
+	return true, nil
+}
+
+func (m *memStore) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.data, k)
+	}
+	return nil
+}
+
+func (m *memStore) DeleteIfEquals(ctx context.Context, key, value string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.data[key]; !ok || v != value {
+		return false, nil
+	}
+	delete(m.data, key)
+	return true, nil
+}
+
+func chainLLM(judgeScore string) *ai.FakeProvider {
+	return ai.NewFakeProvider(func(prompt string) (string, error) {
+		if strings.Contains(prompt, "grammar judge") {
+			return `{"score": ` + judgeScore + `, "gramatical": true, "frase_corrigida": "Yesterday I went home.", "feedback": "Nice"}`, nil
+		}
+		return `{"nextword": "went"}`, nil
+	})
+}
+
+func expectChainStart(mock pgxmock.PgxPoolIface) {
+	mock.ExpectQuery("SELECT (.+) FROM exercicios WHERE id").
+		WithArgs(42).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "usuario_id", "catalogo_id", "dados_exercicio", "nivel", "criado_em"},
+		).AddRow(42, nil, 8, []byte(`{"palavra_inicial": "Yesterday"}`), 1, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM exercicios_catalogo c JOIN tipos_exercicio t (.+) WHERE c.id").
+		WithArgs(8).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "nome", "descricao", "tipo_id", "tipo_nome", "ativo", "img"},
+		).AddRow(8, "Sentence Chain", "", 3, "Escrita", true, nil))
+}
+
+func TestChainSession_FullGame(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	llm := chainLLM("95")
+	svc := chain.New(newMemStore(), repo, ai.NewServiceWithProvider(llm), llm)
+	ctx := context.Background()
+
+	expectChainStart(mock)
+	session, err := svc.Start(ctx, 7, chain.StartInput{ExercicioID: 42, MaxPalavras: 1})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if session.MaxPalavras != chain.MinMaxPalavras {
+		t.Errorf("expected max_palavras clamped to %d, got %d", chain.MinMaxPalavras, session.MaxPalavras)
+	}
+
+	if _, err := svc.Get(ctx, 8, session.ID); !errors.Is(err, chain.ErrSessionNotFound) {
+		t.Errorf("other users must not see the session, got %v", err)
+	}
+
+	if _, err := svc.Turn(ctx, 7, session.ID, chain.TurnInput{Palavra: "I went"}); !errors.Is(err, chain.ErrInvalidWord) {
+		t.Errorf("expected ErrInvalidWord for two words, got %v", err)
+	}
+
+	var result *chain.TurnResult
+	for _, w := range []string{"I", "home", "today."} {
+		result, err = svc.Turn(ctx, 7, session.ID, chain.TurnInput{Palavra: w})
+		if err != nil {
+			t.Fatalf("turn %q: %v", w, err)
+		}
+	}
+	if !result.Completa || len(result.Session.Turnos) != 6 {
+		t.Fatalf("expected a full session with 6 turns, got %d", len(result.Session.Turnos))
+	}
+	if result.Frase != "Yesterday I went home went today. went" {
+		t.Errorf("unexpected sentence: %q", result.Frase)
+	}
+	if _, err := svc.Turn(ctx, 7, session.ID, chain.TurnInput{Palavra: "more"}); !errors.Is(err, chain.ErrSessionFull) {
+		t.Errorf("expected ErrSessionFull, got %v", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM exercicios_catalogo WHERE id").
+		WithArgs(8).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "tipo_id", "proef_base", "proef_bonus"}).AddRow(8, 3, 20, 5))
+	mock.ExpectExec("INSERT INTO usuario_estatisticas (.+) ON CONFLICT \\(usuario_id\\) (.+) INSERT INTO usuario_estatisticas_tipo (.+) ON CONFLICT").
+		WithArgs(7, 3, 24).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO exercicios_visualizados").
+		WithArgs(7, 42).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	finished, err := svc.Finish(ctx, 7, session.ID)
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	// round(20 * 95 / 100) + bonus 5
+	if finished.Status != chain.StatusFinalizada || finished.Resultado.Pontos != 24 {
+		t.Errorf("unexpected result: %+v", finished.Resultado)
+	}
+
+	// Segundo finish devolve o mesmo resultado sem pontuar de novo
+	again, err := svc.Finish(ctx, 7, session.ID)
+	if err != nil || again.Resultado.Pontos != 24 {
+		t.Errorf("expected idempotent finish, got %+v, %v", again, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestChainSession_FinishWithoutWordsAwardsNothing(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	llm := chainLLM("100")
+	svc := chain.New(newMemStore(), repo, ai.NewServiceWithProvider(llm), llm)
+	ctx := context.Background()
+
+	expectChainStart(mock)
+	session, err := svc.Start(ctx, 7, chain.StartInput{ExercicioID: 42})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	mock.ExpectExec("INSERT INTO exercicios_visualizados").
+		WithArgs(7, 42).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	finished, err := svc.Finish(ctx, 7, session.ID)
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if finished.Resultado.Pontos != 0 || len(llm.Prompts()) != 0 {
+		t.Errorf("expected no judge call and no points, got %+v", finished.Resultado)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestChainSession_FinishWaitsForTurnInProgress(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	thinking := make(chan struct{})
+	release := make(chan struct{})
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) {
+		close(thinking)
+		<-release
+		return `{"nextword": "went"}`, nil
+	})
+	svc := chain.New(newMemStore(), repo, ai.NewServiceWithProvider(llm), llm)
+	ctx := context.Background()
+
+	expectChainStart(mock)
+	session, err := svc.Start(ctx, 7, chain.StartInput{ExercicioID: 42})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.Turn(ctx, 7, session.ID, chain.TurnInput{Palavra: "I"})
+		done <- err
+	}()
+	<-thinking
+
+	// Finish durante o turno não pode pontuar uma sessão que o turno vai regravar
+	if _, err := svc.Finish(ctx, 7, session.ID); !errors.Is(err, chain.ErrSessionBusy) {
+		t.Errorf("expected ErrSessionBusy while a turn is in progress, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("turn: %v", err)
+	}
+
+	got, err := svc.Get(ctx, 7, session.ID)
+	if err != nil || got.Status != chain.StatusAtiva || len(got.Turnos) != 2 {
+		t.Errorf("expected active session with both words, got %+v, %v", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestChainSession_ExpiredLockIsNotReleasedByOldOwner(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	store := newMemStore()
+	thinking := make(chan struct{})
+	release := make(chan struct{})
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) {
+		close(thinking)
+		<-release
+		return `{"nextword": "went"}`, nil
+	})
+	svc := chain.New(store, repo, ai.NewServiceWithProvider(llm), llm)
+	ctx := context.Background()
+
+	expectChainStart(mock)
+	session, err := svc.Start(ctx, 7, chain.StartInput{ExercicioID: 42})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.Turn(ctx, 7, session.ID, chain.TurnInput{Palavra: "I"})
+		done <- err
+	}()
+	<-thinking
+
+	// O LLM passou do lockTTL: a trava expirou e outra request a pegou
+	lockKey := "chain:session:" + session.ID + ":lock"
+	store.Delete(ctx, lockKey)
+	if ok, _ := store.SetNX(ctx, lockKey, "other-owner", time.Minute); !ok {
+		t.Fatal("expected the expired lock to be free")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("turn: %v", err)
+	}
+	if v, err := store.Get(ctx, lockKey); err != nil || v != "other-owner" {
+		t.Errorf("expected the new owner's lock to survive the old unlock, got %q, %v", v, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestChainPontos(t *testing.T) {
+	rec := &exercises.Recompensa{ProefBase: 10, ProefBonus: 3}
+	cases := map[int]int{0: 0, 50: 5, 89: 9, 90: 12, 100: 13}
+	for score, want := range cases {
+		if got := chain.Pontos(rec, score); got != want {
+			t.Errorf("Pontos(%d) = %d, want %d", score, got, want)
+		}
+	}
+}
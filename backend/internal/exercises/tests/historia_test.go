@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"extension-backend/internal/ai"
+	"extension-backend/internal/anki"
+	"extension-backend/internal/exercises/generator"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type fakeCards struct {
+	cards []anki.AnkiCard
+	limit int
+}
+
+func (f *fakeCards) GetStoryCards(ctx context.Context, userID int, limit int) ([]anki.AnkiCard, error) {
+	f.limit = limit
+	return f.cards, nil
+}
+
+const historiaResponse = `{
+	"titulo": "Café",
+	"texto": "At the café, Ana tried to [[break the ice]]. Later she said: \"Let's [[Break the ice]] again!\" It was a [[piece of cake]].",
+	"palavras_alvo": [
+		{"anki_id": 100, "termo": "break the ice"},
+		{"anki_id": 999, "termo": "piece of cake"}
+	],
+	"perguntas": [
+		{"pergunta": "Where was Ana?", "opcoes": ["café", "school"], "resposta_correta": "café"},
+		{"pergunta": "Broken?", "opcoes": ["yes", "no"], "resposta_correta": "maybe"}
+	]
+}`
+
+func TestGenerateHistoria_MarksTargetTerms(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM exercicios_catalogo c JOIN tipos_exercicio t (.+) WHERE c.nome").
+		WithArgs("historia").
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "nome", "descricao", "tipo_id", "tipo_nome", "ativo", "img"},
+		).AddRow(9, "historia", "", 1, "Leitura", true, nil))
+	mock.ExpectQuery("SELECT (.+) FROM usuarios u").
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "idioma_origem_id", "idioma_origem", "idioma_aprendizado_id", "idioma_aprendizado", "nivel_proficiencia"},
+		).AddRow(7, 1, "pt", 2, "en", "beginner"))
+
+	mock.ExpectQuery("INSERT INTO exercicios").
+		WithArgs(7, 9, pgxmock.AnyArg(), 1, 2, 1, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "criado_em"}).AddRow(55, time.Now()))
+
+	cards := &fakeCards{cards: []anki.AnkiCard{
+		{ID: 100, FraseID: 200, Conteudo: "We need to break the ice", TraducaoCompleta: "Precisamos quebrar o gelo"},
+	}}
+	llm := ai.NewFakeProvider(func(prompt string) (string, error) { return historiaResponse, nil })
+	gen := generator.NewHistoria(repo, cards, llm)
+
+	ex, err := gen.Generate(context.Background(), 7, generator.HistoriaInput{Palavras: 50})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cards.limit != generator.MaxPalavrasHistoria {
+		t.Errorf("expected limit capped to %d, got %d", generator.MaxPalavrasHistoria, cards.limit)
+	}
+
+	saved, _ := json.Marshal(ex.DadosExercicio)
+	var dados struct {
+		Texto        string                    `json:"texto"`
+		PalavrasAlvo []generator.PalavraAlvo   `json:"palavras_alvo"`
+		Perguntas    map[string]map[string]any `json:"perguntas_do_texto"`
+	}
+	if err := json.Unmarshal(saved, &dados); err != nil {
+		t.Fatalf("failed to decode dados: %v", err)
+	}
+
+	want := `At the café, Ana tried to break the ice. Later she said: "Let's Break the ice again!" It was a piece of cake.`
+	if dados.Texto != want {
+		t.Fatalf("markers should be stripped, got %q", dados.Texto)
+	}
+	if len(dados.PalavrasAlvo) != 1 || dados.PalavrasAlvo[0].FraseID != 200 {
+		t.Fatalf("expected only the card term as target, got %+v", dados.PalavrasAlvo)
+	}
+
+	units := utf16.Encode([]rune(dados.Texto))
+	for _, o := range dados.PalavrasAlvo[0].Ocorrencias {
+		if got := string(utf16.Decode(units[o.Inicio:o.Fim])); got != "break the ice" && got != "Break the ice" {
+			t.Errorf("offset %+v points to %q", o, got)
+		}
+	}
+	if len(dados.PalavrasAlvo[0].Ocorrencias) != 2 {
+		t.Errorf("expected 2 occurrences, got %d", len(dados.PalavrasAlvo[0].Ocorrencias))
+	}
+	if len(dados.Perguntas) != 1 {
+		t.Errorf("expected invalid question to be dropped, got %d", len(dados.Perguntas))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestGenerateHistoria_NoCards(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM exercicios_catalogo c").
+		WithArgs("historia").
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "nome", "descricao", "tipo_id", "tipo_nome", "ativo", "img"},
+		).AddRow(9, "historia", "", 1, "Leitura", true, nil))
+	mock.ExpectQuery("SELECT (.+) FROM usuarios u").
+		WithArgs(7).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "idioma_origem_id", "idioma_origem", "idioma_aprendizado_id", "idioma_aprendizado", "nivel_proficiencia"},
+		).AddRow(7, 1, "pt", 2, "en", "beginner"))
+
+	llm := ai.NewFakeProvider(nil)
+	gen := generator.NewHistoria(repo, &fakeCards{}, llm)
+
+	if _, err := gen.Generate(context.Background(), 7, generator.HistoriaInput{}); err == nil {
+		t.Fatal("expected error when user has no cards")
+	}
+	if len(llm.Prompts()) != 0 {
+		t.Error("LLM should not be called without cards")
+	}
+}
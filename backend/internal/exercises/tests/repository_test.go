@@ -54,8 +54,8 @@ func TestListCatalogo_Success(t *testing.T) {
 
 	mock.ExpectQuery("SELECT (.+) FROM exercicios_catalogo c JOIN tipos_exercicio t").
 		WillReturnRows(pgxmock.NewRows(
-			[]string{"id", "nome", "descricao", "tipo_id", "tipo_nome", "ativo"},
-		).AddRow(1, "Cat 1", "Desc 1", 2, "Tradução", true))
+			[]string{"id", "nome", "descricao", "tipo_id", "tipo_nome", "ativo", "img"},
+		).AddRow(1, "Cat 1", "Desc 1", 2, "Tradução", true, nil))
 
 	catalogo, err := repo.ListCatalogo(context.Background())
 	if err != nil {
@@ -178,6 +178,32 @@ func TestGetByCatalogoAndUserLanguages_Success(t *testing.T) {
 	}
 }
 
+func TestGetByCatalogoAdaptive_FiltersByLevel(t *testing.T) {
+	mock, repo := setupMock(t)
+	defer mock.Close()
+
+	now := time.Now()
+	userID := 1
+	nivel := "B2"
+
+	mock.ExpectQuery("SELECT (.+) FROM exercicios e JOIN usuarios u (.+) ORDER BY ABS\\(COALESCE\\(array_position").
+		WithArgs(10, userID, 3, "B2", 4).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "usuario_id", "catalogo_id", "dados_exercicio", "nivel", "nivel_cefr", "criado_em"},
+		).AddRow(42, &userID, 10, []byte(`{"text":"filtered"}`), 3, &nivel, now))
+
+	exs, err := repo.GetByCatalogoAdaptive(context.Background(), 10, userID, 3, "B2", 4)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(exs) != 1 || exs[0].NivelCEFR == nil || *exs[0].NivelCEFR != "B2" {
+		t.Errorf("expected one B2 exercise, got %+v", exs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
 func TestMarkExerciseAsViewed_Success(t *testing.T) {
 	mock, repo := setupMock(t)
 	defer mock.Close()
@@ -3,17 +3,27 @@ package group
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
+
+	"extension-backend/internal/events"
 )
 
 type Service struct {
-	repo *Repository
+	repo   *Repository
+	events events.Publisher
 }
 
 func NewService(repo *Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// WithEvents publica GroupChanged a cada alteração em um grupo ou nas suas frases
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
+
 func (s *Service) Create(ctx context.Context, input CreateInput) (*Group, error) {
 	g := &Group{
 		UsuarioID:   input.UsuarioID,
@@ -25,6 +35,7 @@ func (s *Service) Create(ctx context.Context, input CreateInput) (*Group, error)
 	if err := s.repo.Create(ctx, g); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, g, 0, events.GroupCreated)
 	return g, nil
 }
 
@@ -68,6 +79,7 @@ func (s *Service) Update(ctx context.Context, id string, input UpdateInput) (*Gr
 	if err := s.repo.Update(ctx, g); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, g, 0, events.GroupUpdated)
 	return g, nil
 }
 
@@ -76,17 +88,62 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return fmt.Errorf("invalid id")
 	}
-	return s.repo.Delete(ctx, intID)
+	g := s.owner(ctx, intID)
+	if err := s.repo.Delete(ctx, intID); err != nil {
+		return err
+	}
+	s.publish(ctx, g, 0, events.GroupDeleted)
+	return nil
 }
 
 func (s *Service) AddPhrase(ctx context.Context, phraseID, groupID int) error {
-	return s.repo.AddPhraseToGroup(ctx, phraseID, groupID)
+	if err := s.repo.AddPhraseToGroup(ctx, phraseID, groupID); err != nil {
+		return err
+	}
+	s.publish(ctx, s.owner(ctx, groupID), phraseID, events.GroupPhraseAdded)
+	return nil
 }
 
 func (s *Service) RemovePhrase(ctx context.Context, phraseID, groupID int) error {
-	return s.repo.RemovePhraseFromGroup(ctx, phraseID, groupID)
+	if err := s.repo.RemovePhraseFromGroup(ctx, phraseID, groupID); err != nil {
+		return err
+	}
+	s.publish(ctx, s.owner(ctx, groupID), phraseID, events.GroupPhraseRemoved)
+	return nil
 }
 
 func (s *Service) GetPhraseGroups(ctx context.Context, phraseID int) ([]Group, error) {
 	return s.repo.GetPhraseGroups(ctx, phraseID)
 }
+
+// owner busca o grupo para saber a quem avisar; sem bus não há consulta extra
+func (s *Service) owner(ctx context.Context, groupID int) *Group {
+	if s.events == nil {
+		return nil
+	}
+	g, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		log.Printf("[Group] Failed to load group %d for event: %v", groupID, err)
+		return nil
+	}
+	return g
+}
+
+// publish avisa os assinantes; falha no bus não desfaz a alteração
+func (s *Service) publish(ctx context.Context, g *Group, phraseID int, action string) {
+	if s.events == nil || g == nil {
+		return
+	}
+	e, err := events.New(events.GroupChanged, g.UsuarioID, events.GroupChangedPayload{
+		GrupoID:   g.ID,
+		NomeGrupo: g.NomeGrupo,
+		FraseID:   phraseID,
+		Action:    action,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Group] Failed to publish %s for group %d: %v", events.GroupChanged, g.ID, err)
+	}
+}
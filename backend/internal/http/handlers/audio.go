@@ -4,8 +4,11 @@ import (
 	"log"
 	"net/http"
 
+	"extension-backend/internal/ai"
+	"extension-backend/internal/audio"
 	"extension-backend/internal/audio/processor"
 	"extension-backend/internal/audio/service"
+	"extension-backend/internal/http/middleware"
 
 	"github.com/gorilla/websocket"
 )
@@ -14,8 +17,15 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true }, // Accept connections from browser ext/web
 }
 
-// ConversationWS Handles real-time Audio STT->LLM->TTS connection
+// ConversationWS Handles real-time Audio STT->LLM->TTS connection.
+// The route sits behind middleware.AuthOrTicket: unauthenticated upgrades never get here.
 func (h *Handler) ConversationWS(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("[Audio WS] Falha no upgrade: %v", err)
@@ -46,10 +56,29 @@ func (h *Handler) ConversationWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Tie them into the pipeline orchestrator
-	historyManager := processor.NewHistoryManager(h.cacheClient)
+	// Sem Redis a conversa segue sem histórico
+	var historyManager audio.ConversationHistory
+	if h.cacheClient != nil {
+		historyManager = processor.NewHistoryManager(h.cacheClient)
+	}
 	audioPipeline := processor.NewPipeline(sttFactory, tts, llm, historyManager)
+	if h.events != nil {
+		audioPipeline.WithEvents(h.events)
+	}
+	audioPipeline.WithMetrics(h.convMetrics)
+	if h.aiService != nil {
+		audioPipeline.WithGrammar(service.NewGrammarChecker(h.aiService.Provider()))
+	}
+	if h.convService != nil {
+		audioPipeline.WithScenarios(h.convService)
+		audioPipeline.WithRecorder(h.convService, audio.Providers{
+			STT: "elevenlabs/" + service.ElevenLabsSTTModel,
+			LLM: "gemini/" + ai.ModelName,
+			TTS: "elevenlabs/" + service.ElevenLabsTTSModel,
+		})
+	}
 
-	log.Println("[Audio WS] Nova conexão pipeline iniciada")
+	log.Printf("[Audio WS] Nova conexão pipeline iniciada (user: %d)", claims.UserID)
 	// Block executing the loop handler until ctx is canceled/conn drops
-	audioPipeline.HandleWSConnection(r.Context(), conn)
+	audioPipeline.HandleWSConnection(r.Context(), conn, claims.UserID)
 }
@@ -2,9 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"extension-backend/internal/ai"
+	"extension-backend/internal/exercises/chain"
+	"extension-backend/internal/http/middleware"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // TODO: CRIAR LIMITE DE CHAMADAS POR USUARIO DE 10
@@ -36,3 +41,120 @@ func (h *Handler) ChainNextWord(w http.ResponseWriter, r *http.Request) {
 
 	SendSuccess(w, http.StatusOK, "Next word generated", resp)
 }
+
+// StartChainSession abre uma sessão de SentenceChain guardada no servidor
+func (h *Handler) StartChainSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.chainService == nil {
+		SendError(w, http.StatusServiceUnavailable, "chain sessions not available")
+		return
+	}
+
+	var input chain.StartInput
+	if err := DecodeJSON(r, &input); err != nil || input.ExercicioID <= 0 {
+		SendError(w, http.StatusBadRequest, "exercicio_id is required")
+		return
+	}
+
+	session, err := h.chainService.Start(ctx, claims.UserID, input)
+	if err != nil {
+		sendChainError(w, err)
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, "Chain session started", session)
+}
+
+// GetChainSession retorna o estado de uma sessão
+func (h *Handler) GetChainSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.chainService == nil {
+		SendError(w, http.StatusServiceUnavailable, "chain sessions not available")
+		return
+	}
+
+	session, err := h.chainService.Get(ctx, claims.UserID, chi.URLParam(r, "sessionId"))
+	if err != nil {
+		sendChainError(w, err)
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Chain session retrieved", session)
+}
+
+// ChainTurn registra a palavra do usuário e devolve a palavra da IA
+func (h *Handler) ChainTurn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.chainService == nil {
+		SendError(w, http.StatusServiceUnavailable, "chain sessions not available")
+		return
+	}
+
+	var input chain.TurnInput
+	if err := DecodeJSON(r, &input); err != nil {
+		SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.chainService.Turn(ctx, claims.UserID, chi.URLParam(r, "sessionId"), input)
+	if err != nil {
+		sendChainError(w, err)
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Turn registered", result)
+}
+
+// FinishChainSession avalia a frase e credita os pontos de proficiência
+func (h *Handler) FinishChainSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.chainService == nil {
+		SendError(w, http.StatusServiceUnavailable, "chain sessions not available")
+		return
+	}
+
+	session, err := h.chainService.Finish(ctx, claims.UserID, chi.URLParam(r, "sessionId"))
+	if err != nil {
+		sendChainError(w, err)
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Chain session finished", session)
+}
+
+func sendChainError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, chain.ErrSessionNotFound):
+		SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, chain.ErrSessionFinished), errors.Is(err, chain.ErrSessionFull), errors.Is(err, chain.ErrSessionBusy):
+		SendError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, chain.ErrInvalidWord), errors.Is(err, chain.ErrNotChainCatalog):
+		SendError(w, http.StatusBadRequest, err.Error())
+	default:
+		SendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
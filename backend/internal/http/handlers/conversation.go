@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"extension-backend/internal/cefr"
+	"extension-backend/internal/conversation"
+	"extension-backend/internal/http/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListConversations retorna as conversas de voz do usuário, mais recentes primeiro
+// Query params: limit, offset
+func (h *Handler) ListConversations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.convService == nil {
+		SendError(w, http.StatusServiceUnavailable, "conversation history not available")
+		return
+	}
+
+	var params conversation.ListParams
+	q := r.URL.Query()
+	if l, err := strconv.Atoi(q.Get("limit")); err == nil {
+		params.Limit = l
+	}
+	if o, err := strconv.Atoi(q.Get("offset")); err == nil {
+		params.Offset = o
+	}
+
+	conversas, err := h.convService.List(ctx, claims.UserID, params)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Conversations retrieved", conversas)
+}
+
+// ListScenarios retorna o catálogo de cenários de role-play para o setup do WebSocket
+// Query params: idioma (código, ex: en), cefr
+func (h *Handler) ListScenarios(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.convService == nil {
+		SendError(w, http.StatusServiceUnavailable, "conversation history not available")
+		return
+	}
+
+	q := r.URL.Query()
+	params := conversation.CenarioParams{Idioma: q.Get("idioma")}
+	if c := q.Get("cefr"); c != "" {
+		nivel, ok := cefr.ParseLevel(c)
+		if !ok {
+			SendError(w, http.StatusBadRequest, "invalid cefr level")
+			return
+		}
+		params.Nivel = string(nivel)
+	}
+
+	cenarios, err := h.convService.ListScenarios(ctx, params)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Scenarios retrieved", cenarios)
+}
+
+// GetConversation retorna a conversa com todos os turnos
+func (h *Handler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.convService == nil {
+		SendError(w, http.StatusServiceUnavailable, "conversation history not available")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		SendError(w, http.StatusBadRequest, "invalid conversation id")
+		return
+	}
+
+	detalhe, err := h.convService.Get(ctx, claims.UserID, id)
+	if err != nil {
+		if errors.Is(err, conversation.ErrNotFound) {
+			SendError(w, http.StatusNotFound, "conversation not found")
+			return
+		}
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Conversation retrieved", detalhe)
+}
+
+// SaveConversationPhrase salva um trecho de um turno da conversa como frase
+func (h *Handler) SaveConversationPhrase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.convService == nil {
+		SendError(w, http.StatusServiceUnavailable, "conversation history not available")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		SendError(w, http.StatusBadRequest, "invalid conversation id")
+		return
+	}
+
+	var input conversation.SavePhraseInput
+	if err := DecodeJSON(r, &input); err != nil || input.TurnoID == 0 {
+		SendError(w, http.StatusBadRequest, "turno_id is required")
+		return
+	}
+
+	created, err := h.convService.SavePhrase(ctx, claims.UserID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, conversation.ErrNotFound):
+			SendError(w, http.StatusNotFound, "conversation not found")
+		case errors.Is(err, conversation.ErrInvalidSentence):
+			SendError(w, http.StatusBadRequest, err.Error())
+		default:
+			SendError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, "Phrase created", created)
+}
+
+// SaveCorrectionPhrase salva a forma correta de uma correção gramatical como frase
+func (h *Handler) SaveCorrectionPhrase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if h.convService == nil {
+		SendError(w, http.StatusServiceUnavailable, "conversation history not available")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		SendError(w, http.StatusBadRequest, "invalid correction id")
+		return
+	}
+
+	// Corpo opcional: só o idioma da frase
+	var input conversation.SaveCorrectionInput
+	if r.ContentLength > 0 {
+		if err := DecodeJSON(r, &input); err != nil {
+			SendError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	created, err := h.convService.SaveCorrection(ctx, claims.UserID, id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, conversation.ErrNotFound):
+			SendError(w, http.StatusNotFound, "correction not found")
+		case errors.Is(err, conversation.ErrCorrectionSaved):
+			SendError(w, http.StatusConflict, err.Error())
+		default:
+			SendError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, "Phrase created", created)
+}
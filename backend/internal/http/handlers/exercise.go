@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"extension-backend/internal/cefr"
+	"extension-backend/internal/exercises/generator"
 	"extension-backend/internal/http/middleware"
 	"fmt"
 	"net/http"
@@ -69,7 +71,16 @@ func (h *Handler) GetExercisesByCatalogo(w http.ResponseWriter, r *http.Request)
 	}
 	userID := claims.UserID
 
-	exs, err := h.exerciseService.GetExerciciosByCatalogo(ctx, catalogoID, userID, limit)
+	// Filtro opcional por nível CEFR; sem ele a seleção se adapta ao nível do usuário
+	nivel := r.URL.Query().Get("cefr")
+	if nivel != "" {
+		if _, ok := cefr.ParseLevel(nivel); !ok {
+			SendError(w, http.StatusBadRequest, "invalid cefr level")
+			return
+		}
+	}
+
+	exs, err := h.exerciseService.GetExerciciosByNivel(ctx, catalogoID, userID, limit, nivel)
 	if err != nil{
 		fmt.Println(err.Error())
 		if err.Error() == "failed to get exercises for catalogo " + strconv.Itoa(catalogoID) + " and user " + strconv.Itoa(userID) + ": no exercises found"{
@@ -135,3 +146,69 @@ func (h *Handler) ListHistories(w http.ResponseWriter, r *http.Request) {
 
 	SendSuccess(w, http.StatusOK, "Histories retrieved", exs)
 }
+
+// GenerateExercises gera exercícios personalizados a partir das frases do usuário
+func (h *Handler) GenerateExercises(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if h.exerciseGen == nil {
+		SendError(w, http.StatusServiceUnavailable, "AI service not available")
+		return
+	}
+
+	var input generator.GenerateInput
+	if err := DecodeJSON(r, &input); err != nil {
+		SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if input.CatalogoID <= 0 {
+		SendError(w, http.StatusBadRequest, "catalogo_id is required")
+		return
+	}
+
+	result, err := h.exerciseGen.Generate(ctx, claims.UserID, input)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, "Exercises generated", result)
+}
+
+// GenerateHistory gera uma história com as frases vencidas ou erradas do Anki do usuário
+func (h *Handler) GenerateHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if h.historiaGen == nil {
+		SendError(w, http.StatusServiceUnavailable, "AI service not available")
+		return
+	}
+
+	var input generator.HistoriaInput
+	if r.ContentLength != 0 {
+		if err := DecodeJSON(r, &input); err != nil {
+			SendError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	ex, err := h.historiaGen.Generate(ctx, claims.UserID, input)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, "History generated", ex)
+}
@@ -5,11 +5,17 @@ import (
 
 	"extension-backend/internal/ai"
 	"extension-backend/internal/anki"
+	"extension-backend/internal/audio/processor"
 	"extension-backend/internal/cache"
+	"extension-backend/internal/conversation"
+	"extension-backend/internal/events"
 	"extension-backend/internal/exercises"
+	"extension-backend/internal/exercises/chain"
+	"extension-backend/internal/exercises/generator"
 	"extension-backend/internal/group"
 	"extension-backend/internal/phrase"
 	"extension-backend/internal/user"
+	"extension-backend/internal/vocabulary"
 )
 
 type Handler struct {
@@ -19,8 +25,15 @@ type Handler struct {
 	tokenService    *user.TokenService
 	ankiService     anki.ServiceInterface
 	exerciseService exercises.ServiceInterface
+	exerciseGen     *generator.Generator
+	historiaGen     *generator.HistoriaGenerator
+	chainService    *chain.Service
+	vocabService    vocabulary.ServiceInterface
 	aiService       *ai.Service
 	cacheClient     *cache.Client
+	events          events.Publisher
+	convMetrics     *processor.Metrics
+	convService     conversation.ServiceInterface
 }
 
 func NewHandler(
@@ -30,6 +43,10 @@ func NewHandler(
 	tokenService *user.TokenService,
 	ankiService anki.ServiceInterface,
 	exerciseService exercises.ServiceInterface,
+	exerciseGen *generator.Generator,
+	historiaGen *generator.HistoriaGenerator,
+	chainService *chain.Service,
+	vocabService vocabulary.ServiceInterface,
 	aiService *ai.Service,
 	cacheClient *cache.Client,
 ) *Handler {
@@ -40,11 +57,33 @@ func NewHandler(
 		tokenService:    tokenService,
 		ankiService:     ankiService,
 		exerciseService: exerciseService,
+		exerciseGen:     exerciseGen,
+		historiaGen:     historiaGen,
+		chainService:    chainService,
+		vocabService:    vocabService,
 		aiService:       aiService,
 		cacheClient:     cacheClient,
 	}
 }
 
+// WithEvents publica eventos de domínio a partir dos handlers (ex: pipeline de conversa)
+func (h *Handler) WithEvents(publisher events.Publisher) *Handler {
+	h.events = publisher
+	return h
+}
+
+// WithConversationMetrics contadores de latência compartilhados pelos pipelines de conversa
+func (h *Handler) WithConversationMetrics(m *processor.Metrics) *Handler {
+	h.convMetrics = m
+	return h
+}
+
+// WithConversations grava as conversas de voz no Postgres e habilita o GET /conversations
+func (h *Handler) WithConversations(s conversation.ServiceInterface) *Handler {
+	h.convService = s
+	return h
+}
+
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	SendSuccess(w, http.StatusOK, "Service is healthy", nil)
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"extension-backend/internal/cefr"
 	"extension-backend/internal/http/middleware"
 	"extension-backend/internal/phrase"
 	"extension-backend/internal/shared"
@@ -24,6 +25,16 @@ func (h *Handler) ListPhrases(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Filtro opcional por nível CEFR (?cefr=B2)
+	var nivel cefr.Level
+	if c := r.URL.Query().Get("cefr"); c != "" {
+		var ok bool
+		if nivel, ok = cefr.ParseLevel(c); !ok {
+			SendError(w, http.StatusBadRequest, "invalid cefr level")
+			return
+		}
+	}
+
 	claims := middleware.GetUserFromContext(ctx)
 	if claims == nil {
 		SendError(w, http.StatusUnauthorized, "unauthorized")
@@ -33,6 +44,7 @@ func (h *Handler) ListPhrases(w http.ResponseWriter, r *http.Request) {
 	result, err := h.phraseService.GetByUserIDPaginated(ctx, claims.UserID, phrase.PaginationParams{
 		Cursor: cursor,
 		Limit:  limit,
+		CEFR:   string(nivel),
 	})
 	if err != nil {
 		SendError(w, http.StatusInternalServerError, err.Error())
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/user"
+)
+
+// IssueSSETicket emite um ticket de uso único (30s) para abrir o stream SSE
+// com ?ticket=, usado por clientes que não enviam cookie
+func (h *Handler) IssueSSETicket(w http.ResponseWriter, r *http.Request) {
+	h.issueTicket(w, r, user.TicketAudienceSSE, "SSE ticket issued")
+}
+
+// IssueConversationTicket emite um ticket de uso único (30s) para abrir o WebSocket
+// de voz com ?ticket= (o WebSocket do browser não envia headers). Não abre o SSE.
+func (h *Handler) IssueConversationTicket(w http.ResponseWriter, r *http.Request) {
+	h.issueTicket(w, r, user.TicketAudienceConversation, "Conversation ticket issued")
+}
+
+func (h *Handler) issueTicket(w http.ResponseWriter, r *http.Request, audience, message string) {
+	claims := middleware.GetUserFromContext(r.Context())
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	ticket, expiresAt, err := h.tokenService.GenerateTicket(claims.UserID, audience)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, "failed to issue ticket")
+		return
+	}
+
+	SendSuccess(w, http.StatusCreated, message, map[string]interface{}{
+		"ticket":     ticket,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/vocabulary"
+)
+
+// ListVocabulary retorna as palavras do usuário com status known/learning/new
+// Query params: idioma, status, limit, offset
+func (h *Handler) ListVocabulary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	params := vocabulary.ListParams{
+		Idioma: q.Get("idioma"),
+		Status: q.Get("status"),
+	}
+	if l, err := strconv.Atoi(q.Get("limit")); err == nil {
+		params.Limit = l
+	}
+	if o, err := strconv.Atoi(q.Get("offset")); err == nil {
+		params.Offset = o
+	}
+
+	result, err := h.vocabService.List(ctx, claims.UserID, params)
+	if err != nil {
+		if errors.Is(err, vocabulary.ErrInvalidStatus) {
+			SendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Vocabulary retrieved", result)
+}
+
+// RebuildVocabulary reindexa todas as frases do usuário
+func (h *Handler) RebuildVocabulary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claims := middleware.GetUserFromContext(ctx)
+	if claims == nil {
+		SendError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	total, err := h.vocabService.Rebuild(ctx, claims.UserID)
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SendSuccess(w, http.StatusOK, "Vocabulary rebuilt", map[string]int{"frases": total})
+}
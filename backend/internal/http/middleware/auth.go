@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"extension-backend/internal/user"
 )
@@ -18,23 +19,7 @@ const UserContextKey contextKey = "user"
 func Auth(tokenService *user.TokenService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var tokenString string
-
-			// 1. Try Authorization header first (preferred for API/Extension)
-			authHeader := r.Header.Get("Authorization")
-			if authHeader != "" {
-				parts := strings.Split(authHeader, " ")
-				if len(parts) == 2 && parts[0] == "Bearer" {
-					tokenString = parts[1]
-				}
-			}
-
-			// 2. Fallback to cookie
-			if tokenString == "" {
-				if cookie, err := r.Cookie("access_token"); err == nil && cookie.Value != "" {
-					tokenString = cookie.Value
-				}
-			}
+			tokenString := tokenFromRequest(r)
 
 			// No token found
 			if tokenString == "" {
@@ -54,6 +39,69 @@ func Auth(tokenService *user.TokenService) func(http.Handler) http.Handler {
 	}
 }
 
+// TicketStore garante que cada ticket seja usado uma única vez (sse.TicketStore)
+type TicketStore interface {
+	Consume(ctx context.Context, ticketID string, ttl time.Duration) (bool, error)
+}
+
+// AuthOrTicket como Auth, aceitando também um ticket de uso único (?ticket=) emitido
+// para a finalidade audience, para clientes que não conseguem enviar headers, como o
+// WebSocket do browser. tickets nil desliga o ticket.
+func AuthOrTicket(tokenService *user.TokenService, tickets TicketStore, audience string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var claims *user.TokenClaims
+
+			if tokenString := tokenFromRequest(r); tokenString != "" {
+				c, err := tokenService.ValidateAccessToken(tokenString)
+				if err != nil {
+					http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+					return
+				}
+				claims = c
+			} else if ticket := r.URL.Query().Get("ticket"); ticket != "" && tickets != nil {
+				t, err := tokenService.ValidateTicket(ticket, audience)
+				if err != nil {
+					http.Error(w, "invalid ticket", http.StatusUnauthorized)
+					return
+				}
+				ok, err := tickets.Consume(r.Context(), t.ID, user.TicketTTL)
+				if err != nil {
+					http.Error(w, "failed to validate ticket", http.StatusInternalServerError)
+					return
+				}
+				if !ok {
+					http.Error(w, "ticket already used", http.StatusUnauthorized)
+					return
+				}
+				claims = &user.TokenClaims{UserID: t.UserID}
+			}
+
+			if claims == nil {
+				http.Error(w, "authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// tokenFromRequest lê o JWT do header Authorization (preferido) ou do cookie
+func tokenFromRequest(r *http.Request) string {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			return parts[1]
+		}
+	}
+	if cookie, err := r.Cookie("access_token"); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	return ""
+}
+
 func GetUserFromContext(ctx context.Context) *user.TokenClaims {
 	claims, ok := ctx.Value(UserContextKey).(*user.TokenClaims)
 	if !ok {
@@ -61,3 +109,12 @@ func GetUserFromContext(ctx context.Context) *user.TokenClaims {
 	}
 	return claims
 }
+
+// UserID retorna o usuário autenticado da request (cache.UserFunc)
+func UserID(r *http.Request) (int, bool) {
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		return 0, false
+	}
+	return claims.UserID, true
+}
@@ -11,7 +11,8 @@ func CORS(next http.Handler) http.Handler {
 		// No wildcard fallback — incompatible with credentials: include
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Cache")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if r.Method == "OPTIONS" {
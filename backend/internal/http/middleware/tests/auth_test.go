@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/sse"
+	"extension-backend/internal/user"
+)
+
+// whoami responde com o usuário autenticado (ou 500 se o middleware deixou passar sem)
+func whoami(t *testing.T, tokens *user.TokenService) http.Handler {
+	t.Helper()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.UserID(r)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-User", strconv.Itoa(userID))
+	})
+	return middleware.AuthOrTicket(tokens, sse.NewMemoryTicketStore(), user.TicketAudienceConversation)(next)
+}
+
+func TestAuthOrTicket_RejectsAnonymousUpgrade(t *testing.T) {
+	h := whoami(t, user.NewTokenService())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/conversation", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestAuthOrTicket_AcceptsBearerAndCookie(t *testing.T) {
+	tokens := user.NewTokenService()
+	h := whoami(t, tokens)
+	token, err := tokens.GenerateAccessToken(&user.User{ID: 7})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	bearer := httptest.NewRequest(http.MethodGet, "/api/v1/conversation", nil)
+	bearer.Header.Set("Authorization", "Bearer "+token)
+	cookie := httptest.NewRequest(http.MethodGet, "/api/v1/conversation", nil)
+	cookie.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+
+	for name, req := range map[string]*http.Request{"bearer": bearer, "cookie": cookie} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get("X-User") != "7" {
+			t.Errorf("%s: expected user 7, got %d %q", name, rec.Code, rec.Header().Get("X-User"))
+		}
+	}
+}
+
+func TestAuthOrTicket_TicketIsSingleUse(t *testing.T) {
+	tokens := user.NewTokenService()
+	h := whoami(t, tokens)
+	ticket, _, err := tokens.GenerateTicket(3, user.TicketAudienceConversation)
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %v", err)
+	}
+
+	first := httptest.NewRecorder()
+	h.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/v1/conversation?ticket="+ticket, nil))
+	if first.Code != http.StatusOK || first.Header().Get("X-User") != "3" {
+		t.Fatalf("expected ticket to authenticate user 3, got %d %q", first.Code, first.Header().Get("X-User"))
+	}
+
+	reused := httptest.NewRecorder()
+	h.ServeHTTP(reused, httptest.NewRequest(http.MethodGet, "/api/v1/conversation?ticket="+ticket, nil))
+	if reused.Code != http.StatusUnauthorized {
+		t.Errorf("expected reused ticket to be rejected, got %d", reused.Code)
+	}
+}
+
+func TestAuthOrTicket_RejectsSSETicket(t *testing.T) {
+	tokens := user.NewTokenService()
+	h := whoami(t, tokens)
+	// Ticket do stream SSE (somente leitura) não abre sessão de voz
+	ticket, _, err := tokens.GenerateTicket(3, user.TicketAudienceSSE)
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/conversation?ticket="+ticket, nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected SSE ticket to be rejected on the conversation endpoint, got %d", rec.Code)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"extension-backend/internal/cache"
 	"extension-backend/internal/http/handlers"
 	"extension-backend/internal/http/middleware"
+	"extension-backend/internal/metrics"
 	"extension-backend/internal/settings"
 	"extension-backend/internal/sse"
 	"extension-backend/internal/user"
@@ -26,20 +27,34 @@ func NewRouter() chi.Router {
 	return r
 }
 
-func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler, settingsHandler *settings.Handler, youtubeHandler *youtube.Handler, aiMiddleware *middleware.AIMiddleware, sseHub *sse.Hub, cacheClient *cache.Client, tokenService *user.TokenService) {
+func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler, settingsHandler *settings.Handler, youtubeHandler *youtube.Handler, aiMiddleware *middleware.AIMiddleware, sseHub *sse.Hub, metricsRegistry *metrics.Registry, httpCache *cache.HTTPCache, tokenService *user.TokenService) {
 	r.Get("/health", h.HealthCheck)
+	if metricsRegistry != nil {
+		r.Get("/metrics", metricsRegistry.Handler())
+	}
 
-	// SSE endpoint — protected by cookie auth (Hub extracts UserID from cookie)
+	// SSE endpoint — protected by cookie auth or a single-use ticket (POST /api/v1/sse/ticket)
 	if sseHub != nil {
 		r.Get("/api/v1/sse/translations", sseHub.Handler())
 	}
 
+	// Mutações de frases e grupos respondem com o ETag da listagem nova
+	if httpCache != nil {
+		httpCache.WithRepresentation("phrases", "/api/v1/phrases", cache.DefaultTTL, h.ListPhrases).
+			WithRepresentation("groups", "/api/v1/groups", cache.DefaultTTL, h.ListGroups)
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/", h.Welcome)
 
+		// Audio WS Pipeline Upgrade point — cookie, Bearer ou ?ticket= (o WebSocket do browser não envia headers)
+		var wsTickets middleware.TicketStore
+		if sseHub != nil {
+			wsTickets = sseHub.Tickets()
+		}
+		r.With(middleware.AuthOrTicket(tokenService, wsTickets, user.TicketAudienceConversation)).Get("/conversation", h.ConversationWS)
+
 		// ==================== PUBLIC ROUTES ====================
-		r.Get("/conversation", h.ConversationWS) // Audio WS Pipeline Upgrade point
-		
 		r.Route("/auth", func(r chi.Router) {
 			r.Post("/login", authHandler.Login)
 			r.Post("/register", authHandler.Register)
@@ -58,31 +73,38 @@ func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.Auth(tokenService))
 
+			r.Post("/sse/ticket", h.IssueSSETicket)
+			r.Post("/conversation/ticket", h.IssueConversationTicket)
+
 			r.Route("/phrases", func(r chi.Router) {
 				// GET routes com cache
-				if cacheClient != nil {
-					r.With(cacheClient.Middleware("phrases", cache.DefaultTTL)).Get("/", h.ListPhrases)
-					r.With(cacheClient.Middleware("phrases", cache.DefaultTTL)).Get("/{id}", h.GetPhrase)
+				if httpCache != nil {
+					r.With(httpCache.Middleware("phrases", cache.DefaultTTL)).Get("/", h.ListPhrases)
+					r.With(httpCache.Middleware("phrases", cache.DefaultTTL)).Get("/{id}", h.GetPhrase)
 				} else {
 					r.Get("/", h.ListPhrases)
 					r.Get("/{id}", h.GetPhrase)
 				}
 
-				r.Delete("/{id}", h.DeletePhrase)
+				if httpCache != nil {
+					r.With(httpCache.InvalidateOn("phrases")).Delete("/{id}", h.DeletePhrase)
+				} else {
+					r.Delete("/{id}", h.DeletePhrase)
+				}
 
 				// Mutações com invalidação de cache
 				if aiMiddleware != nil {
-					if cacheClient != nil {
-						r.With(cacheClient.InvalidateOn("cache:phrases:*"), aiMiddleware.ProcessTranslation).Post("/", h.CreatePhrase)
-						r.With(cacheClient.InvalidateOn("cache:phrases:*"), aiMiddleware.ProcessTranslation).Put("/{id}", h.UpdatePhrase)
+					if httpCache != nil {
+						r.With(httpCache.InvalidateOn("phrases"), aiMiddleware.ProcessTranslation).Post("/", h.CreatePhrase)
+						r.With(httpCache.InvalidateOn("phrases"), aiMiddleware.ProcessTranslation).Put("/{id}", h.UpdatePhrase)
 					} else {
 						r.With(aiMiddleware.ProcessTranslation).Post("/", h.CreatePhrase)
 						r.With(aiMiddleware.ProcessTranslation).Put("/{id}", h.UpdatePhrase)
 					}
 				} else {
-					if cacheClient != nil {
-						r.With(cacheClient.InvalidateOn("cache:phrases:*")).Post("/", h.CreatePhrase)
-						r.With(cacheClient.InvalidateOn("cache:phrases:*")).Put("/{id}", h.UpdatePhrase)
+					if httpCache != nil {
+						r.With(httpCache.InvalidateOn("phrases")).Post("/", h.CreatePhrase)
+						r.With(httpCache.InvalidateOn("phrases")).Put("/{id}", h.UpdatePhrase)
 					} else {
 						r.Post("/", h.CreatePhrase)
 						r.Put("/{id}", h.UpdatePhrase)
@@ -91,8 +113,8 @@ func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler
 			})
 
 			r.Route("/users", func(r chi.Router) {
-				if cacheClient != nil {
-					r.With(cacheClient.Middleware("users", cache.DefaultTTL)).Get("/", h.ListUsers)
+				if httpCache != nil {
+					r.With(httpCache.Middleware("users", cache.DefaultTTL)).Get("/", h.ListUsers)
 				} else {
 					r.Get("/", h.ListUsers)
 				}
@@ -109,15 +131,18 @@ func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler
 			})
 
 			r.Route("/groups", func(r chi.Router) {
-				if cacheClient != nil {
-					r.With(cacheClient.Middleware("groups", cache.DefaultTTL)).Get("/", h.ListGroups)
+				if httpCache != nil {
+					r.With(httpCache.Middleware("groups", cache.DefaultTTL)).Get("/", h.ListGroups)
+					r.With(httpCache.InvalidateOn("groups")).Post("/", h.CreateGroup)
+					r.With(httpCache.InvalidateOn("groups")).Put("/{id}", h.UpdateGroup)
+					r.With(httpCache.InvalidateOn("groups")).Delete("/{id}", h.DeleteGroup)
 				} else {
 					r.Get("/", h.ListGroups)
+					r.Post("/", h.CreateGroup)
+					r.Put("/{id}", h.UpdateGroup)
+					r.Delete("/{id}", h.DeleteGroup)
 				}
-				r.Post("/", h.CreateGroup)
 				r.Get("/{id}", h.GetGroup)
-				r.Put("/{id}", h.UpdateGroup)
-				r.Delete("/{id}", h.DeleteGroup)
 			})
 
 			r.Route("/anki", func(r chi.Router) {
@@ -130,9 +155,40 @@ func RegisterRoutes(r chi.Router, h *handlers.Handler, authHandler *auth.Handler
 				r.Get("/", h.ListExercises)
 				r.Get("/catalogo/{catalogoId}", h.GetExercisesByCatalogo)
 				r.Get("/histories", h.ListHistories)
+				r.Post("/histories/generate", h.GenerateHistory)
+				r.Post("/generate", h.GenerateExercises)
 				r.Get("/{id}", h.GetExercise)
 				r.Post("/{id}/view", h.MarkExerciseAsViewed)
 				r.Post("/chain/next-word", h.ChainNextWord)
+				r.Post("/chain/sessions", h.StartChainSession)
+				r.Get("/chain/sessions/{sessionId}", h.GetChainSession)
+				r.Post("/chain/sessions/{sessionId}/turn", h.ChainTurn)
+				r.Post("/chain/sessions/{sessionId}/finish", h.FinishChainSession)
+			})
+
+			r.Route("/vocabulary", func(r chi.Router) {
+				r.Get("/", h.ListVocabulary)
+				r.Post("/rebuild", h.RebuildVocabulary)
+			})
+
+			r.Route("/conversations", func(r chi.Router) {
+				r.Get("/", h.ListConversations)
+				r.Get("/scenarios", h.ListScenarios)
+				r.Get("/{id}", h.GetConversation)
+
+				// Salvar trecho como frase: a tradução sai do service (conteúdo resolvido + turno como contexto)
+				if httpCache != nil {
+					r.With(httpCache.InvalidateOn("phrases")).Post("/{id}/phrases", h.SaveConversationPhrase)
+				} else {
+					r.Post("/{id}/phrases", h.SaveConversationPhrase)
+				}
+
+				// Correção já traz a explicação: vira frase sem passar pela tradução
+				if httpCache != nil {
+					r.With(httpCache.InvalidateOn("phrases")).Post("/corrections/{id}/phrase", h.SaveCorrectionPhrase)
+				} else {
+					r.Post("/corrections/{id}/phrase", h.SaveCorrectionPhrase)
+				}
 			})
 
 			r.Route("/youtube", func(r chi.Router) {
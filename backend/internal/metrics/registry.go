@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Tipos de métrica no formato de exposição do Prometheus
+const (
+	KindCounter = "counter"
+	KindGauge   = "gauge"
+)
+
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func() float64
+}
+
+// Registry guarda as métricas expostas em /metrics. Cada módulo mantém os
+// próprios contadores (atomic) e registra funções que leem o valor atual.
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]metric
+}
+
+// NewRegistry cria um Registry vazio
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// CounterFunc registra um contador (só cresce) lido de fn
+func (r *Registry) CounterFunc(name, help string, fn func() int64) {
+	r.register(metric{name: name, help: help, kind: KindCounter, value: func() float64 { return float64(fn()) }})
+}
+
+// GaugeFunc registra um valor instantâneo lido de fn
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(metric{name: name, help: help, kind: KindGauge, value: fn})
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[m.name]; ok {
+		panic("metrics: " + m.name + " registered twice")
+	}
+	r.metrics[m.name] = m
+}
+
+// Snapshot retorna o valor atual de cada métrica
+func (r *Registry) Snapshot() map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]float64, len(r.metrics))
+	for name, m := range r.metrics {
+		out[name] = m.value()
+	}
+	return out
+}
+
+// Handler expõe as métricas no formato texto do Prometheus, ordenadas por nome
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.mu.RLock()
+		list := make([]metric, 0, len(r.metrics))
+		for _, m := range r.metrics {
+			list = append(list, m)
+		}
+		r.mu.RUnlock()
+		sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, m := range list {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", m.name, m.help, m.name, m.kind, m.name, m.value())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"extension-backend/internal/metrics"
+)
+
+func TestHandler_WritesPrometheusText(t *testing.T) {
+	reg := metrics.NewRegistry()
+	var delivered int64 = 42
+	reg.CounterFunc("sse_events_delivered_total", "Eventos entregues", func() int64 { return delivered })
+	reg.GaugeFunc("sse_clients", "Clientes conectados", func() float64 { return 3 })
+
+	rec := httptest.NewRecorder()
+	reg.Handler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	want := "# HELP sse_clients Clientes conectados\n# TYPE sse_clients gauge\nsse_clients 3\n" +
+		"# HELP sse_events_delivered_total Eventos entregues\n# TYPE sse_events_delivered_total counter\nsse_events_delivered_total 42\n"
+	if body != want {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestRegister_DuplicateNamePanics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	reg.GaugeFunc("x", "", func() float64 { return 0 })
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate metric")
+		}
+	}()
+	reg.GaugeFunc("x", "", func() float64 { return 0 })
+}
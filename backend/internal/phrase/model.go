@@ -36,6 +36,7 @@ type PhraseWithDetails struct {
 	URLOrigem    string    `json:"url_origem,omitempty"`
 	TituloPagina string    `json:"titulo_pagina,omitempty"`
 	CapturadoEm  time.Time `json:"capturado_em"`
+	NivelCEFR    *string   `json:"nivel_cefr,omitempty"`
 	Detalhes     *Details  `json:"detalhes,omitempty"`
 }
 
@@ -51,6 +52,7 @@ type Details struct {
 type PaginationParams struct {
 	Cursor string
 	Limit  int
+	CEFR   string // filtra por frases.nivel_cefr ("B2"); vazio = todas
 }
 
 // PaginatedResult resultado paginado
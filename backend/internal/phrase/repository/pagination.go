@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"extension-backend/internal/phrase"
 )
@@ -20,18 +21,26 @@ func (r *Repository) GetAllPaginated(ctx context.Context, params phrase.Paginati
 	var args []any
 	var whereClause string
 
+	var conds []string
 	if cursor != nil {
-		whereClause = "WHERE (f.capturado_em, f.id) < ($1, $2)"
-		args = []any{cursor.CreatedAt, cursor.ID, limit + 1}
-	} else {
-		args = []any{limit + 1}
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		conds = append(conds, "(f.capturado_em, f.id) < ($1, $2)")
 	}
+	if params.CEFR != "" {
+		args = append(args, params.CEFR)
+		conds = append(conds, fmt.Sprintf("f.nivel_cefr = $%d", len(args)))
+	}
+	if len(conds) > 0 {
+		whereClause = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, limit+1)
 
 	query := fmt.Sprintf(`
 		SELECT 
 			f.id, f.usuario_id, f.conteudo, f.idioma_origem, 
 			f.url_origem, f.titulo_pagina, f.capturado_em,
-			d.traducao_completa, d.explicacao, d.fatias_traducoes, d.modelo_ia
+			d.traducao_completa, d.explicacao, d.fatias_traducoes, d.modelo_ia,
+			f.nivel_cefr
 		FROM frases f
 		LEFT JOIN frase_detalhes d ON d.frase_id = f.id
 		%s
@@ -55,6 +64,7 @@ func (r *Repository) GetAllPaginated(ctx context.Context, params phrase.Paginati
 			&p.ID, &p.UsuarioID, &p.Conteudo, &p.IdiomaOrigem,
 			&p.URLOrigem, &p.TituloPagina, &p.CapturadoEm,
 			&traducao, &explicacao, &fatias, &modeloIA,
+			&p.NivelCEFR,
 		); err != nil {
 			return nil, err
 		}
@@ -105,19 +115,24 @@ func (r *Repository) GetByUserIDPaginated(ctx context.Context, userID int, param
 	var args []any
 	var whereClause string
 
+	args = []any{userID}
+	whereClause = "WHERE f.usuario_id = $1"
 	if cursor != nil {
-		whereClause = "WHERE f.usuario_id = $1 AND (f.capturado_em, f.id) < ($2, $3)"
-		args = []any{userID, cursor.CreatedAt, cursor.ID, limit + 1}
-	} else {
-		whereClause = "WHERE f.usuario_id = $1"
-		args = []any{userID, limit + 1}
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		whereClause += " AND (f.capturado_em, f.id) < ($2, $3)"
+	}
+	if params.CEFR != "" {
+		args = append(args, params.CEFR)
+		whereClause += fmt.Sprintf(" AND f.nivel_cefr = $%d", len(args))
 	}
+	args = append(args, limit+1)
 
 	query := fmt.Sprintf(`
 		SELECT 
 			f.id, f.usuario_id, f.conteudo, f.idioma_origem, 
 			f.url_origem, f.titulo_pagina, f.capturado_em,
-			d.traducao_completa, d.explicacao, d.fatias_traducoes, d.modelo_ia
+			d.traducao_completa, d.explicacao, d.fatias_traducoes, d.modelo_ia,
+			f.nivel_cefr
 		FROM frases f
 		LEFT JOIN frase_detalhes d ON d.frase_id = f.id
 		%s
@@ -141,6 +156,7 @@ func (r *Repository) GetByUserIDPaginated(ctx context.Context, userID int, param
 			&p.ID, &p.UsuarioID, &p.Conteudo, &p.IdiomaOrigem,
 			&p.URLOrigem, &p.TituloPagina, &p.CapturadoEm,
 			&traducao, &explicacao, &fatias, &modeloIA,
+			&p.NivelCEFR,
 		); err != nil {
 			return nil, err
 		}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 
+	"extension-backend/internal/events"
 	"extension-backend/internal/phrase"
 )
 
@@ -26,9 +28,28 @@ func (s *Service) Create(ctx context.Context, input phrase.CreateInput) (*phrase
 	if err := s.repo.Create(ctx, p); err != nil {
 		return nil, err
 	}
+	s.publishCreated(ctx, p)
 	return p, nil
 }
 
+// publishCreated avisa os assinantes; falha no bus não desfaz a frase criada
+func (s *Service) publishCreated(ctx context.Context, p *phrase.Phrase) {
+	if s.events == nil {
+		return
+	}
+	e, err := events.New(events.PhraseCreated, p.UsuarioID, events.PhraseCreatedPayload{
+		PhraseID: p.ID,
+		Conteudo: p.Conteudo,
+		Idioma:   p.IdiomaOrigem,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Phrase] Failed to publish %s for phrase %d: %v", events.PhraseCreated, p.ID, err)
+	}
+}
+
 // GetByID busca frase por ID
 func (s *Service) GetByID(ctx context.Context, id string) (*phrase.Phrase, error) {
 	intID, err := strconv.Atoi(id)
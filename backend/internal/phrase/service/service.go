@@ -1,15 +1,23 @@
 package service
 
 import (
+	"extension-backend/internal/events"
 	"extension-backend/internal/phrase"
 )
 
 // Service gerencia a lógica de negócio para frases
 type Service struct {
-	repo phrase.RepositoryInterface
+	repo   phrase.RepositoryInterface
+	events events.Publisher
 }
 
 // New cria uma nova instância do serviço
 func New(repo phrase.RepositoryInterface) *Service {
 	return &Service{repo: repo}
 }
+
+// WithEvents publica PhraseCreated a cada frase criada
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
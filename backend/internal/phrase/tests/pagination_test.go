@@ -26,10 +26,10 @@ func TestGetByUserIDPaginated_FirstPage(t *testing.T) {
 		WithArgs(1, 21). // 1 (UserID), 21 (20 + 1)
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "usuario_id", "conteudo", "idioma_origem", "url_origem", "titulo_pagina", "capturado_em",
-			"traducao_completa", "explicacao", "fatias_traducoes", "modelo_ia",
+			"traducao_completa", "explicacao", "fatias_traducoes", "modelo_ia", "nivel_cefr",
 		}).AddRow(
 			100, 1, "Hello test", "en", "http://test.com", "Page Title", now,
-			nil, nil, nil, nil, // no initial details attached for simplicity
+			nil, nil, nil, nil, nil, // no initial details attached for simplicity
 		))
 
 	res, err := repo.GetByUserIDPaginated(context.Background(), 1, phrase.PaginationParams{
@@ -75,10 +75,10 @@ func TestGetByUserIDPaginated_CursorMode(t *testing.T) {
 		WithArgs(1, pgxmock.AnyArg(), cursor.ID, 21).
 		WillReturnRows(pgxmock.NewRows([]string{
 			"id", "usuario_id", "conteudo", "idioma_origem", "url_origem", "titulo_pagina", "capturado_em",
-			"traducao_completa", "explicacao", "fatias_traducoes", "modelo_ia",
+			"traducao_completa", "explicacao", "fatias_traducoes", "modelo_ia", "nivel_cefr",
 		}).AddRow(
 			149, 1, "Another phrase", "en", "http://test.com", "Page Title", now.Add(-1*time.Minute),
-			nil, nil, nil, nil,
+			nil, nil, nil, nil, nil,
 		))
 
 	res, err := repo.GetByUserIDPaginated(context.Background(), 1, phrase.PaginationParams{
@@ -98,3 +98,39 @@ func TestGetByUserIDPaginated_CursorMode(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %s", err)
 	}
 }
+
+func TestGetByUserIDPaginated_CEFRFilter(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer mock.Close()
+
+	repo := repository.New(mock)
+	nivel := "B2"
+
+	mock.ExpectQuery("SELECT (.+) FROM frases f LEFT JOIN frase_detalhes d (.+) WHERE f.usuario_id = \\$1 AND f.nivel_cefr = \\$2 ORDER BY f.capturado_em DESC, f.id DESC LIMIT \\$3").
+		WithArgs(1, "B2", 21).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "usuario_id", "conteudo", "idioma_origem", "url_origem", "titulo_pagina", "capturado_em",
+			"traducao_completa", "explicacao", "fatias_traducoes", "modelo_ia", "nivel_cefr",
+		}).AddRow(
+			120, 1, "Had I known, I would have stayed.", "en", "http://test.com", "Page Title", time.Now(),
+			nil, nil, nil, nil, &nivel,
+		))
+
+	res, err := repo.GetByUserIDPaginated(context.Background(), 1, phrase.PaginationParams{
+		Limit: 20,
+		CEFR:  "B2",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(res.Data) != 1 || res.Data[0].NivelCEFR == nil || *res.Data[0].NivelCEFR != "B2" {
+		t.Errorf("expected one B2 phrase, got %+v", res.Data)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
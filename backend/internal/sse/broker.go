@@ -0,0 +1,83 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Broker distribui eventos SSE entre as instâncias da API. Cada instância
+// publica no canal do usuário e entrega aos seus próprios clientes o que recebe.
+type Broker interface {
+	Publish(ctx context.Context, userID int, data []byte) error
+	// Run assina os canais e chama deliver para cada mensagem até o ctx terminar
+	Run(ctx context.Context, deliver func(userID int, data []byte))
+	// Ready indica se a assinatura está ativa; sem ela a entrega deve ser local
+	Ready() bool
+}
+
+const userChannelPrefix = "sse:user:"
+
+// errBrokerNotReady assinatura inativa: publicar faria a mensagem sumir
+var errBrokerNotReady = errors.New("sse broker not ready")
+
+// RedisBroker Broker sobre Redis pub/sub, um canal por usuário (sse:user:<id>)
+type RedisBroker struct {
+	rdb   *redis.Client
+	ready atomic.Bool
+}
+
+// NewRedisBroker cria um Broker sobre o client Redis
+func NewRedisBroker(rdb *redis.Client) *RedisBroker {
+	return &RedisBroker{rdb: rdb}
+}
+
+// Publish envia o evento serializado para o canal do usuário
+func (b *RedisBroker) Publish(ctx context.Context, userID int, data []byte) error {
+	return b.rdb.Publish(ctx, userChannelPrefix+strconv.Itoa(userID), data).Err()
+}
+
+// Ready indica se a assinatura do padrão sse:user:* está ativa
+func (b *RedisBroker) Ready() bool {
+	return b.ready.Load()
+}
+
+// Run assina sse:user:* e repassa as mensagens; reconecta sozinho quando o Redis volta
+func (b *RedisBroker) Run(ctx context.Context, deliver func(userID int, data []byte)) {
+	pubsub := b.rdb.PSubscribe(ctx, userChannelPrefix+"*")
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if b.ready.Swap(false) {
+				log.Printf("[SSE] Redis pub/sub unavailable, falling back to local delivery: %v", err)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			time.Sleep(time.Second)
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if !b.ready.Swap(true) {
+				log.Printf("[SSE] Subscribed to %s", m.Channel)
+			}
+		case *redis.Message:
+			userID, err := strconv.Atoi(strings.TrimPrefix(m.Channel, userChannelPrefix))
+			if err != nil {
+				log.Printf("[SSE] Ignoring message on unexpected channel %s", m.Channel)
+				continue
+			}
+			deliver(userID, []byte(m.Payload))
+		}
+	}
+}
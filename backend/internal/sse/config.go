@@ -0,0 +1,59 @@
+package sse
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config limites do Hub. NewHub lê do ambiente; WithConfig sobrescreve (testes).
+type Config struct {
+	// BufferSize eventos enfileirados por cliente antes de começar a descartar (SSE_BUFFER_SIZE)
+	BufferSize int
+	// PingInterval intervalo do keep-alive (SSE_PING_INTERVAL, ex: "15s")
+	PingInterval time.Duration
+	// WriteTimeout prazo para escrever e dar flush em um evento (SSE_WRITE_TIMEOUT)
+	WriteTimeout time.Duration
+	// EvictAfter tempo com o canal cheio até o cliente ser desconectado (SSE_EVICT_AFTER)
+	EvictAfter time.Duration
+}
+
+// DefaultConfig valores usados quando a variável não está definida
+func DefaultConfig() Config {
+	return Config{
+		BufferSize:   10,
+		PingInterval: 5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		EvictAfter:   15 * time.Second,
+	}
+}
+
+// ConfigFromEnv parte do DefaultConfig e aplica as variáveis SSE_* válidas
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	if v := os.Getenv("SSE_BUFFER_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.BufferSize = n
+		} else {
+			log.Printf("[SSE] Ignoring invalid SSE_BUFFER_SIZE=%q", v)
+		}
+	}
+	cfg.PingInterval = envDuration("SSE_PING_INTERVAL", cfg.PingInterval)
+	cfg.WriteTimeout = envDuration("SSE_WRITE_TIMEOUT", cfg.WriteTimeout)
+	cfg.EvictAfter = envDuration("SSE_EVICT_AFTER", cfg.EvictAfter)
+	return cfg
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("[SSE] Ignoring invalid %s=%q", key, v)
+		return def
+	}
+	return d
+}
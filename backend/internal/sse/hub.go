@@ -1,10 +1,14 @@
 package sse
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -16,22 +20,37 @@ import (
 type Hub struct {
 	repo         *repository.Repository
 	service      *Service
+	broker       Broker
 	tokenService *user.TokenService
+	tickets      TicketStore
+	cfg          Config
+	metrics      *Metrics
 	pingTicker   *time.Ticker
 	stopPing     chan struct{}
+	stopBroker   context.CancelFunc
 }
 
-// NewHub cria um novo Hub com repository e service
+// NewHub cria um novo Hub com repository e service (limites lidos de SSE_*)
 func NewHub(tokenService *user.TokenService) *Hub {
 	repo := repository.New()
 	service := NewService(repo)
 
-	return &Hub{
+	h := &Hub{
 		repo:         repo,
 		service:      service,
 		tokenService: tokenService,
+		tickets:      NewMemoryTicketStore(),
+		metrics:      service.metrics,
 		stopPing:     make(chan struct{}),
 	}
+	return h.WithConfig(ConfigFromEnv())
+}
+
+// WithConfig troca os limites do Hub (buffer, ping, timeouts). Chamar antes do Run.
+func (h *Hub) WithConfig(cfg Config) *Hub {
+	h.cfg = cfg
+	h.service.evictAfter = cfg.EvictAfter
+	return h
 }
 
 // GetService retorna o Service do Hub (usado pelos callers externos)
@@ -39,13 +58,43 @@ func (h *Hub) GetService() *Service {
 	return h.service
 }
 
-// Run inicia o Hub (ping routine)
+// WithStore troca o buffer de replay (ex: RedisStore). Chamar antes do Run.
+func (h *Hub) WithStore(store EventStore) *Hub {
+	h.service.WithStore(store)
+	return h
+}
+
+// WithTicketStore troca o controle de tickets usados (ex: RedisTicketStore)
+func (h *Hub) WithTicketStore(tickets TicketStore) *Hub {
+	h.tickets = tickets
+	return h
+}
+
+// Tickets controle de tickets usados, compartilhado com outras rotas que aceitam ?ticket=
+func (h *Hub) Tickets() TicketStore {
+	return h.tickets
+}
+
+// WithBroker distribui os eventos entre instâncias (ex: RedisBroker). Chamar antes do Run.
+func (h *Hub) WithBroker(broker Broker) *Hub {
+	h.broker = broker
+	h.service.WithBroker(broker)
+	return h
+}
+
+// Run inicia o Hub (ping routine e assinatura do broker)
 func (h *Hub) Run() {
 	h.startPingRoutine()
+
+	if h.broker != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stopBroker = cancel
+		go h.broker.Run(ctx, h.service.deliverRemote)
+	}
 }
 
 func (h *Hub) startPingRoutine() {
-	h.pingTicker = time.NewTicker(5 * time.Second)
+	h.pingTicker = time.NewTicker(h.cfg.PingInterval)
 
 	go func() {
 		for {
@@ -67,13 +116,14 @@ func (h *Hub) sendPingIfHasClients() {
 		return
 	}
 
-	h.service.BroadcastAll(repository.Event{
-		Type: "ping",
-		Payload: map[string]interface{}{
-			"timestamp":    time.Now().Unix(),
-			"client_count": count,
-		},
+	event, err := repository.NewEvent(repository.PingPayload{
+		Timestamp:   time.Now().Unix(),
+		ClientCount: count,
 	})
+	if err != nil {
+		return
+	}
+	h.service.BroadcastAll(event)
 }
 
 // HasActiveClients verifica se há clientes ativos
@@ -89,32 +139,43 @@ func (h *Hub) ClientCount() int {
 // Stop encerra o Hub
 func (h *Hub) Stop() {
 	close(h.stopPing)
+	if h.stopBroker != nil {
+		h.stopBroker()
+	}
 }
 
-// extractUserID extracts user ID from cookie JWT or query param fallback
+// extractUserID autentica a conexão pelo cookie JWT ou por um ticket de uso único (?ticket=)
 func (h *Hub) extractUserID(r *http.Request) (int, error) {
-	// 1. Try cookie auth (browser/extension)
+	if h.tokenService == nil {
+		return 0, fmt.Errorf("authentication unavailable")
+	}
+
+	// 1. Cookie auth (browser/extension)
 	if cookie, err := r.Cookie("access_token"); err == nil && cookie.Value != "" {
-		if h.tokenService != nil {
-			claims, err := h.tokenService.ValidateAccessToken(cookie.Value)
-			if err == nil {
-				return claims.UserID, nil
-			}
-			log.Printf("[SSE] Cookie JWT invalid: %v", err)
+		claims, err := h.tokenService.ValidateAccessToken(cookie.Value)
+		if err == nil {
+			return claims.UserID, nil
 		}
+		log.Printf("[SSE] Cookie JWT invalid: %v", err)
 	}
 
-	// 2. Fallback to ?user_id=N (API/dev usage)
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr != "" {
-		userID, err := strconv.Atoi(userIDStr)
+	// 2. Ticket emitido por POST /sse/ticket (clientes sem cookie, ex: service worker)
+	if ticket := r.URL.Query().Get("ticket"); ticket != "" {
+		claims, err := h.tokenService.ValidateTicket(ticket, user.TicketAudienceSSE)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ticket")
+		}
+		ok, err := h.tickets.Consume(r.Context(), claims.ID, user.TicketTTL)
 		if err != nil {
-			return 0, fmt.Errorf("invalid user_id: %w", err)
+			return 0, fmt.Errorf("failed to validate ticket: %w", err)
+		}
+		if !ok {
+			return 0, fmt.Errorf("ticket already used")
 		}
-		return userID, nil
+		return claims.UserID, nil
 	}
 
-	return 0, fmt.Errorf("authentication required: provide cookie or ?user_id=N")
+	return 0, fmt.Errorf("authentication required: provide cookie or ?ticket=")
 }
 
 // Handler retorna o http.HandlerFunc para conexões SSE
@@ -134,12 +195,7 @@ func (h *Hub) Handler() http.HandlerFunc {
 
 		// Gerar client ID único
 		clientID := repository.GenerateClientID(userID, fmt.Sprintf("%p", r))
-
-		client := &repository.Client{
-			ID:      clientID,
-			UserID:  userID,
-			Channel: make(chan repository.Event, 10),
-		}
+		client := repository.NewClient(clientID, userID, h.cfg.BufferSize)
 
 		// Registrar cliente
 		h.repo.Add(client)
@@ -151,16 +207,36 @@ func (h *Hub) Handler() http.HandlerFunc {
 			log.Printf("[SSE] Client disconnected: %s (user: %d, total: %d)", clientID, userID, h.repo.Count())
 		}()
 
-		// Flush inicial
-		flusher, ok := w.(http.Flusher)
-		if !ok {
+		if _, ok := w.(http.Flusher); !ok {
 			http.Error(w, "SSE not supported", http.StatusInternalServerError)
 			return
 		}
+		stream := &eventWriter{w: w, rc: http.NewResponseController(w), timeout: h.cfg.WriteTimeout}
 
 		// Evento de conexão
-		fmt.Fprintf(w, "event: connected\ndata: {\"client_id\":\"%s\",\"user_id\":%d}\n\n", clientID, userID)
-		flusher.Flush()
+		if err := stream.writeRaw(fmt.Sprintf("event: connected\ndata: {\"client_id\":\"%s\",\"user_id\":%d}\n\n", clientID, userID)); err != nil {
+			log.Printf("[SSE] Failed to write to %s: %v", clientID, err)
+			return
+		}
+
+		// Replay do que o cliente perdeu. O cliente já está registrado, então
+		// eventos novos esperam no canal; os que também vierem no replay são pulados.
+		var lastSent int64
+		if lastID := lastEventID(r); lastID > 0 {
+			missed, err := h.service.Replay(r.Context(), userID, lastID)
+			if err != nil {
+				log.Printf("[SSE] Replay failed for user %d: %v", userID, err)
+			}
+			for _, event := range missed {
+				if err := h.write(stream, client, event); err != nil {
+					return
+				}
+				lastSent = event.ID
+			}
+			if len(missed) > 0 {
+				log.Printf("[SSE] Replayed %d event(s) to %s after id %d", len(missed), clientID, lastID)
+			}
+		}
 
 		// Loop de eventos
 		for {
@@ -168,20 +244,79 @@ func (h *Hub) Handler() http.HandlerFunc {
 			case <-r.Context().Done():
 				return
 
-			case event, ok := <-client.Channel:
-				if !ok {
-					return
-				}
+			case <-client.Evicted():
+				return
 
-				data, err := json.Marshal(event)
-				if err != nil {
-					log.Printf("[SSE] Error marshaling event: %v", err)
+			case event := <-client.Channel:
+				if event.ID > 0 && event.ID <= lastSent {
 					continue
 				}
-
-				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
-				flusher.Flush()
+				if err := h.write(stream, client, event); err != nil {
+					return
+				}
 			}
 		}
 	}
 }
+
+// write escreve um evento no stream do cliente. Erro de escrita (cliente lento
+// demais ou conexão morta) encerra a conexão; evento que não serializa é pulado.
+func (h *Hub) write(stream *eventWriter, client *repository.Client, event repository.Event) error {
+	frame, err := formatEvent(event)
+	if err != nil {
+		log.Printf("[SSE] Error marshaling event: %v", err)
+		return nil
+	}
+	if err := stream.writeRaw(frame); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) && client.Evict() {
+			h.metrics.Evicted.Add(1)
+		}
+		log.Printf("[SSE] Failed to write to %s, closing: %v", client.ID, err)
+		return err
+	}
+	h.metrics.Delivered.Add(1)
+	return nil
+}
+
+// eventWriter escreve e dá flush com prazo: um cliente que não lê não prende o handler
+type eventWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (s *eventWriter) writeRaw(frame string) error {
+	if err := s.rc.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := io.WriteString(s.w, frame); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// formatEvent monta o evento no formato SSE; eventos numerados levam o campo id
+func formatEvent(event repository.Event) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	if event.ID > 0 {
+		return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data), nil
+	}
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", event.Type, data), nil
+}
+
+// lastEventID lê o header Last-Event-ID (reconexão do EventSource) ou
+// ?last_event_id= (cliente que reabre o popup e guardou o último ID)
+func lastEventID(r *http.Request) int64 {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
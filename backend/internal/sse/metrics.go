@@ -0,0 +1,25 @@
+package sse
+
+import (
+	"sync/atomic"
+
+	"extension-backend/internal/metrics"
+)
+
+// Metrics contadores do SSE nesta instância
+type Metrics struct {
+	Delivered atomic.Int64 // eventos escritos no stream de um cliente
+	Dropped   atomic.Int64 // eventos descartados com o canal do cliente cheio
+	Evicted   atomic.Int64 // clientes desconectados por ficarem atrasados ou sem conseguir escrever
+}
+
+// RegisterMetrics expõe os contadores do Hub no registry (/metrics)
+func (h *Hub) RegisterMetrics(reg *metrics.Registry) {
+	m := h.metrics
+	reg.CounterFunc("sse_events_delivered_total", "SSE events written to a client stream", m.Delivered.Load)
+	reg.CounterFunc("sse_events_dropped_total", "SSE events dropped because the client buffer was full", m.Dropped.Load)
+	reg.CounterFunc("sse_clients_evicted_total", "SSE clients disconnected for falling behind or failing to write", m.Evicted.Load)
+	reg.GaugeFunc("sse_clients", "SSE clients connected to this instance", func() float64 {
+		return float64(h.repo.Count())
+	})
+}
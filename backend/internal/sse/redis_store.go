@@ -0,0 +1,80 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"extension-backend/internal/sse/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	seqKeyPrefix    = "sse:seq:"
+	bufferKeyPrefix = "sse:buffer:"
+)
+
+// RedisStore EventStore compartilhado entre instâncias: INCR por usuário para o
+// ID e um sorted set (score = ID) como ring buffer
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore cria um RedisStore
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+type redisEntry struct {
+	Event repository.Event `json:"event"`
+	At    int64            `json:"at"` // unix ms
+}
+
+// Append atribui o ID e guarda o evento, mantendo só os últimos bufferSize
+func (s *RedisStore) Append(ctx context.Context, userID int, event repository.Event) (repository.Event, error) {
+	uid := strconv.Itoa(userID)
+
+	id, err := s.rdb.Incr(ctx, seqKeyPrefix+uid).Result()
+	if err != nil {
+		return event, err
+	}
+	event.ID = id
+
+	data, err := json.Marshal(redisEntry{Event: event, At: time.Now().UnixMilli()})
+	if err != nil {
+		return event, err
+	}
+
+	key := bufferKeyPrefix + uid
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(id), Member: data})
+		pipe.ZRemRangeByRank(ctx, key, 0, -bufferSize-1)
+		pipe.Expire(ctx, key, bufferTTL)
+		return nil
+	})
+	return event, err
+}
+
+// Since retorna os eventos com ID > lastID ainda dentro do TTL
+func (s *RedisStore) Since(ctx context.Context, userID int, lastID int64) ([]repository.Event, error) {
+	members, err := s.rdb.ZRangeByScore(ctx, bufferKeyPrefix+strconv.Itoa(userID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(lastID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-bufferTTL).UnixMilli()
+	var result []repository.Event
+	for _, m := range members {
+		var entry redisEntry
+		if err := json.Unmarshal([]byte(m), &entry); err != nil || entry.At < cutoff {
+			continue
+		}
+		result = append(result, entry.Event)
+	}
+	return result, nil
+}
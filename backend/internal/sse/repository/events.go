@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tipos de evento SSE (campo "event:" do stream)
+const (
+	EventTranslation        = "translation"
+	EventTranslationPartial = "translation_partial"
+	EventTranslationError   = "translation_error"
+	EventPing               = "ping"
+	EventAnkiDueChanged     = "anki_due_changed"
+	EventExerciseCompleted  = "exercise_completed"
+	EventStreakUpdated      = "streak_updated"
+	EventQuotaWarning       = "quota_warning"
+	EventGroupShared        = "group_shared"
+)
+
+// Payload payload tipado de um evento registrado. A versão vai no campo "v"
+// do evento; mudança incompatível no payload = nova struct com versão nova.
+type Payload interface {
+	EventType() string
+	EventVersion() int
+}
+
+// EventSpec descreve um tipo de evento: versão atual e se entra no buffer de replay
+type EventSpec struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	Replay  bool   `json:"replay"`
+}
+
+var registry = map[string]EventSpec{}
+
+func register(spec EventSpec) {
+	if _, ok := registry[spec.Type]; ok {
+		panic("sse: event " + spec.Type + " registered twice")
+	}
+	registry[spec.Type] = spec
+}
+
+func init() {
+	register(EventSpec{Type: EventTranslation, Version: 1, Replay: true})
+	register(EventSpec{Type: EventTranslationPartial, Version: 1})
+	register(EventSpec{Type: EventTranslationError, Version: 1, Replay: true})
+	register(EventSpec{Type: EventPing, Version: 1})
+	register(EventSpec{Type: EventAnkiDueChanged, Version: 1, Replay: true})
+	register(EventSpec{Type: EventExerciseCompleted, Version: 1, Replay: true})
+	register(EventSpec{Type: EventStreakUpdated, Version: 1, Replay: true})
+	register(EventSpec{Type: EventQuotaWarning, Version: 1})
+	register(EventSpec{Type: EventGroupShared, Version: 1, Replay: true})
+}
+
+// Lookup retorna a especificação de um tipo de evento registrado
+func Lookup(eventType string) (EventSpec, bool) {
+	spec, ok := registry[eventType]
+	return spec, ok
+}
+
+// Registry lista os eventos registrados, ordenados por tipo
+func Registry() []EventSpec {
+	specs := make([]EventSpec, 0, len(registry))
+	for _, spec := range registry {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Type < specs[j].Type })
+	return specs
+}
+
+// NewEvent monta o Event de um payload registrado, conferindo tipo e versão
+func NewEvent(p Payload) (Event, error) {
+	spec, ok := registry[p.EventType()]
+	if !ok {
+		return Event{}, fmt.Errorf("unregistered SSE event %q", p.EventType())
+	}
+	if p.EventVersion() != spec.Version {
+		return Event{}, fmt.Errorf("SSE event %q: payload v%d, registry v%d", spec.Type, p.EventVersion(), spec.Version)
+	}
+	return Event{Type: spec.Type, Version: spec.Version, Payload: p}, nil
+}
+
+// TranslationErrorPayload payload de erro na tradução. Reset: as parciais já
+// enviadas para a frase não valem (resposta rejeitada) e devem sumir da tela.
+type TranslationErrorPayload struct {
+	PhraseID int    `json:"phrase_id"`
+	Error    string `json:"error"`
+	Reset    bool   `json:"reset"`
+}
+
+// PingPayload keep-alive enviado a todos os clientes
+type PingPayload struct {
+	Timestamp   int64 `json:"timestamp"`
+	ClientCount int   `json:"client_count"`
+}
+
+// AnkiDueChangedV1 total de cards para revisar agora mudou
+type AnkiDueChangedV1 struct {
+	Due            int    `json:"due"`
+	AnkiID         int    `json:"anki_id,omitempty"`
+	ProximaRevisao string `json:"proxima_revisao,omitempty"`
+}
+
+// ExerciseCompletedV1 exercício concluído (com pontos creditados, se houver)
+type ExerciseCompletedV1 struct {
+	ExercicioID int `json:"exercicio_id"`
+	CatalogoID  int `json:"catalogo_id,omitempty"`
+	Score       int `json:"score,omitempty"`
+	Pontos      int `json:"pontos"`
+}
+
+// StreakUpdatedV1 ofensiva (dias seguidos com atividade) mudou
+type StreakUpdatedV1 struct {
+	OfensivaDias   int `json:"ofensiva_dias"`
+	MelhorOfensiva int `json:"melhor_ofensiva"`
+}
+
+// QuotaWarningV1 cota de um recurso (ex: IA) esgotada ou perto do limite
+type QuotaWarningV1 struct {
+	Quota    string `json:"quota"`
+	Mensagem string `json:"mensagem"`
+}
+
+// GroupSharedV1 grupo do usuário alterado (em outro dispositivo ou aba)
+type GroupSharedV1 struct {
+	GrupoID   int    `json:"grupo_id"`
+	NomeGrupo string `json:"nome_grupo,omitempty"`
+	FraseID   int    `json:"frase_id,omitempty"`
+	Acao      string `json:"acao"`
+}
+
+func (TranslationPayload) EventType() string        { return EventTranslation }
+func (TranslationPayload) EventVersion() int        { return 1 }
+func (TranslationPartialPayload) EventType() string { return EventTranslationPartial }
+func (TranslationPartialPayload) EventVersion() int { return 1 }
+func (TranslationErrorPayload) EventType() string   { return EventTranslationError }
+func (TranslationErrorPayload) EventVersion() int   { return 1 }
+func (PingPayload) EventType() string               { return EventPing }
+func (PingPayload) EventVersion() int               { return 1 }
+func (AnkiDueChangedV1) EventType() string          { return EventAnkiDueChanged }
+func (AnkiDueChangedV1) EventVersion() int          { return 1 }
+func (ExerciseCompletedV1) EventType() string       { return EventExerciseCompleted }
+func (ExerciseCompletedV1) EventVersion() int       { return 1 }
+func (StreakUpdatedV1) EventType() string           { return EventStreakUpdated }
+func (StreakUpdatedV1) EventVersion() int           { return 1 }
+func (QuotaWarningV1) EventType() string            { return EventQuotaWarning }
+func (QuotaWarningV1) EventVersion() int            { return 1 }
+func (GroupSharedV1) EventType() string             { return EventGroupShared }
+func (GroupSharedV1) EventVersion() int             { return 1 }
@@ -1,18 +1,80 @@
 package repository
 
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 // Client representa um cliente SSE conectado, vinculado a um usuário
 type Client struct {
 	ID      string
 	UserID  int
 	Channel chan Event
+
+	backedUpSince atomic.Int64 // UnixNano do primeiro envio com o canal cheio; 0 = em dia
+	evicted       chan struct{}
+	evictOnce     sync.Once
+}
+
+// NewClient cria um cliente com um canal de bufferSize eventos
+func NewClient(id string, userID, bufferSize int) *Client {
+	return &Client{
+		ID:      id,
+		UserID:  userID,
+		Channel: make(chan Event, bufferSize),
+		evicted: make(chan struct{}),
+	}
+}
+
+// Send enfileira o evento sem bloquear. Com o canal cheio retorna false e há
+// quanto tempo o cliente está sem conseguir acompanhar.
+func (c *Client) Send(event Event) (bool, time.Duration) {
+	select {
+	case c.Channel <- event:
+		c.backedUpSince.Store(0)
+		return true, 0
+	default:
+	}
+	now := time.Now().UnixNano()
+	if c.backedUpSince.CompareAndSwap(0, now) {
+		return false, 0
+	}
+	return false, time.Duration(now - c.backedUpSince.Load())
 }
 
-// Event representa um evento SSE
+// Evict sinaliza ao handler que a conexão deve ser encerrada.
+// Retorna true só na primeira chamada.
+func (c *Client) Evict() bool {
+	first := false
+	c.evictOnce.Do(func() {
+		close(c.evicted)
+		first = true
+	})
+	return first
+}
+
+// Evicted é fechado quando o cliente é desconectado pelo servidor
+func (c *Client) Evicted() <-chan struct{} {
+	return c.evicted
+}
+
+// Event representa um evento SSE. ID é 0 para eventos transitórios
+// (ping, parciais), que não são guardados para replay. Version é a versão
+// do payload no registro (ver events.go).
 type Event struct {
+	ID      int64       `json:"id,omitempty"`
 	Type    string      `json:"type"`
+	Version int         `json:"v,omitempty"`
 	Payload interface{} `json:"payload"`
 }
 
+// TranslationPartialPayload payload de evento de tradução parcial (streaming)
+type TranslationPartialPayload struct {
+	PhraseID        int    `json:"phrase_id"`
+	TraducaoParcial string `json:"traducao_parcial"`
+}
+
 // TranslationPayload payload de evento de tradução
 type TranslationPayload struct {
 	PhraseID         int               `json:"phrase_id"`
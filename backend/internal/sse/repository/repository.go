@@ -25,14 +25,12 @@ func (r *Repository) Add(client *Client) {
 	r.clients[client.ID] = client
 }
 
-// Remove remove um cliente e fecha o canal
+// Remove remove um cliente. O canal não é fechado: um envio em andamento
+// ainda pode ter a referência do cliente (o handler já parou de ler).
 func (r *Repository) Remove(clientID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if c, ok := r.clients[clientID]; ok {
-		close(c.Channel)
-		delete(r.clients, clientID)
-	}
+	delete(r.clients, clientID)
 }
 
 // GetByUserID retorna todos os clientes de um usuário específico
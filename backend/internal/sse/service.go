@@ -1,77 +1,208 @@
 package sse
 
 import (
+	"context"
+	"encoding/json"
 	"log"
+	"time"
 
 	"extension-backend/internal/sse/repository"
 )
 
+// publishTimeout tempo máximo para publicar um evento no broker
+const publishTimeout = 2 * time.Second
+
 // Service gerencia a lógica de envio de eventos SSE
 type Service struct {
-	repo *repository.Repository
+	repo       *repository.Repository
+	broker     Broker
+	store      EventStore
+	metrics    *Metrics
+	evictAfter time.Duration
 }
 
-// NewService cria um novo Service
+// NewService cria um novo Service (buffer de replay em memória)
 func NewService(repo *repository.Repository) *Service {
-	return &Service{repo: repo}
+	return &Service{
+		repo:       repo,
+		store:      NewMemoryStore(),
+		metrics:    &Metrics{},
+		evictAfter: DefaultConfig().EvictAfter,
+	}
+}
+
+// WithStore troca o buffer de replay (ex: RedisStore, compartilhado entre instâncias)
+func (s *Service) WithStore(store EventStore) *Service {
+	s.store = store
+	return s
+}
+
+// WithBroker faz o SendToUser passar pelo broker, alcançando clientes de outras instâncias
+func (s *Service) WithBroker(broker Broker) *Service {
+	s.broker = broker
+	return s
 }
 
-// SendToUser envia um evento para todos os clientes de um usuário específico
+// SendToUser numera o evento, guarda no buffer do usuário (replay via Last-Event-ID)
+// e envia para todos os clientes do usuário, em qualquer instância.
+// Sem broker (ou com o broker fora do ar) entrega só aos clientes desta instância.
 func (s *Service) SendToUser(userID int, event repository.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	stored, err := s.store.Append(ctx, userID, event)
+	cancel()
+	if err != nil {
+		log.Printf("[SSE] Failed to buffer event '%s' for user %d: %v", event.Type, userID, err)
+	} else {
+		event = stored
+	}
+	s.send(userID, event)
+}
+
+// SendTransient envia um evento sem ID e sem buffer (ex: tradução parcial)
+func (s *Service) SendTransient(userID int, event repository.Event) {
+	if !s.reachable() {
+		return
+	}
+	s.send(userID, event)
+}
+
+// Replay retorna os eventos do usuário posteriores a lastID
+func (s *Service) Replay(ctx context.Context, userID int, lastID int64) ([]repository.Event, error) {
+	return s.store.Since(ctx, userID, lastID)
+}
+
+func (s *Service) send(userID int, event repository.Event) {
+	if s.broker != nil {
+		err := s.publish(userID, event)
+		if err == nil {
+			return
+		}
+		log.Printf("[SSE] Broker publish failed for user %d, delivering locally: %v", userID, err)
+	}
+	s.deliverLocal(userID, event)
+}
+
+func (s *Service) publish(userID int, event repository.Event) error {
+	if !s.broker.Ready() {
+		return errBrokerNotReady
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return s.broker.Publish(ctx, userID, data)
+}
+
+// deliverRemote entrega um evento recebido do broker aos clientes locais
+func (s *Service) deliverRemote(userID int, data []byte) {
+	var msg struct {
+		ID      int64           `json:"id"`
+		Type    string          `json:"type"`
+		Version int             `json:"v"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("[SSE] Invalid broker message for user %d: %v", userID, err)
+		return
+	}
+	// Só o canal do usuário chega aqui, mas o usuário pode estar conectado em outra instância
+	if len(s.repo.GetByUserID(userID)) == 0 {
+		return
+	}
+	s.deliverLocal(userID, repository.Event{ID: msg.ID, Type: msg.Type, Version: msg.Version, Payload: msg.Payload})
+}
+
+// reachable indica se vale montar o evento: há clientes locais ou outras instâncias podem ter
+func (s *Service) reachable() bool {
+	return s.broker != nil || s.repo.HasClients()
+}
+
+// deliverLocal envia o evento aos clientes do usuário conectados nesta instância
+func (s *Service) deliverLocal(userID int, event repository.Event) {
 	clients := s.repo.GetByUserID(userID)
 	if len(clients) == 0 {
-		log.Printf("[SSE] No active clients for user %d, skipping event %s", userID, event.Type)
+		if event.ID > 0 {
+			log.Printf("[SSE] No active clients for user %d, event %s %d kept for replay", userID, event.Type, event.ID)
+		}
 		return
 	}
 
 	for _, client := range clients {
-		select {
-		case client.Channel <- event:
-		default:
-			log.Printf("[SSE] Channel full for client %s (user %d), skipping", client.ID, userID)
+		if !s.offer(client, event) {
+			s.metrics.Dropped.Add(1)
+			log.Printf("[SSE] Channel full for client %s (user %d), dropped event '%s'", client.ID, userID, event.Type)
 		}
 	}
 
 	log.Printf("[SSE] Sent event '%s' to %d client(s) of user %d", event.Type, len(clients), userID)
 }
 
-// SendTranslation envia tradução para um usuário específico
-func (s *Service) SendTranslation(userID, phraseID int, traducao, explicacao string, fatias map[string]string, modelo string) {
-	if !s.repo.HasClients() {
-		log.Printf("[SSE] No active clients, skipping translation for phrase %d", phraseID)
+// SendEvent envia um evento registrado. Eventos com Replay no registro são
+// numerados e guardados no buffer; os demais são transitórios.
+func (s *Service) SendEvent(userID int, p repository.Payload) {
+	event, err := repository.NewEvent(p)
+	if err != nil {
+		log.Printf("[SSE] Dropping event for user %d: %v", userID, err)
 		return
 	}
+	if spec, _ := repository.Lookup(event.Type); spec.Replay {
+		s.SendToUser(userID, event)
+		return
+	}
+	s.SendTransient(userID, event)
+}
 
-	s.SendToUser(userID, repository.Event{
-		Type: "translation",
-		Payload: repository.TranslationPayload{
-			PhraseID:         phraseID,
-			TraducaoCompleta: traducao,
-			Explicacao:       explicacao,
-			FatiasTraducoes:  fatias,
-			ModeloIA:         modelo,
-		},
+// SendTranslation envia tradução para um usuário específico.
+// Mesmo sem clientes conectados o evento fica no buffer para o replay.
+func (s *Service) SendTranslation(userID, phraseID int, traducao, explicacao string, fatias map[string]string, modelo string) {
+	s.SendEvent(userID, repository.TranslationPayload{
+		PhraseID:         phraseID,
+		TraducaoCompleta: traducao,
+		Explicacao:       explicacao,
+		FatiasTraducoes:  fatias,
+		ModeloIA:         modelo,
 	})
 }
 
-// SendError envia erro para um usuário específico
-func (s *Service) SendError(userID, phraseID int, errMsg string) {
-	s.SendToUser(userID, repository.Event{
-		Type: "translation_error",
-		Payload: map[string]interface{}{
-			"phrase_id": phraseID,
-			"error":     errMsg,
-		},
+// SendTranslationPartial envia a tradução parcial enquanto o modelo ainda está gerando.
+// Parciais são transitórias: não entram no buffer de replay.
+func (s *Service) SendTranslationPartial(userID, phraseID int, parcial string) {
+	s.SendEvent(userID, repository.TranslationPartialPayload{
+		PhraseID:        phraseID,
+		TraducaoParcial: parcial,
 	})
 }
 
-// BroadcastAll envia evento para todos os clientes (usado apenas para ping)
+// SendError envia erro para um usuário específico; reset pede ao cliente que
+// descarte a tradução parcial (translation_partial) já exibida para a frase
+func (s *Service) SendError(userID, phraseID int, errMsg string, reset bool) {
+	s.SendEvent(userID, repository.TranslationErrorPayload{
+		PhraseID: phraseID,
+		Error:    errMsg,
+		Reset:    reset,
+	})
+}
+
+// BroadcastAll envia evento para todos os clientes (usado apenas para ping).
+// Ping descartado não conta como evento perdido, mas conta para o despejo.
 func (s *Service) BroadcastAll(event repository.Event) {
 	clients := s.repo.GetAll()
 	for _, client := range clients {
-		select {
-		case client.Channel <- event:
-		default:
-		}
+		s.offer(client, event)
+	}
+}
+
+// offer enfileira o evento para o cliente; se o canal continua cheio há mais
+// de evictAfter, o cliente é desconectado. Com cookie o EventSource reconecta
+// sozinho e recebe o replay; com ticket (uso único) o cliente precisa pedir um
+// ticket novo e reabrir com ?last_event_id= (docs/backend/modules/sse.md).
+func (s *Service) offer(client *repository.Client, event repository.Event) bool {
+	ok, late := client.Send(event)
+	if !ok && late >= s.evictAfter && client.Evict() {
+		s.metrics.Evicted.Add(1)
+		log.Printf("[SSE] Evicting slow client %s (user %d): backed up for %s", client.ID, client.UserID, late.Round(time.Millisecond))
 	}
+	return ok
 }
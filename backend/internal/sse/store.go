@@ -0,0 +1,89 @@
+package sse
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"extension-backend/internal/sse/repository"
+)
+
+// Limites do buffer de eventos perdidos
+const (
+	bufferSize = 100              // eventos guardados por usuário
+	bufferTTL  = 10 * time.Minute // idade máxima de um evento para replay
+)
+
+// EventStore numera os eventos e guarda os últimos de cada usuário para replay
+// (Last-Event-ID). IDs são crescentes por usuário.
+type EventStore interface {
+	// Append atribui o ID ao evento e o guarda no buffer do usuário
+	Append(ctx context.Context, userID int, event repository.Event) (repository.Event, error)
+	// Since retorna os eventos do usuário com ID maior que lastID, em ordem
+	Since(ctx context.Context, userID int, lastID int64) ([]repository.Event, error)
+}
+
+type storedEvent struct {
+	event repository.Event
+	at    time.Time
+}
+
+// MemoryStore EventStore em memória (uma instância); ring buffer por usuário
+type MemoryStore struct {
+	seq     atomic.Int64
+	mu      sync.Mutex
+	buffers map[int][]storedEvent
+}
+
+// NewMemoryStore cria um MemoryStore. Os IDs partem do relógio para continuarem
+// crescendo depois de um restart.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{buffers: make(map[int][]storedEvent)}
+	s.seq.Store(time.Now().UnixMilli())
+	return s
+}
+
+// Append atribui o ID e guarda o evento, descartando o mais antigo quando o buffer enche
+func (s *MemoryStore) Append(ctx context.Context, userID int, event repository.Event) (repository.Event, error) {
+	event.ID = s.seq.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := pruneExpired(s.buffers[userID], time.Now())
+	if len(buf) >= bufferSize {
+		buf = buf[len(buf)-bufferSize+1:]
+	}
+	s.buffers[userID] = append(buf, storedEvent{event: event, at: time.Now()})
+	return event, nil
+}
+
+// Since retorna os eventos ainda válidos com ID > lastID
+func (s *MemoryStore) Since(ctx context.Context, userID int, lastID int64) ([]repository.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := pruneExpired(s.buffers[userID], time.Now())
+	if len(buf) == 0 {
+		delete(s.buffers, userID)
+		return nil, nil
+	}
+	s.buffers[userID] = buf
+
+	var result []repository.Event
+	for _, se := range buf {
+		if se.event.ID > lastID {
+			result = append(result, se.event)
+		}
+	}
+	return result, nil
+}
+
+func pruneExpired(buf []storedEvent, now time.Time) []storedEvent {
+	i := 0
+	for i < len(buf) && now.Sub(buf[i].at) > bufferTTL {
+		i++
+	}
+	return buf[i:]
+}
@@ -0,0 +1,78 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+
+	"extension-backend/internal/events"
+	"extension-backend/internal/sse/repository"
+)
+
+// Subscribe converte os eventos de domínio do bus nos eventos SSE tipados do
+// registro (traduções continuam com o processor.Notifier)
+func (s *Service) Subscribe(bus events.Bus) {
+	bus.Subscribe(events.ReviewSubmitted, "sse", func(ctx context.Context, e events.Event) error {
+		var p events.ReviewSubmittedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		if p.Due == nil {
+			return nil
+		}
+		s.SendEvent(e.UserID, repository.AnkiDueChangedV1{
+			Due:            *p.Due,
+			AnkiID:         p.AnkiID,
+			ProximaRevisao: p.ProximaRevisao,
+		})
+		return nil
+	})
+
+	bus.Subscribe(events.ExerciseCompleted, "sse", func(ctx context.Context, e events.Event) error {
+		var p events.ExerciseCompletedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		s.SendEvent(e.UserID, repository.ExerciseCompletedV1{
+			ExercicioID: p.ExercicioID,
+			CatalogoID:  p.CatalogoID,
+			Score:       p.Score,
+			Pontos:      p.Pontos,
+		})
+		return nil
+	})
+
+	bus.Subscribe(events.StreakUpdated, "sse", func(ctx context.Context, e events.Event) error {
+		var p events.StreakUpdatedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		s.SendEvent(e.UserID, repository.StreakUpdatedV1{
+			OfensivaDias:   p.OfensivaDias,
+			MelhorOfensiva: p.MelhorOfensiva,
+		})
+		return nil
+	})
+
+	bus.Subscribe(events.QuotaExceeded, "sse", func(ctx context.Context, e events.Event) error {
+		var p events.QuotaExceededPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		s.SendEvent(e.UserID, repository.QuotaWarningV1{Quota: p.Quota, Mensagem: p.Message})
+		return nil
+	})
+
+	bus.Subscribe(events.GroupChanged, "sse", func(ctx context.Context, e events.Event) error {
+		var p events.GroupChangedPayload
+		if err := e.Decode(&p); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+		s.SendEvent(e.UserID, repository.GroupSharedV1{
+			GrupoID:   p.GrupoID,
+			NomeGrupo: p.NomeGrupo,
+			FraseID:   p.FraseID,
+			Acao:      p.Action,
+		})
+		return nil
+	})
+}
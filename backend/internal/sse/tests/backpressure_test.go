@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"extension-backend/internal/metrics"
+	"extension-backend/internal/sse"
+	"extension-backend/internal/user"
+)
+
+// stuckWriter simula um cliente que parou de ler: depois do evento connected,
+// toda escrita fica presa até release ser fechado
+type stuckWriter struct {
+	header  http.Header
+	writes  atomic.Int32
+	release chan struct{}
+}
+
+func (w *stuckWriter) Header() http.Header { return w.header }
+func (w *stuckWriter) WriteHeader(int)     {}
+func (w *stuckWriter) Flush()              {}
+func (w *stuckWriter) Write(p []byte) (int, error) {
+	if w.writes.Add(1) > 1 {
+		<-w.release
+	}
+	return len(p), nil
+}
+
+func TestHub_EvictsClientThatStaysBackedUp(t *testing.T) {
+	tokens := user.NewTokenService()
+	hub := sse.NewHub(tokens).WithConfig(sse.Config{
+		BufferSize:   1,
+		PingInterval: time.Hour,
+		WriteTimeout: time.Second,
+		EvictAfter:   50 * time.Millisecond,
+	})
+	hub.Run()
+	defer hub.Stop()
+	reg := metrics.NewRegistry()
+	hub.RegisterMetrics(reg)
+	svc := hub.GetService()
+
+	token, _ := tokens.GenerateAccessToken(&user.User{ID: 9})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sse/translations", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	w := &stuckWriter{header: http.Header{}, release: make(chan struct{})}
+
+	done := make(chan struct{})
+	go func() {
+		hub.Handler()(w, req)
+		close(done)
+	}()
+	for hub.ClientCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	svc.SendTranslationPartial(9, 1, "x") // handler preso escrevendo este
+	time.Sleep(20 * time.Millisecond)
+	svc.SendTranslationPartial(9, 1, "x") // ocupa o canal
+	svc.SendTranslationPartial(9, 1, "x") // descartado, começa a contar
+	time.Sleep(60 * time.Millisecond)
+	svc.SendTranslationPartial(9, 1, "x") // descartado, despeja
+
+	close(w.release)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected evicted client handler to return")
+	}
+
+	got := reg.Snapshot()
+	if got["sse_clients_evicted_total"] != 1 {
+		t.Errorf("expected 1 eviction, got %v", got["sse_clients_evicted_total"])
+	}
+	if got["sse_events_dropped_total"] != 2 {
+		t.Errorf("expected 2 dropped events, got %v", got["sse_events_dropped_total"])
+	}
+	if got["sse_events_delivered_total"] < 1 {
+		t.Errorf("expected the in-flight event to be delivered, got %v", got["sse_events_delivered_total"])
+	}
+	if hub.ClientCount() != 0 {
+		t.Errorf("expected evicted client to be removed, %d left", hub.ClientCount())
+	}
+}
+
+func TestConfigFromEnv_IgnoresInvalidValues(t *testing.T) {
+	t.Setenv("SSE_BUFFER_SIZE", "32")
+	t.Setenv("SSE_PING_INTERVAL", "soon")
+	t.Setenv("SSE_EVICT_AFTER", "3s")
+
+	cfg := sse.ConfigFromEnv()
+	def := sse.DefaultConfig()
+	if cfg.BufferSize != 32 || cfg.EvictAfter != 3*time.Second {
+		t.Errorf("expected env values to apply, got %+v", cfg)
+	}
+	if cfg.PingInterval != def.PingInterval {
+		t.Errorf("expected default ping interval for invalid value, got %s", cfg.PingInterval)
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"extension-backend/internal/sse"
+	"extension-backend/internal/sse/repository"
+	"extension-backend/internal/user"
+)
+
+// fakeNet simula o Redis pub/sub entre instâncias
+type fakeNet struct {
+	mu   sync.Mutex
+	subs []func(userID int, data []byte)
+}
+
+type fakeBroker struct {
+	net   *fakeNet
+	ready atomic.Bool
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, userID int, data []byte) error {
+	b.net.mu.Lock()
+	subs := append([]func(int, []byte){}, b.net.subs...)
+	b.net.mu.Unlock()
+	for _, deliver := range subs {
+		deliver(userID, data)
+	}
+	return nil
+}
+
+func (b *fakeBroker) Run(ctx context.Context, deliver func(userID int, data []byte)) {
+	b.net.mu.Lock()
+	b.net.subs = append(b.net.subs, deliver)
+	b.net.mu.Unlock()
+	<-ctx.Done()
+}
+
+func (b *fakeBroker) Ready() bool { return b.ready.Load() }
+
+func newInstance(t *testing.T, tokens *user.TokenService, net *fakeNet, ready bool) (*sse.Hub, *fakeBroker) {
+	t.Helper()
+	broker := &fakeBroker{net: net}
+	broker.ready.Store(ready)
+	hub := sse.NewHub(tokens).WithBroker(broker)
+	hub.Run()
+	t.Cleanup(hub.Stop)
+	return hub, broker
+}
+
+// connect abre uma conexão SSE e devolve as linhas "event: ..." recebidas
+func connect(t *testing.T, hub *sse.Hub, tokens *user.TokenService, userID int) <-chan string {
+	t.Helper()
+	srv := httptest.NewServer(hub.Handler())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() { cancel(); srv.Close() })
+
+	token, err := tokens.GenerateAccessToken(&user.User{ID: userID})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	lines := make(chan string, 10)
+	go func() {
+		defer resp.Body.Close()
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if strings.HasPrefix(sc.Text(), "event: ") {
+				lines <- strings.TrimPrefix(sc.Text(), "event: ")
+			}
+		}
+	}()
+
+	if got := next(t, lines); got != "connected" {
+		t.Fatalf("expected connected event, got %q", got)
+	}
+	return lines
+}
+
+func next(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case l := <-lines:
+		return l
+	case <-time.After(2 * time.Second):
+		return ""
+	}
+}
+
+func TestSendToUser_ReachesClientOnOtherInstance(t *testing.T) {
+	tokens := user.NewTokenService()
+	net := &fakeNet{}
+	hubA, _ := newInstance(t, tokens, net, true)
+	hubB, _ := newInstance(t, tokens, net, true)
+
+	lines := connect(t, hubB, tokens, 42)
+
+	hubA.GetService().SendTranslation(42, 7, "olá", "", nil, "fake")
+
+	if got := next(t, lines); got != "translation" {
+		t.Errorf("expected translation event on instance B, got %q", got)
+	}
+}
+
+func TestSendToUser_FallsBackToLocalWhenBrokerDown(t *testing.T) {
+	tokens := user.NewTokenService()
+	net := &fakeNet{}
+	hubA, _ := newInstance(t, tokens, net, false)
+	hubB, _ := newInstance(t, tokens, net, false)
+
+	remote := connect(t, hubB, tokens, 42)
+	local := connect(t, hubA, tokens, 42)
+
+	hubA.GetService().SendToUser(42, repository.Event{Type: "translation_error", Payload: map[string]any{"phrase_id": 7}})
+
+	if got := next(t, local); got != "translation_error" {
+		t.Errorf("expected local delivery on instance A, got %q", got)
+	}
+	select {
+	case got := <-remote:
+		t.Errorf("expected no delivery on instance B while broker is down, got %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"extension-backend/internal/events"
+	"extension-backend/internal/sse"
+	"extension-backend/internal/sse/repository"
+	"extension-backend/internal/user"
+)
+
+type unknownPayload struct{}
+
+func (unknownPayload) EventType() string { return "unknown" }
+func (unknownPayload) EventVersion() int { return 1 }
+
+type streakV2 struct{}
+
+func (streakV2) EventType() string { return repository.EventStreakUpdated }
+func (streakV2) EventVersion() int { return 2 }
+
+func TestNewEvent_ChecksRegistry(t *testing.T) {
+	e, err := repository.NewEvent(repository.StreakUpdatedV1{OfensivaDias: 3, MelhorOfensiva: 5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if e.Type != "streak_updated" || e.Version != 1 {
+		t.Errorf("expected streak_updated v1, got %s v%d", e.Type, e.Version)
+	}
+
+	if _, err := repository.NewEvent(unknownPayload{}); err == nil {
+		t.Error("expected error for unregistered event")
+	}
+	if _, err := repository.NewEvent(streakV2{}); err == nil {
+		t.Error("expected error for payload version not in registry")
+	}
+}
+
+func TestSubscribe_TurnsDomainEventsIntoTypedSSEEvents(t *testing.T) {
+	tokens := user.NewTokenService()
+	hub := sse.NewHub(tokens)
+	hub.Run()
+	defer hub.Stop()
+	svc := hub.GetService()
+
+	bus := events.NewLocal(events.DefaultRetryPolicy)
+	defer bus.Close()
+	svc.Subscribe(bus)
+
+	lines := connect(t, hub, tokens, 7)
+	ctx := context.Background()
+
+	due := 4
+	cases := []struct {
+		eventType string
+		payload   any
+		want      string
+	}{
+		{events.ReviewSubmitted, events.ReviewSubmittedPayload{AnkiID: 1, Due: &due}, "anki_due_changed"},
+		{events.ExerciseCompleted, events.ExerciseCompletedPayload{ExercicioID: 9, Pontos: 12}, "exercise_completed"},
+		{events.StreakUpdated, events.StreakUpdatedPayload{OfensivaDias: 2, MelhorOfensiva: 2}, "streak_updated"},
+		{events.QuotaExceeded, events.QuotaExceededPayload{Quota: "ai_translation"}, "quota_warning"},
+		{events.GroupChanged, events.GroupChangedPayload{GrupoID: 3, Action: events.GroupPhraseAdded}, "group_shared"},
+	}
+	for _, c := range cases {
+		e, _ := events.New(c.eventType, 7, c.payload)
+		if err := bus.Publish(ctx, e); err != nil {
+			t.Fatalf("publish %s: %v", c.eventType, err)
+		}
+		if got := next(t, lines); got != c.want {
+			t.Errorf("%s: expected SSE event %q, got %q", c.eventType, c.want, got)
+		}
+	}
+
+	// quota_warning é transitório: não volta no replay
+	replayed, _ := svc.Replay(ctx, 7, 0)
+	for _, e := range replayed {
+		if e.Type == repository.EventQuotaWarning {
+			t.Error("expected quota_warning to stay out of the replay buffer")
+		}
+	}
+	if len(replayed) != 4 {
+		t.Errorf("expected 4 replayable events, got %d", len(replayed))
+	}
+}
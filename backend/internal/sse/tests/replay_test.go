@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"extension-backend/internal/sse"
+	"extension-backend/internal/sse/repository"
+	"extension-backend/internal/user"
+)
+
+type received struct {
+	id    int64
+	event string
+}
+
+// stream abre uma conexão SSE enviando Last-Event-ID e devolve os eventos numerados
+func stream(t *testing.T, srv *httptest.Server, tokens *user.TokenService, userID int, lastID int64) (<-chan received, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	token, _ := tokens.GenerateAccessToken(&user.User{ID: userID})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	if lastID > 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(lastID, 10))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	out := make(chan received, 10)
+	connected := make(chan struct{})
+	go func() {
+		defer resp.Body.Close()
+		var id int64
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id, _ = strconv.ParseInt(strings.TrimPrefix(line, "id: "), 10, 64)
+			case line == "event: connected":
+				close(connected)
+			case strings.HasPrefix(line, "event: ") && id > 0:
+				out <- received{id: id, event: strings.TrimPrefix(line, "event: ")}
+				id = 0
+			}
+		}
+	}()
+
+	select {
+	case <-connected:
+	case <-time.After(2 * time.Second):
+		cancel()
+		t.Fatal("timeout waiting for connected event")
+	}
+	return out, cancel
+}
+
+func expect(t *testing.T, ch <-chan received) received {
+	t.Helper()
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	return received{}
+}
+
+func TestReplay_DeliversEventsMissedWhileDisconnected(t *testing.T) {
+	tokens := user.NewTokenService()
+	hub := sse.NewHub(tokens)
+	hub.Run()
+	defer hub.Stop()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+	svc := hub.GetService()
+
+	events, cancel := stream(t, srv, tokens, 42, 0)
+	svc.SendTranslation(42, 1, "um", "", nil, "fake")
+	first := expect(t, events)
+	cancel()
+
+	// Popup fechado: as traduções ficam no buffer (parciais não)
+	time.Sleep(50 * time.Millisecond)
+	svc.SendTranslationPartial(42, 2, "do")
+	svc.SendTranslation(42, 2, "dois", "", nil, "fake")
+	svc.SendError(42, 3, "quota", false)
+
+	events, cancel = stream(t, srv, tokens, 42, first.id)
+	defer cancel()
+
+	second := expect(t, events)
+	third := expect(t, events)
+	if second.event != "translation" || third.event != "translation_error" {
+		t.Errorf("expected translation then translation_error, got %s then %s", second.event, third.event)
+	}
+	if !(first.id < second.id && second.id < third.id) {
+		t.Errorf("expected increasing ids, got %d, %d, %d", first.id, second.id, third.id)
+	}
+}
+
+func TestMemoryStore_KeepsLastEventsPerUser(t *testing.T) {
+	store := sse.NewMemoryStore()
+	ctx := context.Background()
+
+	var ids []int64
+	for i := 0; i < 105; i++ {
+		e, _ := store.Append(ctx, 1, repository.Event{Type: "translation"})
+		ids = append(ids, e.ID)
+	}
+	store.Append(ctx, 2, repository.Event{Type: "translation"})
+
+	all, _ := store.Since(ctx, 1, 0)
+	if len(all) != 100 {
+		t.Fatalf("expected ring buffer of 100, got %d", len(all))
+	}
+	if all[0].ID != ids[5] {
+		t.Errorf("expected oldest events to be dropped, first id %d want %d", all[0].ID, ids[5])
+	}
+
+	after, _ := store.Since(ctx, 1, ids[102])
+	if len(after) != 2 || after[0].ID != ids[103] {
+		t.Errorf("expected 2 events after id %d, got %+v", ids[102], after)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"extension-backend/internal/sse"
+	"extension-backend/internal/user"
+)
+
+func openStream(t *testing.T, url string) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestHandler_RejectsUserIDQueryParam(t *testing.T) {
+	hub := sse.NewHub(user.NewTokenService())
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+
+	if code := openStream(t, srv.URL+"?user_id=1"); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for ?user_id, got %d", code)
+	}
+}
+
+func TestHandler_TicketIsSingleUse(t *testing.T) {
+	tokens := user.NewTokenService()
+	hub := sse.NewHub(tokens)
+	hub.Run()
+	defer hub.Stop()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+
+	ticket, _, err := tokens.GenerateTicket(42, user.TicketAudienceSSE)
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %v", err)
+	}
+
+	// Primeiro uso abre o stream (a conexão é encerrada logo depois)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"?ticket="+ticket, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 on first use, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	if code := openStream(t, srv.URL+"?ticket="+ticket); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 on reuse, got %d", code)
+	}
+}
+
+func TestTicket_AccessTokenIsNotATicket(t *testing.T) {
+	tokens := user.NewTokenService()
+
+	access, _ := tokens.GenerateAccessToken(&user.User{ID: 42})
+	if _, err := tokens.ValidateTicket(access, user.TicketAudienceSSE); err == nil {
+		t.Error("expected access token to be rejected as ticket")
+	}
+
+	ticket, _, _ := tokens.GenerateTicket(42, user.TicketAudienceSSE)
+	if _, err := tokens.ValidateAccessToken(ticket); err == nil {
+		t.Error("expected ticket to be rejected as access token")
+	}
+}
+
+func TestHandler_RejectsConversationTicket(t *testing.T) {
+	tokens := user.NewTokenService()
+	hub := sse.NewHub(tokens)
+	hub.Run()
+	defer hub.Stop()
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+
+	ticket, _, err := tokens.GenerateTicket(42, user.TicketAudienceConversation)
+	if err != nil {
+		t.Fatalf("failed to issue ticket: %v", err)
+	}
+	if code := openStream(t, srv.URL+"?ticket="+ticket); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a conversation ticket, got %d", code)
+	}
+}
@@ -0,0 +1,60 @@
+package sse
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TicketStore garante que cada ticket SSE seja usado uma única vez
+type TicketStore interface {
+	// Consume marca o ticket como usado; false se já tinha sido usado
+	Consume(ctx context.Context, ticketID string, ttl time.Duration) (bool, error)
+}
+
+// MemoryTicketStore TicketStore em memória (uma instância)
+type MemoryTicketStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time // ticketID -> expiração
+}
+
+// NewMemoryTicketStore cria um MemoryTicketStore
+func NewMemoryTicketStore() *MemoryTicketStore {
+	return &MemoryTicketStore{used: make(map[string]time.Time)}
+}
+
+// Consume marca o ticket como usado até ele expirar
+func (s *MemoryTicketStore) Consume(ctx context.Context, ticketID string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range s.used {
+		if now.After(exp) {
+			delete(s.used, id)
+		}
+	}
+
+	if _, ok := s.used[ticketID]; ok {
+		return false, nil
+	}
+	s.used[ticketID] = now.Add(ttl)
+	return true, nil
+}
+
+// RedisTicketStore TicketStore compartilhado entre instâncias (SETNX com TTL)
+type RedisTicketStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisTicketStore cria um RedisTicketStore
+func NewRedisTicketStore(rdb *redis.Client) *RedisTicketStore {
+	return &RedisTicketStore{rdb: rdb}
+}
+
+// Consume marca o ticket como usado; a chave expira junto com o ticket
+func (s *RedisTicketStore) Consume(ctx context.Context, ticketID string, ttl time.Duration) (bool, error) {
+	return s.rdb.SetNX(ctx, "sse:ticket:"+ticketID, 1, ttl).Result()
+}
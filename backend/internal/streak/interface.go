@@ -0,0 +1,9 @@
+package streak
+
+import "context"
+
+// RepositoryInterface define o acesso à ofensiva em usuario_estatisticas
+type RepositoryInterface interface {
+	// Touch conta o dia de hoje na ofensiva. changed é false quando hoje já tinha sido contado.
+	Touch(ctx context.Context, userID int) (ofensiva *Ofensiva, changed bool, err error)
+}
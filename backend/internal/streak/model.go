@@ -0,0 +1,7 @@
+package streak
+
+// Ofensiva dias seguidos com ao menos uma atividade (revisão ou exercício)
+type Ofensiva struct {
+	Dias   int `json:"ofensiva_dias"`
+	Melhor int `json:"melhor_ofensiva"`
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"extension-backend/internal/streak"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX define an interface for database transactions.
+type DBTX interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type Repository struct {
+	db DBTX
+}
+
+func New(db DBTX) *Repository {
+	return &Repository{db: db}
+}
+
+// Touch soma um dia à ofensiva se a última atividade foi ontem, recomeça em 1
+// se foi antes disso e não faz nada se hoje já foi contado. Cria a linha de
+// usuario_estatisticas na primeira atividade.
+func (r *Repository) Touch(ctx context.Context, userID int) (*streak.Ofensiva, bool, error) {
+	var o streak.Ofensiva
+	err := r.db.QueryRow(ctx, `
+		UPDATE usuario_estatisticas
+		SET ofensiva_dias = CASE WHEN ofensiva_data = CURRENT_DATE - 1 THEN COALESCE(ofensiva_dias, 0) + 1 ELSE 1 END,
+		    melhor_ofensiva = GREATEST(COALESCE(melhor_ofensiva, 0),
+		        CASE WHEN ofensiva_data = CURRENT_DATE - 1 THEN COALESCE(ofensiva_dias, 0) + 1 ELSE 1 END),
+		    ofensiva_data = CURRENT_DATE,
+		    atualizado_em = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1 AND ofensiva_data IS DISTINCT FROM CURRENT_DATE
+		RETURNING ofensiva_dias, melhor_ofensiva
+	`, userID).Scan(&o.Dias, &o.Melhor)
+	if err == nil {
+		return &o, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	// Nenhuma linha: hoje já contado ou usuário sem estatísticas ainda
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO usuario_estatisticas (usuario_id, ofensiva_dias, melhor_ofensiva, ofensiva_data)
+		SELECT $1, 1, 1, CURRENT_DATE
+		WHERE NOT EXISTS (SELECT 1 FROM usuario_estatisticas WHERE usuario_id = $1)
+		ON CONFLICT (usuario_id) DO NOTHING
+	`, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, false, nil
+	}
+	return &streak.Ofensiva{Dias: 1, Melhor: 1}, true, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"extension-backend/internal/events"
+	"extension-backend/internal/streak"
+)
+
+type Service struct {
+	repo   streak.RepositoryInterface
+	events events.Publisher
+}
+
+func New(repo streak.RepositoryInterface) *Service {
+	return &Service{repo: repo}
+}
+
+// WithEvents publica StreakUpdated quando a ofensiva muda
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.events = publisher
+	return s
+}
+
+// Record conta a atividade de hoje na ofensiva do usuário
+func (s *Service) Record(ctx context.Context, userID int) error {
+	ofensiva, changed, err := s.repo.Touch(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update streak for user %d: %w", userID, err)
+	}
+	if !changed || s.events == nil {
+		return nil
+	}
+
+	e, err := events.New(events.StreakUpdated, userID, events.StreakUpdatedPayload{
+		OfensivaDias:   ofensiva.Dias,
+		MelhorOfensiva: ofensiva.Melhor,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		log.Printf("[Streak] Failed to publish %s for user %d: %v", events.StreakUpdated, userID, err)
+	}
+	return nil
+}
+
+// Subscribe conta revisões do Anki e exercícios concluídos como atividade do dia
+func (s *Service) Subscribe(bus events.Bus) {
+	handler := func(ctx context.Context, e events.Event) error {
+		return s.Record(ctx, e.UserID)
+	}
+	bus.Subscribe(events.ReviewSubmitted, "streak", handler)
+	bus.Subscribe(events.ExerciseCompleted, "streak", handler)
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"extension-backend/internal/events"
+	"extension-backend/internal/streak/repository"
+	"extension-backend/internal/streak/service"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+type recorder struct {
+	published []events.Event
+}
+
+func (r *recorder) Publish(ctx context.Context, e events.Event) error {
+	r.published = append(r.published, e)
+	return nil
+}
+
+func TestRecord_PublishesNewStreak(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	rec := &recorder{}
+	svc := service.New(repository.New(mock)).WithEvents(rec)
+
+	mock.ExpectQuery("UPDATE usuario_estatisticas SET ofensiva_dias").
+		WithArgs(5).
+		WillReturnRows(pgxmock.NewRows([]string{"ofensiva_dias", "melhor_ofensiva"}).AddRow(3, 7))
+
+	if err := svc.Record(context.Background(), 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.published) != 1 || rec.published[0].Type != events.StreakUpdated {
+		t.Fatalf("expected one %s event, got %+v", events.StreakUpdated, rec.published)
+	}
+	var p events.StreakUpdatedPayload
+	rec.published[0].Decode(&p)
+	if p.OfensivaDias != 3 || p.MelhorOfensiva != 7 || rec.published[0].UserID != 5 {
+		t.Errorf("unexpected payload %+v for user %d", p, rec.published[0].UserID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRecord_SameDayDoesNotPublish(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mock.Close()
+
+	rec := &recorder{}
+	svc := service.New(repository.New(mock)).WithEvents(rec)
+
+	mock.ExpectQuery("UPDATE usuario_estatisticas SET ofensiva_dias").
+		WithArgs(5).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectExec("INSERT INTO usuario_estatisticas").
+		WithArgs(5).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	if err := svc.Record(context.Background(), 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.published) != 0 {
+		t.Errorf("expected no event when today was already counted, got %d", len(rec.published))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
@@ -1,6 +1,10 @@
 package user
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"time"
@@ -10,6 +14,22 @@ import (
 
 type TokenService struct {
 	secretKey []byte
+	ticketKey []byte // chave própria para tickets: access token não vale como ticket e vice-versa
+}
+
+// TicketTTL validade de um ticket
+const TicketTTL = 30 * time.Second
+
+// Finalidades (audience) dos tickets: um ticket só abre o endpoint para o qual foi emitido
+const (
+	TicketAudienceSSE          = "sse"          // stream de eventos (somente leitura)
+	TicketAudienceConversation = "conversation" // WebSocket de voz (gasta cota de STT/LLM/TTS)
+)
+
+// TicketClaims ticket de uso único para clientes que não enviam headers (ID = nonce, Audience = finalidade)
+type TicketClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
 }
 
 type TokenClaims struct {
@@ -25,7 +45,9 @@ func NewTokenService() *TokenService {
 	if secret == "" {
 		secret = "default-secret-change-in-production"
 	}
-	return &TokenService{secretKey: []byte(secret)}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("sse-ticket"))
+	return &TokenService{secretKey: []byte(secret), ticketKey: mac.Sum(nil)}
 }
 
 func (s *TokenService) GenerateAccessToken(u *User) (string, error) {
@@ -77,3 +99,47 @@ func (s *TokenService) ValidateAccessToken(tokenString string) (*TokenClaims, er
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// GenerateTicket emite um ticket assinado de 30s para a finalidade informada, para clientes
+// que não enviam cookie (ex: service worker da extensão, WebSocket do browser).
+// O uso único é garantido pelo TicketStore do hub SSE via o ID.
+func (s *TokenService) GenerateTicket(userID int, audience string) (string, time.Time, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(TicketTTL)
+	claims := TicketClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(nonce),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(s.ticketKey)
+	return signed, expiresAt, err
+}
+
+// ValidateTicket valida assinatura, validade e finalidade do ticket (não marca como usado).
+// Ticket emitido para outra finalidade é rejeitado.
+func (s *TokenService) ValidateTicket(ticket, audience string) (*TicketClaims, error) {
+	token, err := jwt.ParseWithClaims(ticket, &TicketClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return s.ticketKey, nil
+	}, jwt.WithAudience(audience))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*TicketClaims); ok && token.Valid && claims.ID != "" {
+		return claims, nil
+	}
+	return nil, fmt.Errorf("invalid ticket")
+}
@@ -0,0 +1,59 @@
+package vocabulary
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLemaLength limite da coluna vocabulario.lema
+const maxLemaLength = 100
+
+// Extract gera as entradas de vocabulário de uma frase.
+// As chaves de fatias_traducoes com mais de uma palavra viram expressões
+// e emprestam a tradução para os lemas; o resto vem do tokenizador.
+func Extract(conteudo, idioma string, fatias map[string]string) []Entrada {
+	entradas := make(map[string]*Entrada)
+	add := func(lema, forma, traducao string, expressao bool) {
+		if lema == "" || utf8.RuneCountInString(lema) > maxLemaLength {
+			return
+		}
+		e, ok := entradas[lema]
+		if !ok {
+			e = &Entrada{Lema: lema, Forma: forma, Expressao: expressao}
+			entradas[lema] = e
+		}
+		e.Ocorrencias++
+		if e.Traducao == "" {
+			e.Traducao = traducao
+		}
+	}
+
+	// Traduções por palavra vindas das fatias com uma só palavra
+	traducoes := make(map[string]string)
+	for chave, traducao := range fatias {
+		tokens := Tokenize(chave, idioma)
+		switch {
+		case len(tokens) == 1:
+			traducoes[tokens[0].Lema] = strings.TrimSpace(traducao)
+		case len(tokens) > 1:
+			lema := LemmatizeExpression(chave, idioma)
+			// Conta quantas vezes a expressão aparece na frase (mínimo 1)
+			n := max(1, strings.Count(strings.ToLower(conteudo), strings.ToLower(strings.TrimSpace(chave))))
+			for range n {
+				add(lema, strings.ToLower(strings.TrimSpace(chave)), strings.TrimSpace(traducao), true)
+			}
+		}
+	}
+
+	for _, t := range Tokenize(conteudo, idioma) {
+		add(t.Lema, t.Forma, traducoes[t.Lema], false)
+	}
+
+	out := make([]Entrada, 0, len(entradas))
+	for _, e := range entradas {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Lema < out[j].Lema })
+	return out
+}
@@ -0,0 +1,19 @@
+package vocabulary
+
+import "context"
+
+// RepositoryInterface define as operações de acesso a dados do vocabulário
+type RepositoryInterface interface {
+	GetFrase(ctx context.Context, fraseID int) (*FraseIndexavel, error)
+	ListFraseIDs(ctx context.Context, userID int) ([]int, error)
+	SaveEntradas(ctx context.Context, frase *FraseIndexavel, entradas []Entrada) error
+	List(ctx context.Context, userID int, params ListParams) ([]Palavra, error)
+	Resumo(ctx context.Context, userID int, idioma string) (*Resumo, error)
+}
+
+// ServiceInterface define a lógica de negócio do vocabulário
+type ServiceInterface interface {
+	IndexPhrase(ctx context.Context, fraseID int) error
+	Rebuild(ctx context.Context, userID int) (int, error)
+	List(ctx context.Context, userID int, params ListParams) (*ListResult, error)
+}
@@ -0,0 +1,277 @@
+package vocabulary
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token uma palavra encontrada no texto
+type Token struct {
+	Forma string // como apareceu (minúsculo)
+	Lema  string
+}
+
+// Tokenize divide o texto em palavras e calcula o lema de cada uma.
+// Números e pontuação são descartados.
+func Tokenize(text, idioma string) []Token {
+	idioma = baseIdioma(idioma)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’' && r != '-'
+	})
+
+	tokens := make([]Token, 0, len(words))
+	for _, w := range words {
+		w = strings.Trim(strings.ReplaceAll(w, "’", "'"), "'-")
+		if w == "" || !strings.ContainsFunc(w, unicode.IsLetter) {
+			continue
+		}
+		for _, part := range splitClitics(w, idioma) {
+			tokens = append(tokens, Token{Forma: part, Lema: Lemmatize(part, idioma)})
+		}
+	}
+	return tokens
+}
+
+// Lemmatize reduz uma palavra à forma de dicionário com regras simples por idioma.
+// Idiomas sem regras devolvem a palavra em minúsculo.
+func Lemmatize(word, idioma string) string {
+	w := strings.ToLower(strings.TrimSpace(word))
+	if w == "" {
+		return w
+	}
+
+	switch baseIdioma(idioma) {
+	case "en":
+		return lemmaEN(w)
+	case "pt":
+		return lemmaPT(w)
+	case "es":
+		return lemmaES(w)
+	}
+	return w
+}
+
+// LemmatizeExpression aplica Lemmatize a cada palavra de uma expressão
+func LemmatizeExpression(expr, idioma string) string {
+	tokens := Tokenize(expr, idioma)
+	lemas := make([]string, len(tokens))
+	for i, t := range tokens {
+		lemas[i] = t.Lema
+	}
+	return strings.Join(lemas, " ")
+}
+
+// baseIdioma "pt-BR" -> "pt"
+func baseIdioma(idioma string) string {
+	idioma = strings.ToLower(strings.TrimSpace(idioma))
+	if i := strings.IndexAny(idioma, "-_"); i > 0 {
+		idioma = idioma[:i]
+	}
+	return idioma
+}
+
+// splitClitics separa contrações do inglês ("don't" -> "do", "not")
+func splitClitics(w, idioma string) []string {
+	if idioma != "en" || !strings.Contains(w, "'") {
+		return []string{w}
+	}
+
+	if base, ok := strings.CutSuffix(w, "n't"); ok {
+		switch base {
+		case "ca":
+			base = "can"
+		case "wo":
+			base = "will"
+		case "sha":
+			base = "shall"
+		}
+		return []string{base, "not"}
+	}
+
+	i := strings.LastIndex(w, "'")
+	base, suffix := w[:i], w[i+1:]
+	switch suffix {
+	case "s", "re", "ve", "ll", "d", "m":
+		if base == "" {
+			return nil
+		}
+		return []string{base}
+	}
+	return []string{w}
+}
+
+// ── Inglês ─────────────────────────────────────────────────────
+
+var irregularEN = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do",
+	"went": "go", "gone": "go", "goes": "go",
+	"said": "say", "says": "say", "made": "make", "took": "take", "taken": "take",
+	"came": "come", "saw": "see", "seen": "see", "got": "get", "gotten": "get",
+	"knew": "know", "known": "know", "thought": "think", "told": "tell", "found": "find",
+	"gave": "give", "given": "give", "felt": "feel", "left": "leave", "kept": "keep",
+	"ran": "run", "wrote": "write", "written": "write", "ate": "eat", "eaten": "eat",
+	"bought": "buy", "brought": "bring", "began": "begin", "begun": "begin",
+	"spoke": "speak", "spoken": "speak", "met": "meet", "paid": "pay", "sat": "sit",
+	"stood": "stand", "understood": "understand", "heard": "hear", "held": "hold",
+	"meant": "mean", "sent": "send", "spent": "spend", "built": "build", "lost": "lose",
+	"sold": "sell", "taught": "teach", "caught": "catch", "fought": "fight",
+	"broke": "break", "broken": "break", "chose": "choose", "chosen": "choose",
+	"drove": "drive", "driven": "drive", "fell": "fall", "fallen": "fall",
+	"flew": "fly", "flown": "fly", "forgot": "forget", "forgotten": "forget",
+	"grew": "grow", "grown": "grow", "led": "lead", "slept": "sleep", "won": "win",
+	"better": "good", "best": "good", "worse": "bad", "worst": "bad",
+	"children": "child", "men": "man", "women": "woman", "people": "person",
+	"mice": "mouse", "feet": "foot", "teeth": "tooth", "geese": "goose",
+	"i": "i", "me": "i", "my": "i", "him": "he", "his": "he", "her": "she",
+	"us": "we", "our": "we", "them": "they", "their": "they",
+	"this": "this", "yes": "yes", "news": "news", "writing": "write", "always": "always", "perhaps": "perhaps",
+}
+
+// restoreE endings cujo radical costuma terminar em "e" ("making" -> "make")
+var restoreE = []string{"at", "iz", "bl", "v", "ak", "ok", "ik", "ag", "id", "ud", "ur", "ar", "im", "in", "ut", "ot", "os"}
+
+func lemmaEN(w string) string {
+	if l, ok := irregularEN[w]; ok {
+		return l
+	}
+	n := utf8.RuneCountInString(w)
+
+	switch {
+	case n > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case n > 4 && strings.HasSuffix(w, "ied"):
+		return w[:len(w)-3] + "y"
+	case n > 4 && strings.HasSuffix(w, "sses"):
+		return w[:len(w)-2]
+	case n > 4 && (strings.HasSuffix(w, "ches") || strings.HasSuffix(w, "shes") ||
+		strings.HasSuffix(w, "xes") || strings.HasSuffix(w, "zes")):
+		return w[:len(w)-2]
+	case n > 5 && strings.HasSuffix(w, "ing"):
+		return fixStemEN(w[:len(w)-3])
+	case n > 4 && strings.HasSuffix(w, "ed"):
+		return fixStemEN(w[:len(w)-2])
+	case n > 3 && strings.HasSuffix(w, "s") &&
+		!strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+// fixStemEN desfaz consoante dobrada ("stopp" -> "stop") e devolve o "e" final ("lov" -> "love")
+func fixStemEN(stem string) string {
+	n := len(stem)
+	if n >= 3 && stem[n-1] == stem[n-2] && !isVowel(rune(stem[n-1])) &&
+		stem[n-1] != 'l' && stem[n-1] != 's' && stem[n-1] != 'z' {
+		return stem[:n-1]
+	}
+	if n >= 3 && isVowel(rune(stem[n-2])) && !isVowel(rune(stem[n-1])) && !isVowel(rune(stem[n-3])) {
+		for _, end := range restoreE {
+			if strings.HasSuffix(stem, end) {
+				return stem + "e"
+			}
+		}
+	}
+	if strings.HasSuffix(stem, "v") || strings.HasSuffix(stem, "at") || strings.HasSuffix(stem, "iz") {
+		return stem + "e"
+	}
+	return stem
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
+
+// ── Português ──────────────────────────────────────────────────
+
+var irregularPT = map[string]string{
+	"é": "ser", "são": "ser", "era": "ser", "eram": "ser", "sou": "ser", "somos": "ser", "foi": "ser", "foram": "ser",
+	"está": "estar", "estão": "estar", "estou": "estar", "estava": "estar", "estavam": "estar",
+	"tem": "ter", "têm": "ter", "tenho": "ter", "tinha": "ter", "teve": "ter",
+	"vai": "ir", "vão": "ir", "vou": "ir", "ia": "ir",
+	"faz": "fazer", "fez": "fazer", "feito": "fazer", "faço": "fazer",
+	"pode": "poder", "posso": "poder", "pôde": "poder",
+	"há": "haver", "houve": "haver",
+	"as": "a", "os": "o", "das": "da", "dos": "do", "nas": "na", "nos": "no",
+	"pelas": "pela", "pelos": "pelo", "mais": "mais", "depois": "depois", "português": "português",
+}
+
+func lemmaPT(w string) string {
+	if l, ok := irregularPT[w]; ok {
+		return l
+	}
+	n := utf8.RuneCountInString(w)
+	if n <= 3 {
+		return w
+	}
+
+	switch {
+	case strings.HasSuffix(w, "ões"), strings.HasSuffix(w, "ães"):
+		return strings.TrimSuffix(strings.TrimSuffix(w, "ões"), "ães") + "ão"
+	case strings.HasSuffix(w, "ais"):
+		return w[:len(w)-len("ais")] + "al"
+	case strings.HasSuffix(w, "éis"):
+		return w[:len(w)-len("éis")] + "el"
+	case strings.HasSuffix(w, "óis"):
+		return w[:len(w)-len("óis")] + "ol"
+	case strings.HasSuffix(w, "ns"):
+		return w[:len(w)-2] + "m"
+	case strings.HasSuffix(w, "res"), strings.HasSuffix(w, "zes"), strings.HasSuffix(w, "ses"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "s"):
+		r, _ := utf8.DecodeLastRuneInString(w[:len(w)-1])
+		if isVowelLatin(r) {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+// ── Espanhol ───────────────────────────────────────────────────
+
+var irregularES = map[string]string{
+	"es": "ser", "son": "ser", "era": "ser", "eran": "ser", "soy": "ser", "somos": "ser", "fue": "ser", "fueron": "ser",
+	"está": "estar", "están": "estar", "estoy": "estar", "estaba": "estar",
+	"tiene": "tener", "tienen": "tener", "tengo": "tener", "tenía": "tener", "tuvo": "tener",
+	"va": "ir", "van": "ir", "voy": "ir", "iba": "ir",
+	"hace": "hacer", "hizo": "hacer", "hago": "hacer", "hecho": "hacer",
+	"puede": "poder", "puedo": "poder", "pudo": "poder",
+	"hay": "haber", "ha": "haber", "han": "haber", "he": "haber", "había": "haber",
+	"las": "la", "los": "el", "unas": "una", "unos": "uno", "más": "más", "lunes": "lunes",
+}
+
+func lemmaES(w string) string {
+	if l, ok := irregularES[w]; ok {
+		return l
+	}
+	n := utf8.RuneCountInString(w)
+	if n <= 3 {
+		return w
+	}
+
+	switch {
+	case strings.HasSuffix(w, "ces"):
+		return w[:len(w)-3] + "z"
+	case strings.HasSuffix(w, "iones"):
+		return w[:len(w)-len("iones")] + "ión"
+	case strings.HasSuffix(w, "es"):
+		r, _ := utf8.DecodeLastRuneInString(w[:len(w)-2])
+		if strings.ContainsRune("lrndjy", r) {
+			return w[:len(w)-2]
+		}
+		return w[:len(w)-1]
+	case strings.HasSuffix(w, "s"):
+		r, _ := utf8.DecodeLastRuneInString(w[:len(w)-1])
+		if isVowelLatin(r) {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+func isVowelLatin(r rune) bool {
+	return strings.ContainsRune("aeiouáéíóúâêôãõà", r)
+}
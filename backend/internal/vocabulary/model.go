@@ -0,0 +1,74 @@
+package vocabulary
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidStatus status desconhecido no filtro do GET /vocabulary
+var ErrInvalidStatus = errors.New("invalid status")
+
+// Status de uma palavra calculado a partir do anki_progresso das frases ligadas
+const (
+	StatusKnown    = "known"    // algum card maduro (revisao com intervalo >= 21 dias)
+	StatusLearning = "learning" // há card em estudo, ainda não maduro
+	StatusNew      = "new"      // nenhuma frase com a palavra está no Anki
+)
+
+// IntervaloMaduro intervalo (dias) a partir do qual um card conta como conhecido
+const IntervaloMaduro = 21
+
+// Entrada lema extraído de uma frase
+type Entrada struct {
+	Lema        string
+	Forma       string // primeira forma encontrada na frase
+	Traducao    string
+	Expressao   bool
+	Ocorrencias int
+}
+
+// FraseIndexavel frase com os dados necessários para extrair vocabulário
+type FraseIndexavel struct {
+	ID          int
+	UsuarioID   int
+	Conteudo    string
+	Idioma      string
+	CapturadoEm time.Time
+	Fatias      map[string]string
+}
+
+// Palavra item do vocabulário do usuário (GET /vocabulary)
+type Palavra struct {
+	ID            int       `json:"id"`
+	Lema          string    `json:"lema"`
+	Idioma        string    `json:"idioma"`
+	Expressao     bool      `json:"expressao"`
+	Traducao      string    `json:"traducao,omitempty"`
+	Frequencia    int       `json:"frequencia"`
+	Frases        int       `json:"frases"`
+	Status        string    `json:"status"`
+	PrimeiraVezEm time.Time `json:"primeira_vez_em"`
+	UltimaVezEm   time.Time `json:"ultima_vez_em"`
+}
+
+// Resumo contagem de palavras por status
+type Resumo struct {
+	Total    int `json:"total"`
+	Known    int `json:"known"`
+	Learning int `json:"learning"`
+	New      int `json:"new"`
+}
+
+// ListParams filtros do GET /vocabulary
+type ListParams struct {
+	Idioma string
+	Status string
+	Limit  int
+	Offset int
+}
+
+// ListResult resposta do GET /vocabulary
+type ListResult struct {
+	Palavras []Palavra `json:"palavras"`
+	Resumo   Resumo    `json:"resumo"`
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"extension-backend/internal/vocabulary"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX define an interface for database transactions.
+type DBTX interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type Repository struct {
+	db DBTX
+}
+
+func New(db DBTX) *Repository {
+	return &Repository{db: db}
+}
+
+// GetFrase busca a frase com as fatias da tradução (se já traduzida)
+func (r *Repository) GetFrase(ctx context.Context, fraseID int) (*vocabulary.FraseIndexavel, error) {
+	query := `
+		SELECT f.id, f.usuario_id, f.conteudo, COALESCE(f.idioma_origem, 'en'), f.capturado_em, fd.fatias_traducoes
+		FROM frases f
+		LEFT JOIN frase_detalhes fd ON fd.frase_id = f.id
+		WHERE f.id = $1
+	`
+
+	var f vocabulary.FraseIndexavel
+	var fatiasJSON []byte
+	err := r.db.QueryRow(ctx, query, fraseID).Scan(
+		&f.ID, &f.UsuarioID, &f.Conteudo, &f.Idioma, &f.CapturadoEm, &fatiasJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if fatiasJSON != nil {
+		json.Unmarshal(fatiasJSON, &f.Fatias)
+	}
+	return &f, nil
+}
+
+// ListFraseIDs retorna os IDs das frases do usuário (para reconstruir o vocabulário)
+func (r *Repository) ListFraseIDs(ctx context.Context, userID int) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT id FROM frases WHERE usuario_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SaveEntradas substitui os lemas ligados à frase e recalcula a frequência
+// de todas as palavras afetadas. Reindexar a mesma frase não duplica contagens.
+// Tudo numa transação que trava a frase: duas indexações simultâneas da mesma
+// frase (retry do bus e /vocabulary/rebuild) rodam uma depois da outra.
+func (r *Repository) SaveEntradas(ctx context.Context, frase *vocabulary.FraseIndexavel, entradas []vocabulary.Entrada) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := saveEntradas(ctx, tx, frase, entradas); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func saveEntradas(ctx context.Context, tx pgx.Tx, frase *vocabulary.FraseIndexavel, entradas []vocabulary.Entrada) error {
+	var id int
+	if err := tx.QueryRow(ctx, `SELECT id FROM frases WHERE id = $1 FOR UPDATE`, frase.ID).Scan(&id); err != nil {
+		return fmt.Errorf("failed to lock phrase %d: %w", frase.ID, err)
+	}
+
+	afetados := make(map[int]struct{})
+	rows, err := tx.Query(ctx, `DELETE FROM vocabulario_frases WHERE frase_id = $1 RETURNING vocabulario_id`, frase.ID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink phrase %d: %w", frase.ID, err)
+	}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		afetados[id] = struct{}{}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(entradas) > 0 {
+		query := `
+			WITH v AS (
+				INSERT INTO vocabulario (usuario_id, idioma, lema, expressao, traducao, primeira_vez_em, ultima_vez_em)
+				VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $6)
+				ON CONFLICT (usuario_id, idioma, lema) DO UPDATE SET
+					traducao = COALESCE(vocabulario.traducao, EXCLUDED.traducao),
+					primeira_vez_em = LEAST(vocabulario.primeira_vez_em, EXCLUDED.primeira_vez_em),
+					ultima_vez_em = GREATEST(vocabulario.ultima_vez_em, EXCLUDED.ultima_vez_em)
+				RETURNING id
+			)
+			INSERT INTO vocabulario_frases (vocabulario_id, frase_id, forma, ocorrencias)
+			SELECT id, $7, $8, $9 FROM v
+			RETURNING vocabulario_id
+		`
+
+		batch := &pgx.Batch{}
+		for _, e := range entradas {
+			batch.Queue(query,
+				frase.UsuarioID, frase.Idioma, e.Lema, e.Expressao, e.Traducao, frase.CapturadoEm,
+				frase.ID, e.Forma, e.Ocorrencias,
+			)
+		}
+
+		br := tx.SendBatch(ctx, batch)
+		for range entradas {
+			var id int
+			if err := br.QueryRow().Scan(&id); err != nil {
+				br.Close()
+				return fmt.Errorf("failed to save vocabulary for phrase %d: %w", frase.ID, err)
+			}
+			afetados[id] = struct{}{}
+		}
+		if err := br.Close(); err != nil {
+			return err
+		}
+	}
+
+	if len(afetados) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(afetados))
+	for id := range afetados {
+		ids = append(ids, id)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE vocabulario v
+		SET frequencia = COALESCE((
+			SELECT SUM(vf.ocorrencias) FROM vocabulario_frases vf WHERE vf.vocabulario_id = v.id
+		), 0)
+		WHERE v.id = ANY($1)
+	`, ids)
+	return err
+}
+
+// statusQuery calcula o status de cada palavra pelo melhor card do Anki entre as frases ligadas.
+// A frequência é somada das ligações atuais: apagar uma frase remove as ligações em
+// cascata sem passar pelo SaveEntradas, então a coluna frequencia pode estar velha.
+const statusQuery = `
+	SELECT v.id, v.lema, v.idioma, v.expressao, COALESCE(v.traducao, ''),
+	       (SELECT COALESCE(SUM(l.ocorrencias), 0) FROM vocabulario_frases l WHERE l.vocabulario_id = v.id) AS frequencia,
+	       COUNT(DISTINCT vf.frase_id) AS frases,
+	       CASE
+	           WHEN COUNT(ap.id) FILTER (WHERE ap.estado = 'revisao' AND ap.intervalo >= $2) > 0 THEN 'known'
+	           WHEN COUNT(ap.id) FILTER (WHERE ap.estado != 'suspenso') > 0 THEN 'learning'
+	           ELSE 'new'
+	       END AS status,
+	       v.primeira_vez_em, v.ultima_vez_em
+	FROM vocabulario v
+	JOIN vocabulario_frases vf ON vf.vocabulario_id = v.id
+	LEFT JOIN anki_progresso ap ON ap.frase_id = vf.frase_id AND ap.usuario_id = v.usuario_id
+	WHERE v.usuario_id = $1
+	  AND ($3 = '' OR v.idioma = $3)
+	GROUP BY v.id
+`
+
+// List retorna as palavras do usuário com status calculado, mais frequentes primeiro
+func (r *Repository) List(ctx context.Context, userID int, params vocabulary.ListParams) ([]vocabulary.Palavra, error) {
+	query := `
+		SELECT id, lema, idioma, expressao, traducao, frequencia, frases, status, primeira_vez_em, ultima_vez_em
+		FROM (` + statusQuery + `) s
+		WHERE ($4 = '' OR s.status = $4)
+		ORDER BY s.frequencia DESC, s.lema ASC
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.Query(ctx, query,
+		userID, vocabulary.IntervaloMaduro, params.Idioma, params.Status, params.Limit, params.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	palavras := []vocabulary.Palavra{}
+	for rows.Next() {
+		var p vocabulary.Palavra
+		if err := rows.Scan(
+			&p.ID, &p.Lema, &p.Idioma, &p.Expressao, &p.Traducao, &p.Frequencia,
+			&p.Frases, &p.Status, &p.PrimeiraVezEm, &p.UltimaVezEm,
+		); err != nil {
+			return nil, err
+		}
+		palavras = append(palavras, p)
+	}
+	return palavras, rows.Err()
+}
+
+// Resumo conta as palavras do usuário por status
+func (r *Repository) Resumo(ctx context.Context, userID int, idioma string) (*vocabulary.Resumo, error) {
+	query := `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'known'),
+		       COUNT(*) FILTER (WHERE status = 'learning'),
+		       COUNT(*) FILTER (WHERE status = 'new')
+		FROM (` + statusQuery + `) s
+	`
+
+	var res vocabulary.Resumo
+	err := r.db.QueryRow(ctx, query, userID, vocabulary.IntervaloMaduro, idioma).Scan(
+		&res.Total, &res.Known, &res.Learning, &res.New,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
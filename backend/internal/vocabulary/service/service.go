@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"extension-backend/internal/vocabulary"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type Service struct {
+	repo vocabulary.RepositoryInterface
+}
+
+func New(repo vocabulary.RepositoryInterface) *Service {
+	return &Service{repo: repo}
+}
+
+// IndexPhrase extrai os lemas de uma frase e atualiza o vocabulário do dono. Roda na
+// criação (sem tradução) e de novo quando a tradução chega, com as fatias traduzidas.
+func (s *Service) IndexPhrase(ctx context.Context, fraseID int) error {
+	frase, err := s.repo.GetFrase(ctx, fraseID)
+	if err != nil {
+		return fmt.Errorf("phrase %d not found: %w", fraseID, err)
+	}
+
+	entradas := vocabulary.Extract(frase.Conteudo, frase.Idioma, frase.Fatias)
+	if err := s.repo.SaveEntradas(ctx, frase, entradas); err != nil {
+		return err
+	}
+
+	log.Printf("[Vocabulary] Phrase %d indexed: %d lemma(s)", fraseID, len(entradas))
+	return nil
+}
+
+// Rebuild reindexa todas as frases do usuário; retorna quantas foram processadas
+func (s *Service) Rebuild(ctx context.Context, userID int) (int, error) {
+	ids, err := s.repo.ListFraseIDs(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list phrases: %w", err)
+	}
+
+	for i, id := range ids {
+		if err := s.IndexPhrase(ctx, id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// List retorna as palavras do usuário e o resumo por status
+func (s *Service) List(ctx context.Context, userID int, params vocabulary.ListParams) (*vocabulary.ListResult, error) {
+	switch params.Status {
+	case "", vocabulary.StatusKnown, vocabulary.StatusLearning, vocabulary.StatusNew:
+	default:
+		return nil, fmt.Errorf("%w %q", vocabulary.ErrInvalidStatus, params.Status)
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultLimit
+	}
+	params.Limit = min(params.Limit, maxLimit)
+	params.Offset = max(params.Offset, 0)
+
+	palavras, err := s.repo.List(ctx, userID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vocabulary: %w", err)
+	}
+
+	resumo, err := s.repo.Resumo(ctx, userID, params.Idioma)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize vocabulary: %w", err)
+	}
+
+	return &vocabulary.ListResult{Palavras: palavras, Resumo: *resumo}, nil
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"extension-backend/internal/vocabulary"
+)
+
+func TestLemmatize(t *testing.T) {
+	cases := []struct {
+		idioma, word, want string
+	}{
+		{"en", "Running", "run"},
+		{"en", "stopped", "stop"},
+		{"en", "making", "make"},
+		{"en", "walked", "walk"},
+		{"en", "cities", "city"},
+		{"en", "boxes", "box"},
+		{"en", "went", "go"},
+		{"en", "children", "child"},
+		{"en", "glass", "glass"},
+		{"en-US", "cats", "cat"},
+		{"pt", "corações", "coração"},
+		{"pt", "animais", "animal"},
+		{"pt", "flores", "flor"},
+		{"pt", "homens", "homem"},
+		{"pt-BR", "casas", "casa"},
+		{"es", "luces", "luz"},
+		{"es", "canciones", "canción"},
+		{"es", "ciudades", "ciudad"},
+		{"es", "tiene", "tener"},
+		{"fr", "Maisons", "maisons"},
+	}
+
+	for _, c := range cases {
+		if got := vocabulary.Lemmatize(c.word, c.idioma); got != c.want {
+			t.Errorf("Lemmatize(%q, %q) = %q, want %q", c.word, c.idioma, got, c.want)
+		}
+	}
+}
+
+func TestTokenize_SplitsContractionsAndSkipsNumbers(t *testing.T) {
+	tokens := vocabulary.Tokenize("I don't have 3 cats, it’s fine!", "en")
+
+	var lemas []string
+	for _, tk := range tokens {
+		lemas = append(lemas, tk.Lema)
+	}
+	want := []string{"i", "do", "not", "have", "cat", "it", "fine"}
+	if !reflect.DeepEqual(lemas, want) {
+		t.Errorf("got %v, want %v", lemas, want)
+	}
+}
+
+func TestExtract_UsesFatiasForExpressionsAndTranslations(t *testing.T) {
+	fatias := map[string]string{
+		"break the ice": "quebrar o gelo",
+		"party":         "festa",
+	}
+
+	entradas := vocabulary.Extract("Jokes break the ice at every party, parties need jokes.", "en", fatias)
+
+	byLema := make(map[string]vocabulary.Entrada)
+	for _, e := range entradas {
+		byLema[e.Lema] = e
+	}
+
+	expr, ok := byLema["break the ice"]
+	if !ok || !expr.Expressao || expr.Traducao != "quebrar o gelo" {
+		t.Errorf("expected expression entry, got %+v", expr)
+	}
+	if p := byLema["party"]; p.Ocorrencias != 2 || p.Traducao != "festa" {
+		t.Errorf("expected party twice with translation, got %+v", p)
+	}
+	if j := byLema["joke"]; j.Ocorrencias != 2 || j.Forma != "jokes" {
+		t.Errorf("expected joke twice, got %+v", j)
+	}
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"extension-backend/internal/vocabulary"
+	"extension-backend/internal/vocabulary/repository"
+	"extension-backend/internal/vocabulary/service"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func setupService(t *testing.T) (pgxmock.PgxPoolIface, *service.Service) {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	return mock, service.New(repository.New(mock))
+}
+
+func TestIndexPhrase_RelinksAndRecountsFrequency(t *testing.T) {
+	mock, svc := setupService(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM frases f LEFT JOIN frase_detalhes fd").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "usuario_id", "conteudo", "idioma_origem", "capturado_em", "fatias_traducoes"},
+		).AddRow(10, 7, "Cats, cats!", "en", now, []byte(`{"cats": "gatos"}`)))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM frases WHERE id = \\$1 FOR UPDATE").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(10))
+
+	// Reindexação: a frase já estava ligada ao lema 3 (que deixa de aparecer)
+	mock.ExpectQuery("DELETE FROM vocabulario_frases WHERE frase_id").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"vocabulario_id"}).AddRow(3))
+
+	batch := mock.ExpectBatch()
+	batch.ExpectQuery("INSERT INTO vocabulario (.+) ON CONFLICT (.+) INSERT INTO vocabulario_frases").
+		WithArgs(7, "en", "cat", false, "gatos", now, 10, "cats", 2).
+		WillReturnRows(pgxmock.NewRows([]string{"vocabulario_id"}).AddRow(5))
+
+	mock.ExpectExec("UPDATE vocabulario v SET frequencia").
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectCommit()
+
+	if err := svc.IndexPhrase(context.Background(), 10); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestIndexPhrase_BatchFailureRollsBack(t *testing.T) {
+	mock, svc := setupService(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM frases f LEFT JOIN frase_detalhes fd").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows(
+			[]string{"id", "usuario_id", "conteudo", "idioma_origem", "capturado_em", "fatias_traducoes"},
+		).AddRow(10, 7, "Cats", "en", now, nil))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM frases WHERE id = \\$1 FOR UPDATE").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(10))
+	mock.ExpectQuery("DELETE FROM vocabulario_frases WHERE frase_id").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"vocabulario_id"}).AddRow(3))
+	batch := mock.ExpectBatch()
+	batch.ExpectQuery("INSERT INTO vocabulario (.+) INSERT INTO vocabulario_frases").
+		WithArgs(7, "en", "cat", false, "", now, 10, "cats", 1).
+		WillReturnError(errors.New("unique violation"))
+	// A frase não fica desligada: o DELETE é desfeito junto
+	mock.ExpectRollback()
+
+	if err := svc.IndexPhrase(context.Background(), 10); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListVocabulary_Success(t *testing.T) {
+	mock, svc := setupService(t)
+	defer mock.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM vocabulario v (.+) anki_progresso ap (.+) LIMIT \\$5 OFFSET \\$6").
+		WithArgs(7, vocabulary.IntervaloMaduro, "en", "known", 200, 0).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "lema", "idioma", "expressao", "traducao", "frequencia", "frases", "status", "primeira_vez_em", "ultima_vez_em",
+		}).AddRow(5, "cat", "en", false, "gato", 4, 2, "known", now, now))
+	mock.ExpectQuery("SELECT COUNT(.+) FROM (.+) vocabulario v").
+		WithArgs(7, vocabulary.IntervaloMaduro, "en").
+		WillReturnRows(pgxmock.NewRows([]string{"total", "known", "learning", "new"}).AddRow(10, 1, 3, 6))
+
+	result, err := svc.List(context.Background(), 7, vocabulary.ListParams{Idioma: "en", Status: "known", Limit: 1000})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Palavras) != 1 || result.Palavras[0].Status != vocabulary.StatusKnown {
+		t.Errorf("unexpected palavras: %+v", result.Palavras)
+	}
+	if result.Resumo.Total != 10 || result.Resumo.Learning != 3 {
+		t.Errorf("unexpected resumo: %+v", result.Resumo)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListVocabulary_InvalidStatus(t *testing.T) {
+	mock, svc := setupService(t)
+	defer mock.Close()
+
+	_, err := svc.List(context.Background(), 7, vocabulary.ListParams{Status: "mastered"})
+	if !errors.Is(err, vocabulary.ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+}
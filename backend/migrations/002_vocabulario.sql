@@ -0,0 +1,25 @@
+-- Vocabulário por usuário: lemas extraídos das frases capturadas
+CREATE TABLE IF NOT EXISTS vocabulario (
+    id SERIAL PRIMARY KEY,
+    usuario_id integer NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    idioma varchar(10) NOT NULL,
+    lema varchar(100) NOT NULL,
+    expressao boolean NOT NULL DEFAULT false,
+    traducao text,
+    frequencia integer NOT NULL DEFAULT 0,
+    primeira_vez_em timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    ultima_vez_em timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT vocabulario_usuario_idioma_lema_key UNIQUE (usuario_id, idioma, lema)
+);
+
+-- Ligação lema <-> frase (forma encontrada e quantas vezes aparece na frase)
+CREATE TABLE IF NOT EXISTS vocabulario_frases (
+    vocabulario_id integer NOT NULL REFERENCES vocabulario(id) ON DELETE CASCADE,
+    frase_id integer NOT NULL REFERENCES frases(id) ON DELETE CASCADE,
+    forma varchar(100) NOT NULL,
+    ocorrencias integer NOT NULL DEFAULT 1,
+    PRIMARY KEY (vocabulario_id, frase_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vocabulario_usuario_freq ON vocabulario(usuario_id, frequencia DESC);
+CREATE INDEX IF NOT EXISTS idx_vocabulario_frases_frase ON vocabulario_frases(frase_id);
@@ -0,0 +1,13 @@
+-- Nível CEFR (A1–C2) estimado para frases e exercícios; NULL = ainda não classificado
+ALTER TABLE frases ADD COLUMN IF NOT EXISTS nivel_cefr varchar(2);
+ALTER TABLE exercicios ADD COLUMN IF NOT EXISTS nivel_cefr varchar(2);
+
+ALTER TABLE frases DROP CONSTRAINT IF EXISTS frases_nivel_cefr_check;
+ALTER TABLE frases ADD CONSTRAINT frases_nivel_cefr_check
+    CHECK (nivel_cefr IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2'));
+ALTER TABLE exercicios DROP CONSTRAINT IF EXISTS exercicios_nivel_cefr_check;
+ALTER TABLE exercicios ADD CONSTRAINT exercicios_nivel_cefr_check
+    CHECK (nivel_cefr IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2'));
+
+CREATE INDEX IF NOT EXISTS idx_frases_usuario_cefr ON frases(usuario_id, nivel_cefr);
+CREATE INDEX IF NOT EXISTS idx_exercicios_catalogo_cefr ON exercicios(catalogo_id, nivel_cefr);
@@ -0,0 +1,2 @@
+-- Dia (data local do banco) da última atividade contada na ofensiva
+ALTER TABLE usuario_estatisticas ADD COLUMN IF NOT EXISTS ofensiva_data date;
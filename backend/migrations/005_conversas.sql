@@ -0,0 +1,25 @@
+-- Conversas por voz (WebSocket /conversation): uma linha por sessão com turnos
+CREATE TABLE IF NOT EXISTS conversas (
+    id SERIAL PRIMARY KEY,
+    usuario_id integer NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    stt_provider varchar(100),
+    llm_provider varchar(100),
+    tts_provider varchar(100),
+    iniciada_em timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    encerrada_em timestamp without time zone
+);
+
+-- Turnos da conversa na ordem em que aconteceram (papel: user | model)
+CREATE TABLE IF NOT EXISTS conversa_turnos (
+    id SERIAL PRIMARY KEY,
+    conversa_id integer NOT NULL REFERENCES conversas(id) ON DELETE CASCADE,
+    papel varchar(10) NOT NULL,
+    conteudo text NOT NULL,
+    iniciado_em timestamp without time zone NOT NULL,
+    duracao_ms integer NOT NULL DEFAULT 0,
+    ttfa_ms integer,
+    interrompido boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversas_usuario ON conversas(usuario_id, iniciada_em DESC);
+CREATE INDEX IF NOT EXISTS idx_conversa_turnos_conversa ON conversa_turnos(conversa_id, id);
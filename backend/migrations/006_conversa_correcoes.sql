@@ -0,0 +1,13 @@
+-- Correções gramaticais das falas do usuário (mensagem "correction" do WebSocket /conversation).
+-- frase_id: frase criada a partir da correção (POST /conversations/corrections/{id}/phrase)
+CREATE TABLE IF NOT EXISTS conversa_correcoes (
+    id SERIAL PRIMARY KEY,
+    turno_id integer NOT NULL REFERENCES conversa_turnos(id) ON DELETE CASCADE,
+    original text NOT NULL,
+    corrigido text NOT NULL,
+    explicacao text NOT NULL DEFAULT '',
+    frase_id integer REFERENCES frases(id) ON DELETE SET NULL,
+    criado_em timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversa_correcoes_turno ON conversa_correcoes(turno_id, id);
@@ -0,0 +1,64 @@
+-- Cenários de role-play para o WebSocket /conversation (escolhidos no setup pelo scenario_id).
+-- persona: papel do tutor (vai para o prompt do LLM); palavras_alvo: vocabulário que o aluno deve usar;
+-- idioma: código das palavras-alvo (NULL = qualquer); voice_id: voz do TTS (NULL = padrão)
+CREATE TABLE IF NOT EXISTS cenarios (
+    id SERIAL PRIMARY KEY,
+    slug varchar(50) NOT NULL UNIQUE,
+    titulo varchar(100) NOT NULL,
+    descricao text NOT NULL DEFAULT '',
+    persona text NOT NULL,
+    palavras_alvo text[] NOT NULL DEFAULT '{}',
+    nivel_cefr varchar(2) CHECK (nivel_cefr IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2')),
+    idioma varchar(10),
+    voice_id varchar(64),
+    ativo boolean NOT NULL DEFAULT true,
+    criado_em timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cenário e idioma da conversa (NULL = conversa livre)
+ALTER TABLE conversas ADD COLUMN IF NOT EXISTS cenario_id integer REFERENCES cenarios(id) ON DELETE SET NULL;
+ALTER TABLE conversas ADD COLUMN IF NOT EXISTS idioma varchar(10);
+
+-- Palavras-alvo do cenário, no turno do usuário em que foram usadas pela primeira vez
+CREATE TABLE IF NOT EXISTS conversa_palavras_alvo (
+    id SERIAL PRIMARY KEY,
+    turno_id integer NOT NULL REFERENCES conversa_turnos(id) ON DELETE CASCADE,
+    palavra varchar(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversa_palavras_alvo_turno ON conversa_palavras_alvo(turno_id);
+
+INSERT INTO cenarios (slug, titulo, descricao, persona, palavras_alvo, nivel_cefr, idioma, voice_id) VALUES
+(
+    'restaurant-order',
+    'Ordering at a restaurant',
+    'Peça uma refeição, pergunte sobre o cardápio e peça a conta.',
+    'You are a friendly waiter at a busy restaurant in London. Greet the customer, take their order, suggest the dish of the day and bring the bill at the end.',
+    ARRAY['menu', 'order', 'starter', 'main course', 'dessert', 'bill', 'tip', 'recommend'],
+    'A2', 'en', '21m00Tcm4TlvDq8ikWAM'
+),
+(
+    'hotel-check-in',
+    'Checking in at a hotel',
+    'Faça o check-in, confirme a reserva e pergunte sobre o café da manhã.',
+    'You are a receptionist at a hotel front desk. Help the guest check in, confirm their reservation details and answer questions about the room and breakfast.',
+    ARRAY['reservation', 'check in', 'passport', 'room key', 'breakfast', 'checkout', 'elevator'],
+    'A2', 'en', 'EXAVITQu4vr4xnSDxMaL'
+),
+(
+    'job-interview',
+    'Job interview',
+    'Responda perguntas de uma entrevista de emprego e fale da sua experiência.',
+    'You are a hiring manager interviewing the learner for a job at a tech company. Ask one question at a time about their experience, strengths, weaknesses and salary expectations.',
+    ARRAY['experience', 'skills', 'strength', 'weakness', 'team', 'deadline', 'salary', 'responsible'],
+    'B1', 'en', 'pNInz6obpgDQGcFmaJgB'
+),
+(
+    'doctor-appointment',
+    'At the doctor',
+    'Descreva seus sintomas e entenda as orientações do médico.',
+    'You are a calm general practitioner. Ask the patient about their symptoms, how long they have had them, and give simple advice and a prescription.',
+    ARRAY['symptom', 'headache', 'fever', 'prescription', 'appointment', 'allergic', 'pharmacy'],
+    'B1', 'en', 'TxGEqnHWrfWFTfGW9XzX'
+)
+ON CONFLICT (slug) DO NOTHING;
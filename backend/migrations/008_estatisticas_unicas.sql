@@ -0,0 +1,31 @@
+-- Uma linha de estatísticas por usuário (e por usuário + tipo), para a pontuação usar
+-- INSERT ... ON CONFLICT. Linhas duplicadas por inserts concorrentes são somadas antes.
+WITH removidas AS (
+    DELETE FROM usuario_estatisticas e
+    USING (SELECT usuario_id FROM usuario_estatisticas GROUP BY usuario_id HAVING COUNT(*) > 1) d
+    WHERE e.usuario_id = d.usuario_id
+    RETURNING e.*
+)
+INSERT INTO usuario_estatisticas (usuario_id, total_exercicios, total_acertos, total_erros, total_prof, nivel,
+    ofensiva_dias, melhor_ofensiva, ultima_atividade, atualizado_em, ofensiva_data)
+SELECT usuario_id, SUM(total_exercicios), SUM(total_acertos), SUM(total_erros), SUM(total_prof), MAX(nivel),
+    MAX(ofensiva_dias), MAX(melhor_ofensiva), MAX(ultima_atividade), MAX(atualizado_em), MAX(ofensiva_data)
+FROM removidas
+GROUP BY usuario_id;
+
+WITH removidas AS (
+    DELETE FROM usuario_estatisticas_tipo t
+    USING (
+        SELECT usuario_id, tipo_exercicio_id FROM usuario_estatisticas_tipo
+        GROUP BY usuario_id, tipo_exercicio_id HAVING COUNT(*) > 1
+    ) d
+    WHERE t.usuario_id = d.usuario_id AND t.tipo_exercicio_id = d.tipo_exercicio_id
+    RETURNING t.*
+)
+INSERT INTO usuario_estatisticas_tipo (usuario_id, tipo_exercicio_id, total_respostas, total_acertos, total_erros, proef_ganho)
+SELECT usuario_id, tipo_exercicio_id, SUM(total_respostas), SUM(total_acertos), SUM(total_erros), SUM(proef_ganho)
+FROM removidas
+GROUP BY usuario_id, tipo_exercicio_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_usuario_estatisticas_usuario ON usuario_estatisticas(usuario_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_usuario_estatisticas_tipo_usuario_tipo ON usuario_estatisticas_tipo(usuario_id, tipo_exercicio_id);